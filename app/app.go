@@ -1,17 +1,27 @@
 package app
 
 import (
+	"errors"
+	"fmt"
 	"os"
-	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/google/go-github/v69/github"
+	"github.com/google/uuid"
 	"golang.org/x/term"
 
+	"github.com/alex-laycalvert/ghtui/config"
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/ui/pages/actionspage"
 	"github.com/alex-laycalvert/ghtui/ui/pages/issuespage"
+	"github.com/alex-laycalvert/ghtui/ui/pages/myworkpage"
+	"github.com/alex-laycalvert/ghtui/ui/pages/notificationspage"
+	"github.com/alex-laycalvert/ghtui/ui/pages/prpage"
 	"github.com/alex-laycalvert/ghtui/ui/pages/repopage"
+	"github.com/alex-laycalvert/ghtui/ui/theme"
 	"github.com/alex-laycalvert/ghtui/utils"
 )
 
@@ -19,32 +29,109 @@ type App struct {
 	model appModel
 }
 
-func New(token string, repoName string) (*App, error) {
-	client := github.NewClient(nil).WithAuthToken(token)
+// Titles of the tabs of the pages, by their name in the `pages` config option.
+var pageTitles = map[string]string{
+	"repo":          "Repo",
+	"issues":        "Issues",
+	"pulls":         "Pull Requests",
+	"actions":       "Actions",
+	"notifications": "Notifications",
+	"mywork":        "My Work",
+}
 
-	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+// Pages that are not about a single repository. The app has one of each,
+// shown whichever repository is open.
+var crossRepoPages = map[string]bool{
+	"notifications": true,
+	"mywork":        true,
+}
+
+// newPage constructs the page listed as name in the `pages` config option.
+func newPage(name string, client *gh.Client, repo gh.Repo, width int, height int) (utils.Component, error) {
+	id := name + "_" + uuid.NewString()
+	switch name {
+	case "repo":
+		return repopage.NewRepoPage(id, client.Repos, client.Releases, repo, width, height), nil
+	case "issues":
+		return issuespage.NewIssuesPage(id, client.Issues, repo, width, height), nil
+	case "pulls":
+		return prpage.NewPullRequestsPage(id, client.Pulls, repo, width, height), nil
+	case "actions":
+		return actionspage.NewActionsPage(id, client.Actions, repo, width, height), nil
+	case "notifications":
+		return notificationspage.NewNotificationsPage(id, client.Notifications, width, height), nil
+	case "mywork":
+		return myworkpage.NewMyWorkPage(id, client.Search, width, height), nil
+	default:
+		return nil, fmt.Errorf("unknown page %q in config", name)
+	}
+}
+
+// New creates the app for repoName. When repoName is empty the repository is
+// inferred from the git checkout in the current directory.
+func New(cfg *config.Config, repoName string) (*App, error) {
+	host, repoName, err := resolveRepo(cfg, repoName)
 	if err != nil {
 		return nil, err
 	}
 
-	pageWidth := width - 6
-	pageHeight := height - 6
+	if err := theme.Set(cfg.Theme); err != nil {
+		return nil, err
+	}
 
-	repo := repopage.NewRepoPage("Repo", client, repoName, pageWidth, pageHeight)
-	issues := issuespage.NewIssuesPage("Issues", client, repoName, pageWidth, pageHeight)
+	repo, err := gh.ParseRepo(repoName)
+	if err != nil {
+		return nil, err
+	}
+	for _, pinned := range cfg.Pinned {
+		if _, _, err := splitRepoName(cfg, pinned); err != nil {
+			return nil, fmt.Errorf("pinned repository: %w", err)
+		}
+	}
+	client, err := newClient(cfg, host)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Pages) == 0 {
+		return nil, errors.New("no pages configured")
+	}
+	w, err := newWorkspace(cfg, host, client, repo, width-6, height-6)
+	if err != nil {
+		return nil, err
+	}
+	crossRepo := make([]utils.Component, 0)
+	crossRepoIDs := make(map[string]string)
+	for _, name := range cfg.Pages {
+		if !crossRepoPages[name] {
+			continue
+		}
+		page, err := newPage(name, client, repo, width-6, height-6)
+		if err != nil {
+			return nil, err
+		}
+		crossRepo = append(crossRepo, page)
+		crossRepoIDs[name] = page.ID()
+	}
 
 	model := appModel{
-		client: client,
-		repo:   repoName,
-		width:  width,
-		height: height,
+		cfg:     cfg,
+		clients: map[string]*gh.Client{host: client},
+		width:   width,
+		height:  height,
+		styles:  newAppStyles(),
+
+		crossRepoPages:   utils.NewComponentGroup(crossRepo...),
+		crossRepoPageIDs: crossRepoIDs,
+		crossRepoShown:   crossRepoPages[cfg.Pages[0]],
 
-		pageGroup: utils.NewComponentGroup(
-			repo,
-			issues,
-		),
-		issuesPage: issues.ID(),
-		repoPage:   repo.ID(),
+		workspaces: []workspace{w},
+		switcher:   components.NewPickerComponent(width-6, height-6),
 	}
 
 	return &App{model: model}, nil
@@ -61,115 +148,267 @@ type appModel struct {
 	width  int
 	height int
 
-	client *github.Client
-	repo   string
+	cfg *config.Config
+	// Clients by host, created when a repository of the host is first opened.
+	clients map[string]*gh.Client
+
+	styles appStyles
+
+	// The pages about no single repository, with their IDs by name, and
+	// whether one of them is shown rather than a page of the repository.
+	crossRepoPages   utils.ComponentGroup
+	crossRepoPageIDs map[string]string
+	crossRepoShown   bool
+
+	// The repositories opened, in the order they were opened, and the one shown.
+	workspaces []workspace
+	active     int
 
-	pageGroup  utils.ComponentGroup
-	issuesPage string
-	repoPage   string
+	switcher  components.PickerModel
+	switching bool
+	// Repositories of the user on userReposHost, offered by the switcher once listed.
+	userRepos     []*github.Repository
+	userReposHost string
 
-	updates int
+	notice string
+
+	rateLimits gh.RateLimitStatus
 }
 
 func (model appModel) Init() tea.Cmd {
+	// Groups focus their first page from the start, which is the first tab's
+	// in the group shown.
+	first := model.tabs()[0].id
 	return tea.Batch(
-		model.pageGroup.FocusOn(model.repoPage),
-		model.pageGroup.Init(),
+		model.shownPages().Update(first, utils.FocusMsg{ID: first}),
+		model.crossRepoPages.Init(),
+		model.workspaces[model.active].pageGroup.Init(),
+		model.rateLimitTick(),
 	)
 }
 
+// A tab of the page bar.
+type tab struct {
+	id    string
+	title string
+}
+
+// tabs returns the tabs of the pages shown for the current repository, in the
+// order of the `pages` config option.
+func (model appModel) tabs() []tab {
+	current := model.workspaces[model.active]
+	tabs := make([]tab, 0, len(model.cfg.Pages))
+	for _, name := range model.cfg.Pages {
+		id := current.pages[name]
+		if crossRepoPages[name] {
+			id = model.crossRepoPageIDs[name]
+		}
+		tabs = append(tabs, tab{id: id, title: pageTitles[name]})
+	}
+	return tabs
+}
+
+// shownPages returns the group of the page shown: the cross-repository pages
+// or those of the current repository.
+func (model *appModel) shownPages() *utils.ComponentGroup {
+	if model.crossRepoShown {
+		return &model.crossRepoPages
+	}
+	return &model.workspaces[model.active].pageGroup
+}
+
+// pagesOf returns the group of the page with id, nil when it is of none.
+func (model *appModel) pagesOf(id string) *utils.ComponentGroup {
+	if model.crossRepoPages.GetComponent(id) != nil {
+		return &model.crossRepoPages
+	}
+	for i := range model.workspaces {
+		if model.workspaces[i].owns(id) {
+			return &model.workspaces[i].pageGroup
+		}
+	}
+	return nil
+}
+
+// showPage shows the page with id, of the cross-repository pages or those of
+// the current repository, blurring the page it replaces.
+func (model *appModel) showPage(id string) tea.Cmd {
+	current := &model.workspaces[model.active]
+	crossRepo := model.crossRepoPages.GetComponent(id) != nil
+	if crossRepo == model.crossRepoShown {
+		return model.shownPages().FocusOn(id)
+	}
+	blur := model.shownPages().BlurFocused()
+	model.crossRepoShown = crossRepo
+	if crossRepo {
+		return tea.Batch(blur, model.crossRepoPages.FocusOn(id))
+	}
+	return tea.Batch(blur, current.pageGroup.FocusOn(id))
+}
+
+// cycleTabs shows the page of the tab offset tabs from the current one.
+func (model *appModel) cycleTabs(offset int) tea.Cmd {
+	tabs := model.tabs()
+	shown := model.shownPages().GetFocusedComponentName()
+	for i, t := range tabs {
+		if t.id == shown {
+			return model.showPage(tabs[(i+offset+len(tabs))%len(tabs)].id)
+		}
+	}
+	return nil
+}
+
 func (model appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	current := &model.workspaces[model.active]
 	switch msg := msg.(type) {
-	case utils.FocusMsg:
-		return model, model.pageGroup.UpdateAll(msg)
-	case utils.BlurMsg:
-		return model, model.pageGroup.UpdateAll(msg)
-	case utils.UpdateSizeMsg:
-		return model, model.pageGroup.UpdateAll(msg)
+	case utils.PageMsg:
+		if pages := model.pagesOf(msg.ID); pages != nil {
+			return model, pages.Update(msg.ID, msg)
+		}
+		return model, nil
+	case utils.ErrorMsg:
+		if pages := model.pagesOf(msg.Source); pages != nil {
+			return model, pages.Update(msg.Source, msg)
+		}
+		return model, nil
+	case utils.OpenItemMsg:
+		page, open := current.openItem(msg)
+		if page == "" {
+			return model, nil
+		}
+		return model, tea.Sequence(model.showPage(page), open)
+	case rateLimitTickMsg:
+		model.rateLimits = msg.status
+		return model, model.rateLimitTick()
+	case userReposMsg:
+		return model, model.handleUserRepos(msg)
+	case components.PickerSelectMsg:
+		model.switching = false
+		return model, model.switchTo(msg.Value)
+	case components.PickerCancelMsg:
+		model.switching = false
+		return model, nil
 	case tea.WindowSizeMsg:
 		model.width = msg.Width
 		model.height = msg.Height
 		pageWidth := msg.Width - 6
 		pageHeight := msg.Height - 6
-		return model, tea.Batch(
-			model.pageGroup.Update(model.issuesPage, utils.UpdateSizeMsg{
-				ID:     model.issuesPage,
-				Width:  pageWidth,
-				Height: pageHeight,
-			}),
-			model.pageGroup.Update(model.repoPage, utils.UpdateSizeMsg{
-				ID:     model.repoPage,
-				Width:  pageWidth,
-				Height: pageHeight,
-			}),
-		)
+		cmds := make([]tea.Cmd, 0)
+		groups := []*utils.ComponentGroup{&model.crossRepoPages}
+		for i := range model.workspaces {
+			groups = append(groups, &model.workspaces[i].pageGroup)
+		}
+		for _, group := range groups {
+			for _, page := range group.GetComponents() {
+				cmds = append(cmds, group.Update(page.ID(), utils.UpdateSizeMsg{
+					ID:     page.ID(),
+					Width:  pageWidth,
+					Height: pageHeight,
+				}))
+			}
+		}
+		switcher, cmd := model.switcher.Update(utils.UpdateSizeMsg{
+			ID:     model.switcher.ID(),
+			Width:  pageWidth,
+			Height: pageHeight,
+		})
+		model.switcher = switcher.(components.PickerModel)
+		return model, tea.Batch(append(cmds, cmd)...)
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
-		case "ctrl+c":
+		model.notice = ""
+		keypress := msg.String()
+		if keypress == "ctrl+c" {
 			return model, tea.Quit
+		}
+		if model.switching {
+			switcher, cmd := model.switcher.Update(msg)
+			model.switcher = switcher.(components.PickerModel)
+			return model, cmd
+		}
+		if model.shownPages().CapturesInput() {
+			return model, model.shownPages().UpdateFocused(msg)
+		}
+
+		switch keypress {
 		case "tab":
-			return model, model.pageGroup.FocusNext()
+			return model, model.cycleTabs(1)
 		case "shift+tab":
-			return model, model.pageGroup.FocusPrevious()
+			return model, model.cycleTabs(-1)
+		case "ctrl+p":
+			return model, model.openSwitcher()
 		default:
-			return model, model.pageGroup.UpdateFocused(msg)
+			return model, model.shownPages().UpdateFocused(msg)
 		}
+	case utils.FocusMsg, utils.BlurMsg, utils.UpdateSizeMsg:
+		return model, model.shownPages().UpdateAll(msg)
 	default:
-		return model, model.pageGroup.UpdateFocused(msg)
+		return model, model.shownPages().UpdateFocused(msg)
 	}
 }
 
-func tabBorderStyle() lipgloss.Style {
-	border := lipgloss.RoundedBorder()
-	style := lipgloss.NewStyle().
-		Border(border).
-		BorderForeground(highlightColor).
-		Padding(0, 1)
-	return style
+type appStyles struct {
+	doc         lipgloss.Style
+	inactiveTab lipgloss.Style
+	activeTab   lipgloss.Style
+	window      lipgloss.Style
 }
 
-var (
-	docStyle         = lipgloss.NewStyle().Padding(1, 2, 1, 2)
-	highlightColor   = lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"}
-	inactiveTabStyle = tabBorderStyle()
-	activeTabStyle   = inactiveTabStyle.
-				Bold(true)
-	windowStyle = lipgloss.NewStyle().
+// newAppStyles builds the app's styles from the current theme.
+func newAppStyles() appStyles {
+	highlightColor := theme.Current.Highlight
+	inactiveTab := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlightColor).
+		Padding(0, 1)
+	return appStyles{
+		doc:         lipgloss.NewStyle().Padding(1, 2, 1, 2),
+		inactiveTab: inactiveTab,
+		activeTab:   inactiveTab.Bold(true),
+		window: lipgloss.NewStyle().
 			BorderForeground(highlightColor).
-			Border(lipgloss.RoundedBorder())
-)
+			Border(lipgloss.RoundedBorder()),
+	}
+}
 
 func (model appModel) View() string {
 	doc := strings.Builder{}
 
 	var renderedTabs []string
 
-	pages := model.pageGroup.GetComponents()
-	currentPage := model.pageGroup.GetFocusedComponent()
-	for _, t := range pages {
+	current := model.workspaces[model.active]
+	currentPage := model.shownPages().GetFocusedComponent()
+	for _, t := range model.tabs() {
 		var style lipgloss.Style
-		isActive := t.ID() == currentPage.ID()
+		isActive := t.id == currentPage.ID()
 		if isActive {
-			style = activeTabStyle
+			style = model.styles.activeTab
 		} else {
-			style = inactiveTabStyle
+			style = model.styles.inactiveTab
 		}
-		renderedTabs = append(renderedTabs, style.Render(string(t.ID())))
+		renderedTabs = append(renderedTabs, style.Render(t.title))
 	}
 
 	header := lipgloss.NewStyle().
 		MarginLeft(1).
 		Padding(1).
-		Render(model.repo)
+		Render(current.name())
+	status := renderRateLimits(model.rateLimits)
+	if model.notice != "" {
+		status = lowRateLimitStyle.Render(model.notice)
+	}
 	row := lipgloss.JoinHorizontal(
 		lipgloss.Center,
 		lipgloss.JoinHorizontal(lipgloss.Top, renderedTabs...),
 		header,
-		"  "+strconv.Itoa(model.updates),
+		"  "+status,
 	)
 	doc.WriteString(row + "\n")
-	doc.WriteString(windowStyle.Render(currentPage.View()))
-	return docStyle.
+	if model.switching {
+		doc.WriteString(model.styles.window.Render(model.switcher.View()))
+	} else {
+		doc.WriteString(model.styles.window.Render(currentPage.View()))
+	}
+	return model.styles.doc.
 		Width(model.width).
 		Height(model.height).
 		Render(doc.String())
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+)
+
+// How often the rate limit indicator is refreshed.
+const rateLimitRefreshInterval = time.Second
+
+// Resources whose remaining quota drops below this fraction are highlighted.
+const lowRateLimitFraction = 0.1
+
+var (
+	rateLimitStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241"))
+	lowRateLimitStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("9"))
+)
+
+type rateLimitTickMsg struct {
+	status gh.RateLimitStatus
+}
+
+func (model appModel) rateLimitTick() tea.Cmd {
+	limits := model.workspaces[model.active].client.RateLimits
+	return tea.Tick(rateLimitRefreshInterval, func(time.Time) tea.Msg {
+		return rateLimitTickMsg{status: limits.Status()}
+	})
+}
+
+// renderRateLimits renders the remaining quota per resource and, when requests
+// are held back, how long until they resume.
+func renderRateLimits(status gh.RateLimitStatus) string {
+	now := time.Now()
+	if !status.WaitingUntil.IsZero() {
+		return lowRateLimitStyle.Render(fmt.Sprintf(
+			"rate limited, resuming in %s",
+			status.WaitingUntil.Sub(now).Round(time.Second),
+		))
+	}
+	if !status.SecondaryReset.IsZero() {
+		return lowRateLimitStyle.Render(fmt.Sprintf(
+			"secondary rate limit, resets in %s",
+			status.SecondaryReset.Sub(now).Round(time.Second),
+		))
+	}
+
+	resources := make([]string, 0, len(status.Rates))
+	for resource := range status.Rates {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	parts := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		rate := status.Rates[resource]
+		part := fmt.Sprintf("%s %d/%d", resource, rate.Remaining, rate.Limit)
+		if rate.Limit > 0 && float64(rate.Remaining) < float64(rate.Limit)*lowRateLimitFraction {
+			part += " resets " + rate.Reset.Local().Format(time.Kitchen)
+			parts = append(parts, lowRateLimitStyle.Render(part))
+		} else {
+			parts = append(parts, rateLimitStyle.Render(part))
+		}
+	}
+	return strings.Join(parts, rateLimitStyle.Render(" · "))
+}
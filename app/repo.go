@@ -0,0 +1,134 @@
+package app
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/config"
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/gitrepo"
+)
+
+// resolveRepo decides which repository to open, and on which host: the one given
+// explicitly, the GitHub repository of the git checkout in the current directory,
+// or the config's default repository, in that order.
+func resolveRepo(cfg *config.Config, repoName string) (host string, repo string, err error) {
+	if repoName != "" {
+		return splitRepoName(cfg, repoName)
+	}
+
+	remote, err := inferRemote(cfg, os.Stdin, os.Stdout)
+	if err == nil {
+		return remote.Host, remote.FullName(), nil
+	}
+	if !errors.Is(err, gitrepo.ErrNotARepository) && !errors.Is(err, errNoGitHubRemote) {
+		return "", "", err
+	}
+
+	if cfg.Repo == "" {
+		return "", "", fmt.Errorf("%w, pass owner/name or set repo in the config file", err)
+	}
+	return splitRepoName(cfg, cfg.Repo)
+}
+
+// splitRepoName parses `owner/name` or `host/owner/name`, using the config's
+// host when none is given.
+func splitRepoName(cfg *config.Config, repoName string) (host string, repo string, err error) {
+	parts := strings.Split(repoName, "/")
+	switch {
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return cfg.Host, repoName, nil
+	case len(parts) == 3 && parts[0] != "" && parts[1] != "" && parts[2] != "":
+		return strings.ToLower(parts[0]), parts[1] + "/" + parts[2], nil
+	default:
+		return "", "", fmt.Errorf("invalid repository %q, expected owner/name or host/owner/name", repoName)
+	}
+}
+
+var errNoGitHubRemote = errors.New("the current git repository has no GitHub remote")
+
+// inferRemote finds the GitHub remote of the git checkout in the current
+// directory, asking the user to choose when the config's remote preference
+// does not settle it.
+func inferRemote(cfg *config.Config, in io.Reader, out io.Writer) (gitrepo.Remote, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return gitrepo.Remote{}, err
+	}
+	gitDir, err := gitrepo.FindGitDir(wd)
+	if err != nil {
+		return gitrepo.Remote{}, err
+	}
+	remotes, err := gitrepo.Remotes(gitDir, cfg.IsKnownHost)
+	if err != nil {
+		return gitrepo.Remote{}, err
+	}
+	if len(remotes) == 0 {
+		return gitrepo.Remote{}, errNoGitHubRemote
+	}
+
+	if remote, ok := gitrepo.PickRemote(remotes, cfg.Remotes); ok {
+		return remote, nil
+	}
+	return promptRemote(remotes, in, out)
+}
+
+// promptRemote lists remotes and reads the user's choice from in. Runs before the
+// TUI starts, so plain line-based IO is used.
+func promptRemote(remotes []gitrepo.Remote, in io.Reader, out io.Writer) (gitrepo.Remote, error) {
+	fmt.Fprintln(out, "Multiple GitHub remotes found, which repository should be opened?")
+	for i, remote := range remotes {
+		fmt.Fprintf(out, "  %d) %s/%s (%s)\n", i+1, remote.Host, remote.FullName(), remote.Name)
+	}
+
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Fprintf(out, "Choice [1-%d]: ", len(remotes))
+		line, err := reader.ReadString('\n')
+		choice, convErr := strconv.Atoi(strings.TrimSpace(line))
+		if convErr == nil && choice >= 1 && choice <= len(remotes) {
+			return remotes[choice-1], nil
+		}
+		if err != nil {
+			return gitrepo.Remote{}, fmt.Errorf("no remote chosen: %w", err)
+		}
+	}
+}
+
+// newClient creates a GitHub client for host, using the enterprise API URLs for
+// hosts other than github.com.
+func newClient(cfg *config.Config, host string) (*gh.Client, error) {
+	token, _, err := cfg.ResolveToken(host)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := gh.NewRateLimits()
+	transport := limits.Transport(http.DefaultTransport)
+	if !cfg.DisableCache {
+		cacheDir, err := config.CacheDir()
+		if err != nil {
+			return nil, err
+		}
+		transport = gh.CacheTransport(transport, filepath.Join(cacheDir, "http"))
+	}
+	httpClient := &http.Client{Transport: transport}
+	client := github.NewClient(httpClient).WithAuthToken(token)
+	if config.IsEnterprise(host) {
+		settings := cfg.HostSettings(host)
+		client, err = client.WithEnterpriseURLs(settings.APIURL, settings.UploadURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return gh.NewRESTClient(client, limits), nil
+}
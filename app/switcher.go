@@ -0,0 +1,162 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+)
+
+type userReposMsg struct {
+	host  string
+	repos []*github.Repository
+	err   error
+}
+
+// openSwitcher shows the repo switcher, listing the user's repositories on
+// the host of the repository shown the first time.
+func (model *appModel) openSwitcher() tea.Cmd {
+	model.switching = true
+	switcher, _ := model.switcher.Update(components.PickerResetMsg{
+		Title: "Switch repository",
+		Items: model.switcherItems(),
+	})
+	model.switcher = switcher.(components.PickerModel)
+
+	current := model.workspaces[model.active]
+	if model.userReposHost == current.host {
+		return nil
+	}
+	host, client := current.host, current.client
+	return func() tea.Msg {
+		repos, err := client.Repos.ListUserRepos(context.Background())
+		return userReposMsg{host: host, repos: repos, err: err}
+	}
+}
+
+func (model *appModel) handleUserRepos(msg userReposMsg) tea.Cmd {
+	if msg.err != nil {
+		model.notice = "Failed to list your repositories: " + msg.err.Error()
+		return nil
+	}
+	model.userRepos = msg.repos
+	model.userReposHost = msg.host
+	if !model.switching {
+		return nil
+	}
+	switcher, cmd := model.switcher.Update(components.PickerSetItemsMsg{Items: model.switcherItems()})
+	model.switcher = switcher.(components.PickerModel)
+	return cmd
+}
+
+// switcherItems returns the repositories offered by the switcher: those
+// open, those pinned in the config, then the user's.
+func (model appModel) switcherItems() []components.PickerItem {
+	items := make([]components.PickerItem, 0)
+	seen := make(map[string]bool)
+	add := func(name string, detail string) {
+		key := strings.ToLower(name)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		items = append(items, components.PickerItem{Value: name, Detail: detail})
+	}
+
+	for i, w := range model.workspaces {
+		detail := "open"
+		if i == model.active {
+			detail = "shown"
+		}
+		add(w.name(), detail)
+	}
+	for _, pinned := range model.cfg.Pinned {
+		host, repoName, err := splitRepoName(model.cfg, pinned)
+		repo, parseErr := gh.ParseRepo(repoName)
+		if err != nil || parseErr != nil {
+			continue
+		}
+		add(repoDisplayName(host, repo), "pinned")
+	}
+	for _, repo := range model.userRepos {
+		name := repoDisplayName(model.userReposHost, gh.Repo{
+			Owner: repo.GetOwner().GetLogin(),
+			Name:  repo.GetName(),
+		})
+		add(name, strings.Join(strings.Fields(repo.GetDescription()), " "))
+	}
+	return items
+}
+
+// switchTo shows the repository named name, opening it unless it already is.
+func (model *appModel) switchTo(name string) tea.Cmd {
+	host, repoName, err := splitRepoName(model.cfg, name)
+	if err != nil {
+		model.notice = err.Error()
+		return nil
+	}
+	repo, err := gh.ParseRepo(repoName)
+	if err != nil {
+		model.notice = err.Error()
+		return nil
+	}
+	for i, w := range model.workspaces {
+		if w.is(host, repo) {
+			return model.activate(i)
+		}
+	}
+
+	client, ok := model.clients[host]
+	if !ok {
+		client, err = newClient(model.cfg, host)
+		if err != nil {
+			model.notice = "Failed to open " + name + ": " + err.Error()
+			return nil
+		}
+		model.clients[host] = client
+	}
+	w, err := newWorkspace(model.cfg, host, client, repo, model.width-6, model.height-6)
+	if err != nil {
+		model.notice = "Failed to open " + name + ": " + err.Error()
+		return nil
+	}
+	model.workspaces = append(model.workspaces, w)
+	// The pages of the new workspace load from Init, which focusing its first
+	// page as well would only repeat.
+	suspend := model.suspend()
+	model.active = len(model.workspaces) - 1
+	return tea.Batch(suspend, w.pageGroup.Init())
+}
+
+// activate shows the workspace at index i. Unless a cross-repository page is
+// shown, the page of the workspace hidden is suspended, which stops its
+// polling but keeps what it shows, and the page last shown of the other is
+// focused.
+func (model *appModel) activate(i int) tea.Cmd {
+	if i == model.active {
+		return nil
+	}
+	suspend := model.suspend()
+	model.active = i
+	if model.crossRepoShown {
+		return nil
+	}
+	shown := &model.workspaces[i]
+	return tea.Batch(
+		suspend,
+		shown.pageGroup.FocusOn(shown.pageGroup.GetFocusedComponentName()),
+	)
+}
+
+// suspend suspends the page shown of the current workspace, unless a
+// cross-repository page is shown instead.
+func (model *appModel) suspend() tea.Cmd {
+	if model.crossRepoShown {
+		return nil
+	}
+	return model.workspaces[model.active].pageGroup.SuspendFocused()
+}
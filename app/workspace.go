@@ -0,0 +1,80 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/alex-laycalvert/ghtui/config"
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+// A repository open in the app. Each has its own pages, so switching between
+// repositories keeps what each of them shows.
+type workspace struct {
+	host   string
+	repo   gh.Repo
+	client *gh.Client
+
+	pageGroup utils.ComponentGroup
+	// IDs of the pages by their name in the `pages` config option.
+	pages map[string]string
+}
+
+// newWorkspace creates the pages listed in the `pages` config option that show
+// a single repository for repo.
+func newWorkspace(cfg *config.Config, host string, client *gh.Client, repo gh.Repo, width int, height int) (workspace, error) {
+	w := workspace{host: host, repo: repo, client: client, pages: map[string]string{}}
+	pages := make([]utils.Component, 0, len(cfg.Pages))
+	for _, name := range cfg.Pages {
+		if crossRepoPages[name] {
+			continue
+		}
+		page, err := newPage(name, client, repo, width, height)
+		if err != nil {
+			return workspace{}, err
+		}
+		pages = append(pages, page)
+		w.pages[name] = page.ID()
+	}
+	w.pageGroup = utils.NewComponentGroup(pages...)
+	return w, nil
+}
+
+// repoDisplayName returns the name of a repository as shown in the header and
+// the repo switcher, with its host when it is not github.com.
+func repoDisplayName(host string, repo gh.Repo) string {
+	if config.IsEnterprise(host) {
+		return host + "/" + repo.String()
+	}
+	return repo.String()
+}
+
+func (w workspace) name() string {
+	return repoDisplayName(w.host, w.repo)
+}
+
+// is reports whether the workspace shows repo on host.
+func (w workspace) is(host string, repo gh.Repo) bool {
+	return w.host == host && strings.EqualFold(w.repo.String(), repo.String())
+}
+
+// owns reports whether the page with id is one of the workspace's.
+func (w workspace) owns(id string) bool {
+	return w.pageGroup.GetComponent(id) != nil
+}
+
+// openItem shows an issue or pull request, of any repository, in the page
+// listing them.
+func (w *workspace) openItem(msg utils.OpenItemMsg) (string, tea.Cmd) {
+	page := w.pages["issues"]
+	if msg.PullRequest {
+		page = w.pages["pulls"]
+	}
+	if page == "" {
+		return "", nil
+	}
+	msg.ID = page
+	return page, w.pageGroup.Update(page, msg)
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// The pages shown when the config file does not specify `pages`.
+var DefaultPages = []string{"repo", "issues", "pulls", "actions", "notifications", "mywork"}
+
+// The git remotes tried, in order, when inferring the repository from the current checkout.
+var DefaultRemotes = []string{"upstream", "github", "origin"}
+
+// Config holds the user's settings, read from `$XDG_CONFIG_HOME/ghtui/config.toml`.
+//
+// Every field is optional; the zero value is a usable configuration.
+type Config struct {
+	// Repository to open when none is given on the command line and the current
+	// directory is not a clone of a GitHub repository, as `owner/name`.
+	Repo string `toml:"repo"`
+
+	// Preference order of git remote names when inferring the repository from the
+	// current checkout.
+	Remotes []string `toml:"remotes"`
+
+	// Name of the color theme, see `theme.Names`.
+	Theme string `toml:"theme"`
+
+	// Order in which the page tabs are shown. Unknown names are rejected by the app.
+	Pages []string `toml:"pages"`
+
+	// Repositories offered first by the repo switcher, as `owner/name` or
+	// `host/owner/name`.
+	Pinned []string `toml:"pinned"`
+
+	// Host used for repositories given without one and outside of a git checkout.
+	// Defaults to github.com.
+	Host string `toml:"host"`
+
+	Auth AuthConfig `toml:"auth"`
+
+	// GitHub Enterprise Server hosts by hostname.
+	Hosts map[string]HostConfig `toml:"hosts"`
+
+	// Turns off the on-disk HTTP cache in `CacheDir`.
+	DisableCache bool `toml:"disable_cache"`
+
+	// Path the config was loaded from, empty if no file was found.
+	Path string `toml:"-"`
+
+	tokenFileOverride string
+}
+
+// AuthConfig describes where the GitHub token comes from when it is not
+// provided through the `GH_TOKEN` or `GITHUB_TOKEN` environment variables.
+type AuthConfig struct {
+	// File containing the token. `~` is expanded to the home directory.
+	TokenFile string `toml:"token_file"`
+
+	// Command whose standard output is the token, e.g. `gh auth token`.
+	// Run through `sh -c`.
+	TokenCommand string `toml:"token_command"`
+}
+
+// Dir returns the directory holding ghtui's configuration.
+func Dir() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "ghtui"), nil
+}
+
+// CacheDir returns the directory holding ghtui's cached API responses.
+func CacheDir() (string, error) {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(dir, "ghtui"), nil
+}
+
+// DefaultPath returns the path of the config file used when none is given explicitly.
+func DefaultPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// Load reads the config file at path. If path is empty, `DefaultPath` is used
+// and a missing file results in the default config rather than an error.
+func Load(path string) (*Config, error) {
+	explicit := path != ""
+	if !explicit {
+		var err error
+		path, err = DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cfg := &Config{}
+	_, err := toml.DecodeFile(path, cfg)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		cfg.applyDefaults()
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	if err := cfg.normalizeHosts(); err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+	cfg.Path = path
+	cfg.applyDefaults()
+	return cfg, nil
+}
+
+// normalizeHosts lowercases the hostnames of the `hosts` tables, as hostnames
+// are looked up lowercased.
+func (cfg *Config) normalizeHosts() error {
+	hosts := make(map[string]HostConfig, len(cfg.Hosts))
+	for name, host := range cfg.Hosts {
+		lower := strings.ToLower(name)
+		if _, ok := hosts[lower]; ok {
+			return fmt.Errorf("host %q is configured more than once", lower)
+		}
+		hosts[lower] = host
+	}
+	cfg.Hosts = hosts
+	return nil
+}
+
+func (cfg *Config) applyDefaults() {
+	if cfg.Host == "" {
+		cfg.Host = DefaultHost
+	}
+	if cfg.Theme == "" {
+		cfg.Theme = "auto"
+	}
+	if len(cfg.Pages) == 0 {
+		cfg.Pages = DefaultPages
+	}
+	if len(cfg.Remotes) == 0 {
+		cfg.Remotes = DefaultRemotes
+	}
+}
+
+// expandHome replaces a leading `~` in path with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && (len(path) < 2 || path[:2] != "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
@@ -0,0 +1,77 @@
+package config
+
+import "strings"
+
+// The host used when a repository is given without one.
+const DefaultHost = "github.com"
+
+// HostConfig holds the settings for a GitHub Enterprise Server host, configured
+// in a `[hosts."<hostname>"]` table.
+type HostConfig struct {
+	// Base URL of the REST API, e.g. `https://github.example.com/api/v3/`.
+	// Defaults to `https://<hostname>/api/v3/`.
+	APIURL string `toml:"api_url"`
+
+	// Base URL for uploads, e.g. `https://github.example.com/api/uploads/`.
+	// Defaults to `https://<hostname>/api/uploads/`.
+	UploadURL string `toml:"upload_url"`
+
+	// Where the token for this host comes from, used when neither
+	// `GH_ENTERPRISE_TOKEN` nor `GITHUB_ENTERPRISE_TOKEN` is set.
+	Auth AuthConfig `toml:"auth"`
+}
+
+// The environment variables checked for a GitHub Enterprise Server token, in order.
+var enterpriseTokenEnvVars = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+
+// IsEnterprise reports whether host is a GitHub Enterprise Server rather than github.com.
+func IsEnterprise(host string) bool {
+	host = strings.ToLower(host)
+	return host != DefaultHost && host != "www.github.com" && host != "ssh.github.com"
+}
+
+// IsKnownHost reports whether host is github.com or a configured enterprise host.
+func (cfg *Config) IsKnownHost(host string) bool {
+	if !IsEnterprise(host) {
+		return true
+	}
+	_, ok := cfg.Hosts[strings.ToLower(host)]
+	return ok
+}
+
+// HostSettings returns the settings for host, filling in the default API URLs for
+// enterprise hosts that do not set them.
+func (cfg *Config) HostSettings(host string) HostConfig {
+	host = strings.ToLower(host)
+	hostCfg := cfg.Hosts[host]
+	if !IsEnterprise(host) {
+		return hostCfg
+	}
+	if hostCfg.APIURL == "" {
+		hostCfg.APIURL = "https://" + host + "/api/v3/"
+	}
+	if hostCfg.UploadURL == "" {
+		hostCfg.UploadURL = "https://" + host + "/api/uploads/"
+	}
+	return hostCfg
+}
+
+// ResolveToken finds the token for host. For github.com the `GH_TOKEN` and
+// `GITHUB_TOKEN` environment variables and the top level auth settings are used,
+// for enterprise hosts the enterprise environment variables and the host's own
+// auth settings.
+func (cfg *Config) ResolveToken(host string) (token string, source string, err error) {
+	if cfg.tokenFileOverride != "" {
+		return AuthConfig{TokenFile: cfg.tokenFileOverride}.resolveToken(nil)
+	}
+	if !IsEnterprise(host) {
+		return cfg.Auth.resolveToken(tokenEnvVars)
+	}
+	return cfg.HostSettings(host).Auth.resolveToken(enterpriseTokenEnvVars)
+}
+
+// OverrideTokenFile makes every host read its token from path, ignoring the
+// environment and the auth settings in the config file.
+func (cfg *Config) OverrideTokenFile(path string) {
+	cfg.tokenFileOverride = path
+}
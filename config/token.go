@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// The environment variables checked for a token, in order.
+var tokenEnvVars = []string{"GH_TOKEN", "GITHUB_TOKEN"}
+
+var ErrNoToken = errors.New("no GitHub token found")
+
+// resolveToken finds the GitHub token, checking in order the envVars, the
+// configured token file and the configured token command.
+//
+// The returned source describes where the token came from, for display purposes.
+func (auth AuthConfig) resolveToken(envVars []string) (token string, source string, err error) {
+	for _, name := range envVars {
+		if token := strings.TrimSpace(os.Getenv(name)); token != "" {
+			return token, "$" + name, nil
+		}
+	}
+
+	if auth.TokenFile != "" {
+		path := expandHome(auth.TokenFile)
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return "", "", fmt.Errorf("reading token file: %w", err)
+		}
+		if token := strings.TrimSpace(string(contents)); token != "" {
+			return token, path, nil
+		}
+		return "", "", fmt.Errorf("token file %s is empty", path)
+	}
+
+	if auth.TokenCommand != "" {
+		cmd := exec.Command("sh", "-c", auth.TokenCommand)
+		cmd.Stderr = os.Stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return "", "", fmt.Errorf("running token command %q: %w", auth.TokenCommand, err)
+		}
+		if token := strings.TrimSpace(string(output)); token != "" {
+			return token, auth.TokenCommand, nil
+		}
+		return "", "", fmt.Errorf("token command %q printed nothing", auth.TokenCommand)
+	}
+
+	return "", "", fmt.Errorf(
+		"%w: set %s, or configure token_file or token_command",
+		ErrNoToken,
+		strings.Join(envVars, " or "),
+	)
+}
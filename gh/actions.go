@@ -0,0 +1,219 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// RunSearch describes a page of a repository's workflow runs.
+type RunSearch struct {
+	// Only list the runs of this workflow, 0 for all of them.
+	WorkflowID int64
+	Branch     string
+	// Event that triggered the runs, e.g. "push" or "pull_request".
+	Event string
+	// "queued", "in_progress", "completed" or a conclusion like "failure".
+	Status  string
+	Page    int
+	PerPage int
+}
+
+// How a workflow can be run manually from a ref.
+type WorkflowDispatch struct {
+	Ref string
+	// Whether the workflow has the `workflow_dispatch` trigger.
+	Dispatchable bool
+	Inputs       []WorkflowInput
+}
+
+// A page of workflow runs, most recent first.
+type RunPage struct {
+	Runs  []*github.WorkflowRun
+	Total int
+}
+
+type ActionsService interface {
+	// ListWorkflows returns the workflows of the repository.
+	ListWorkflows(ctx context.Context, repo Repo) ([]*github.Workflow, error)
+
+	// ListRuns returns a page of the repository's workflow runs.
+	ListRuns(ctx context.Context, repo Repo, search RunSearch) (RunPage, error)
+
+	// ListJobs returns the jobs of the latest attempt of a run, with their steps.
+	ListJobs(ctx context.Context, repo Repo, runID int64) ([]*github.WorkflowJob, error)
+
+	// GetJobLog downloads the log of a job. GitHub only has it once the job
+	// finished.
+	GetJobLog(ctx context.Context, repo Repo, jobID int64) (string, error)
+
+	// RerunRun runs all the jobs of a run again.
+	RerunRun(ctx context.Context, repo Repo, runID int64) error
+
+	// RerunFailedJobs runs the failed jobs of a run, and those depending on them, again.
+	RerunFailedJobs(ctx context.Context, repo Repo, runID int64) error
+
+	// CancelRun cancels a run that did not finish.
+	CancelRun(ctx context.Context, repo Repo, runID int64) error
+
+	// GetDispatch returns whether the workflow can be run manually through
+	// `workflow_dispatch` from ref, and the inputs it asks for. An empty ref
+	// is the default branch.
+	GetDispatch(ctx context.Context, repo Repo, workflow *github.Workflow, ref string) (WorkflowDispatch, error)
+
+	// DispatchWorkflow runs a workflow on ref with inputs.
+	DispatchWorkflow(ctx context.Context, repo Repo, workflowID int64, ref string, inputs map[string]any) error
+}
+
+type restActionsService struct {
+	client *github.Client
+	limits *RateLimits
+}
+
+func (s restActionsService) ListWorkflows(ctx context.Context, repo Repo) ([]*github.Workflow, error) {
+	return withRateLimitRetry(ctx, s.limits, func() ([]*github.Workflow, error) {
+		workflows, _, err := s.client.Actions.ListWorkflows(ctx, repo.Owner, repo.Name, &github.ListOptions{
+			PerPage: 100,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return workflows.Workflows, nil
+	})
+}
+
+func (s restActionsService) ListRuns(ctx context.Context, repo Repo, search RunSearch) (RunPage, error) {
+	options := &github.ListWorkflowRunsOptions{
+		Branch: search.Branch,
+		Event:  search.Event,
+		Status: search.Status,
+		ListOptions: github.ListOptions{
+			Page:    search.Page,
+			PerPage: search.PerPage,
+		},
+	}
+	return withRateLimitRetry(ctx, s.limits, func() (RunPage, error) {
+		var runs *github.WorkflowRuns
+		var err error
+		if search.WorkflowID != 0 {
+			runs, _, err = s.client.Actions.ListWorkflowRunsByID(ctx, repo.Owner, repo.Name, search.WorkflowID, options)
+		} else {
+			runs, _, err = s.client.Actions.ListRepositoryWorkflowRuns(ctx, repo.Owner, repo.Name, options)
+		}
+		if err != nil {
+			return RunPage{}, err
+		}
+		return RunPage{Runs: runs.WorkflowRuns, Total: runs.GetTotalCount()}, nil
+	})
+}
+
+func (s restActionsService) ListJobs(ctx context.Context, repo Repo, runID int64) ([]*github.WorkflowJob, error) {
+	return withRateLimitRetry(ctx, s.limits, func() ([]*github.WorkflowJob, error) {
+		jobs, _, err := s.client.Actions.ListWorkflowJobs(ctx, repo.Owner, repo.Name, runID, &github.ListWorkflowJobsOptions{
+			Filter:      "latest",
+			ListOptions: github.ListOptions{PerPage: 100},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return jobs.Jobs, nil
+	})
+}
+
+func (s restActionsService) GetJobLog(ctx context.Context, repo Repo, jobID int64) (string, error) {
+	location, err := withRateLimitRetry(ctx, s.limits, func() (string, error) {
+		url, _, err := s.client.Actions.GetWorkflowJobLogs(ctx, repo.Owner, repo.Name, jobID, 2)
+		if err != nil {
+			return "", err
+		}
+		return url.String(), nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// The log is served from a pre-signed URL that must not get the token.
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading the log: %s", response.Status)
+	}
+	log, err := io.ReadAll(response.Body)
+	return string(log), err
+}
+
+func (s restActionsService) RerunRun(ctx context.Context, repo Repo, runID int64) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (*github.Response, error) {
+		return s.client.Actions.RerunWorkflowByID(ctx, repo.Owner, repo.Name, runID)
+	})
+	return err
+}
+
+func (s restActionsService) RerunFailedJobs(ctx context.Context, repo Repo, runID int64) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (*github.Response, error) {
+		return s.client.Actions.RerunFailedJobsByID(ctx, repo.Owner, repo.Name, runID)
+	})
+	return err
+}
+
+func (s restActionsService) CancelRun(ctx context.Context, repo Repo, runID int64) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (*github.Response, error) {
+		response, err := s.client.Actions.CancelWorkflowRunByID(ctx, repo.Owner, repo.Name, runID)
+		// GitHub accepts the cancellation and performs it in the background.
+		var accepted *github.AcceptedError
+		if errors.As(err, &accepted) {
+			return response, nil
+		}
+		return response, err
+	})
+	return err
+}
+
+func (s restActionsService) GetDispatch(ctx context.Context, repo Repo, workflow *github.Workflow, ref string) (WorkflowDispatch, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (WorkflowDispatch, error) {
+		if ref == "" {
+			repository, _, err := s.client.Repositories.Get(ctx, repo.Owner, repo.Name)
+			if err != nil {
+				return WorkflowDispatch{}, err
+			}
+			ref = repository.GetDefaultBranch()
+		}
+
+		file, _, _, err := s.client.Repositories.GetContents(ctx, repo.Owner, repo.Name, workflow.GetPath(), &github.RepositoryContentGetOptions{
+			Ref: ref,
+		})
+		if err != nil {
+			return WorkflowDispatch{}, err
+		}
+		if file == nil {
+			return WorkflowDispatch{}, fmt.Errorf("%s is not a file", workflow.GetPath())
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			return WorkflowDispatch{}, err
+		}
+		inputs, dispatchable := parseDispatchInputs(content)
+		return WorkflowDispatch{Ref: ref, Dispatchable: dispatchable, Inputs: inputs}, nil
+	})
+}
+
+func (s restActionsService) DispatchWorkflow(ctx context.Context, repo Repo, workflowID int64, ref string, inputs map[string]any) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (*github.Response, error) {
+		return s.client.Actions.CreateWorkflowDispatchEventByID(ctx, repo.Owner, repo.Name, workflowID, github.CreateWorkflowDispatchEventRequest{
+			Ref:    ref,
+			Inputs: inputs,
+		})
+	})
+	return err
+}
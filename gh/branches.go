@@ -0,0 +1,303 @@
+package gh
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// At most this many pages of 100 branches, and of 100 tags, are listed.
+const maxRefPages = 5
+
+// A branch with how it compares to the default branch.
+type Branch struct {
+	Name      string
+	IsDefault bool
+	Protected bool
+	// Commits the branch has that the default branch does not, and the other
+	// way around.
+	Ahead  int
+	Behind int
+	// The last commit of the branch.
+	Commit RefCommit
+	// Number and title of the open pull request from the branch, 0 without one.
+	PullRequest      int
+	PullRequestTitle string
+}
+
+// A tag and the commit it points to.
+type Tag struct {
+	Name   string
+	Commit RefCommit
+}
+
+// The commit a branch or tag points to.
+type RefCommit struct {
+	SHA     string
+	Subject string
+	// Login of the author, or their name without a GitHub account.
+	Author string
+	Date   time.Time
+}
+
+// The branches and tags of a repository, the most recently committed to first.
+type BranchList struct {
+	DefaultBranch string
+	Branches      []Branch
+	Tags          []Tag
+}
+
+// Refs returns the names of the list's branches and tags.
+func (l BranchList) Refs() RepoRefs {
+	refs := RepoRefs{DefaultBranch: l.DefaultBranch}
+	for _, branch := range l.Branches {
+		refs.Branches = append(refs.Branches, branch.Name)
+	}
+	for _, tag := range l.Tags {
+		refs.Tags = append(refs.Tags, tag.Name)
+	}
+	return refs
+}
+
+const defaultBranchQuery = `
+query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    defaultBranchRef { name }
+  }
+}`
+
+// Branches are listed with GraphQL, which compares each of them with the
+// default branch and has their pull requests and protection in one request.
+const listBranchesQuery = `
+query($owner: String!, $name: String!, $defaultBranch: String!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    refs(refPrefix: "refs/heads/", first: 100, after: $after, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) {
+      pageInfo { endCursor hasNextPage }
+      nodes {
+        name
+        branchProtectionRule { pattern }
+        compare(headRef: $defaultBranch) { aheadBy behindBy }
+        associatedPullRequests(first: 1, states: [OPEN]) { nodes { number title } }
+        target { ...commit }
+      }
+    }
+  }
+}
+
+fragment commit on Commit {
+  oid
+  messageHeadline
+  committedDate
+  author { name user { login } }
+}`
+
+const listTagsQuery = `
+query($owner: String!, $name: String!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    refs(refPrefix: "refs/tags/", first: 100, after: $after, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) {
+      pageInfo { endCursor hasNextPage }
+      nodes {
+        name
+        target {
+          ...commit
+          ... on Tag { target { ...commit } }
+        }
+      }
+    }
+  }
+}
+
+fragment commit on Commit {
+  oid
+  messageHeadline
+  committedDate
+  author { name user { login } }
+}`
+
+type refCommitData struct {
+	OID             string
+	MessageHeadline string
+	CommittedDate   time.Time
+	Author          struct {
+		Name string
+		User *struct {
+			Login string
+		}
+	}
+}
+
+func (c refCommitData) refCommit() RefCommit {
+	commit := RefCommit{SHA: c.OID, Subject: c.MessageHeadline, Author: c.Author.Name, Date: c.CommittedDate}
+	if c.Author.User != nil {
+		commit.Author = c.Author.User.Login
+	}
+	return commit
+}
+
+type refsPageInfo struct {
+	EndCursor   string
+	HasNextPage bool
+}
+
+type defaultBranchData struct {
+	Repository struct {
+		DefaultBranchRef *struct {
+			Name string
+		}
+	}
+}
+
+type listBranchesData struct {
+	Repository struct {
+		Refs struct {
+			PageInfo refsPageInfo
+			Nodes    []struct {
+				Name                 string
+				BranchProtectionRule *struct {
+					Pattern string
+				}
+				Compare *struct {
+					AheadBy  int
+					BehindBy int
+				}
+				AssociatedPullRequests struct {
+					Nodes []struct {
+						Number int
+						Title  string
+					}
+				}
+				Target refCommitData
+			}
+		}
+	}
+}
+
+type listTagsData struct {
+	Repository struct {
+		Refs struct {
+			PageInfo refsPageInfo
+			Nodes    []struct {
+				Name   string
+				Target struct {
+					refCommitData
+					// Set for annotated tags.
+					Target *refCommitData
+				}
+			}
+		}
+	}
+}
+
+func (s restRepoService) ListBranches(ctx context.Context, repo Repo) (BranchList, error) {
+	variables := map[string]any{"owner": repo.Owner, "name": repo.Name}
+	list := BranchList{}
+
+	data, err := withRateLimitRetry(ctx, s.limits, func() (defaultBranchData, error) {
+		var data defaultBranchData
+		err := graphQL(ctx, s.client, defaultBranchQuery, variables, &data)
+		return data, err
+	})
+	if err != nil {
+		return BranchList{}, err
+	}
+	if data.Repository.DefaultBranchRef == nil {
+		// An empty repository has neither branches nor tags.
+		return list, nil
+	}
+	list.DefaultBranch = data.Repository.DefaultBranchRef.Name
+
+	branchVariables := map[string]any{"owner": repo.Owner, "name": repo.Name, "defaultBranch": list.DefaultBranch}
+	for page := 0; page < maxRefPages; page++ {
+		data, err := withRateLimitRetry(ctx, s.limits, func() (listBranchesData, error) {
+			var data listBranchesData
+			err := graphQL(ctx, s.client, listBranchesQuery, branchVariables, &data)
+			return data, err
+		})
+		if err != nil {
+			return BranchList{}, err
+		}
+		for _, node := range data.Repository.Refs.Nodes {
+			branch := Branch{
+				Name:      node.Name,
+				IsDefault: node.Name == list.DefaultBranch,
+				Protected: node.BranchProtectionRule != nil,
+				Commit:    node.Target.refCommit(),
+			}
+			// The comparison has the branch as its base and the default branch as its head.
+			if node.Compare != nil {
+				branch.Ahead = node.Compare.BehindBy
+				branch.Behind = node.Compare.AheadBy
+			}
+			if pulls := node.AssociatedPullRequests.Nodes; len(pulls) > 0 {
+				branch.PullRequest = pulls[0].Number
+				branch.PullRequestTitle = pulls[0].Title
+			}
+			list.Branches = append(list.Branches, branch)
+		}
+		if !data.Repository.Refs.PageInfo.HasNextPage {
+			break
+		}
+		branchVariables["after"] = data.Repository.Refs.PageInfo.EndCursor
+	}
+
+	for page := 0; page < maxRefPages; page++ {
+		data, err := withRateLimitRetry(ctx, s.limits, func() (listTagsData, error) {
+			var data listTagsData
+			err := graphQL(ctx, s.client, listTagsQuery, variables, &data)
+			return data, err
+		})
+		if err != nil {
+			return BranchList{}, err
+		}
+		for _, node := range data.Repository.Refs.Nodes {
+			commit := node.Target.refCommitData
+			if node.Target.Target != nil {
+				commit = *node.Target.Target
+			}
+			list.Tags = append(list.Tags, Tag{Name: node.Name, Commit: commit.refCommit()})
+		}
+		if !data.Repository.Refs.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = data.Repository.Refs.PageInfo.EndCursor
+	}
+	return list, nil
+}
+
+func (s restRepoService) CreateBranch(ctx context.Context, repo Repo, branch string, from string) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (struct{}, error) {
+		sha, _, err := s.client.Repositories.GetCommitSHA1(ctx, repo.Owner, repo.Name, from, "")
+		if err != nil {
+			return struct{}{}, err
+		}
+		_, _, err = s.client.Git.CreateRef(ctx, repo.Owner, repo.Name, &github.Reference{
+			Ref:    github.Ptr("refs/heads/" + branch),
+			Object: &github.GitObject{SHA: github.Ptr(sha)},
+		})
+		return struct{}{}, err
+	})
+	return err
+}
+
+func (s restRepoService) RenameBranch(ctx context.Context, repo Repo, branch string, newName string) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (struct{}, error) {
+		_, _, err := s.client.Repositories.RenameBranch(ctx, repo.Owner, repo.Name, branch, newName)
+		return struct{}{}, err
+	})
+	return err
+}
+
+func (s restRepoService) DeleteBranch(ctx context.Context, repo Repo, branch string) error {
+	return deleteBranch(ctx, s.client, s.limits, repo, branch)
+}
+
+// deleteBranch deletes a branch of repo, for both the branches list and pull
+// requests whose branch is no longer needed.
+func deleteBranch(ctx context.Context, client *github.Client, limits *RateLimits, repo Repo, branch string) error {
+	_, err := withRateLimitRetry(ctx, limits, func() (struct{}, error) {
+		_, err := client.Git.DeleteRef(ctx, repo.Owner, repo.Name, "heads/"+branch)
+		return struct{}{}, err
+	})
+	return err
+}
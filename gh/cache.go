@@ -0,0 +1,171 @@
+package gh
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotCached is returned for requests made with `WithCacheOnly` that have no cached response.
+var ErrNotCached = errors.New("response not cached")
+
+type cacheOnlyKey struct{}
+
+// WithCacheOnly returns a context whose requests are answered from the disk
+// cache without touching the network, failing with `ErrNotCached` on a miss.
+//
+// Pages use it to show the last known data instantly before revalidating.
+func WithCacheOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheOnlyKey{}, true)
+}
+
+func isCacheOnly(ctx context.Context) bool {
+	cacheOnly, _ := ctx.Value(cacheOnlyKey{}).(bool)
+	return cacheOnly
+}
+
+// CacheTransport returns a transport storing successful GET responses in dir and
+// revalidating them with `If-None-Match` and `If-Modified-Since`.
+//
+// A 304 Not Modified does not count against the rate limit, the cached response
+// is returned in its place marked with an `X-From-Cache` header.
+func CacheTransport(base http.RoundTripper, dir string) http.RoundTripper {
+	return cacheTransport{base: base, dir: dir}
+}
+
+type cacheTransport struct {
+	base http.RoundTripper
+	dir  string
+}
+
+func (t cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		if isCacheOnly(req.Context()) {
+			return nil, ErrNotCached
+		}
+		return t.base.RoundTrip(req)
+	}
+
+	path := t.path(req)
+	cached, cachedErr := readCachedResponse(path, req)
+
+	if isCacheOnly(req.Context()) {
+		if cachedErr != nil {
+			return nil, ErrNotCached
+		}
+		cached.Header.Set("X-From-Cache", "1")
+		return cached, nil
+	}
+
+	if cachedErr == nil {
+		// The request is shared with the caller, so headers are set on a copy.
+		req = req.Clone(req.Context())
+		if etag := cached.Header.Get("Etag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cachedErr == nil {
+		resp.Body.Close()
+		// Keep the fresh rate limit headers, everything else describes the cached body.
+		for name, values := range resp.Header {
+			if strings.HasPrefix(name, "X-Ratelimit-") {
+				cached.Header[name] = values
+			}
+		}
+		cached.Header.Set("X-From-Cache", "1")
+		return cached, nil
+	}
+
+	if resp.StatusCode == http.StatusOK && (resp.Header.Get("Etag") != "" || resp.Header.Get("Last-Modified") != "") {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		// Failing to cache is not worth failing the request over.
+		_ = writeCachedResponse(path, resp, body)
+	}
+
+	return resp, nil
+}
+
+// path returns the cache file for req, keyed by URL, the requested media type
+// and a hash of the credentials so users sharing a cache never see each other's data.
+func (t cacheTransport) path(req *http.Request) string {
+	identity := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	key := sha256.New()
+	key.Write([]byte(req.URL.String()))
+	key.Write([]byte{0})
+	key.Write([]byte(req.Header.Get("Accept")))
+	key.Write([]byte{0})
+	key.Write(identity[:])
+	name := hex.EncodeToString(key.Sum(nil))
+	return filepath.Join(t.dir, name[:2], name)
+}
+
+func readCachedResponse(path string, req *http.Request) (*http.Response, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	resp, err := http.ReadResponse(bufio.NewReader(file), req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+// writeCachedResponse stores resp with body in the HTTP wire format, writing to a
+// temporary file first so readers never see a partial entry.
+func writeCachedResponse(path string, resp *http.Response, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	stored := *resp
+	stored.Body = io.NopCloser(bytes.NewReader(body))
+	stored.ContentLength = int64(len(body))
+	stored.TransferEncoding = nil
+	stored.Header = resp.Header.Clone()
+	stored.Header.Del("Content-Encoding")
+	if err := stored.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,195 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// roundTripFunc answers requests with a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// A response the server gives.
+type serverResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+func TestCacheTransportRevalidates(t *testing.T) {
+	tests := []struct {
+		name string
+		// Responses to the first request and to the same request made again.
+		first  serverResponse
+		second serverResponse
+		// Authorization of the second request, the first one's if empty.
+		secondAuth string
+		cacheOnly  bool
+
+		// Headers the second request is sent with, empty for headers it must
+		// not have.
+		wantSent      map[string]string
+		wantBody      string
+		wantFromCache bool
+		wantErr       error
+		// Headers of the response to the second request.
+		wantHeaders map[string]string
+	}{
+		{
+			name:   "not modified since the etag",
+			first:  serverResponse{status: 200, headers: map[string]string{"Etag": `"v1"`, "X-Ratelimit-Remaining": "10"}, body: "cached"},
+			second: serverResponse{status: 304, headers: map[string]string{"Etag": `"v1"`, "X-Ratelimit-Remaining": "9"}},
+			wantSent: map[string]string{
+				"If-None-Match":     `"v1"`,
+				"If-Modified-Since": "",
+			},
+			wantBody:      "cached",
+			wantFromCache: true,
+			wantHeaders:   map[string]string{"X-Ratelimit-Remaining": "9", "Etag": `"v1"`},
+		},
+		{
+			name:   "not modified since the last modification",
+			first:  serverResponse{status: 200, headers: map[string]string{"Last-Modified": "Mon, 02 Jan 2006 15:04:05 GMT"}, body: "cached"},
+			second: serverResponse{status: 304},
+			wantSent: map[string]string{
+				"If-None-Match":     "",
+				"If-Modified-Since": "Mon, 02 Jan 2006 15:04:05 GMT",
+			},
+			wantBody:      "cached",
+			wantFromCache: true,
+		},
+		{
+			name:          "modified",
+			first:         serverResponse{status: 200, headers: map[string]string{"Etag": `"v1"`}, body: "old"},
+			second:        serverResponse{status: 200, headers: map[string]string{"Etag": `"v2"`}, body: "new"},
+			wantSent:      map[string]string{"If-None-Match": `"v1"`},
+			wantBody:      "new",
+			wantHeaders:   map[string]string{"Etag": `"v2"`, "X-From-Cache": ""},
+			wantFromCache: false,
+		},
+		{
+			name:     "response without validators",
+			first:    serverResponse{status: 200, body: "old"},
+			second:   serverResponse{status: 200, body: "new"},
+			wantSent: map[string]string{"If-None-Match": "", "If-Modified-Since": ""},
+			wantBody: "new",
+		},
+		{
+			name:     "error response",
+			first:    serverResponse{status: 404, headers: map[string]string{"Etag": `"v1"`}, body: "not found"},
+			second:   serverResponse{status: 200, body: "found"},
+			wantSent: map[string]string{"If-None-Match": ""},
+			wantBody: "found",
+		},
+		{
+			name:       "other credentials",
+			first:      serverResponse{status: 200, headers: map[string]string{"Etag": `"v1"`}, body: "mine"},
+			second:     serverResponse{status: 200, body: "theirs"},
+			secondAuth: "token other",
+			wantSent:   map[string]string{"If-None-Match": ""},
+			wantBody:   "theirs",
+		},
+		{
+			name:          "cache only hit",
+			first:         serverResponse{status: 200, headers: map[string]string{"Etag": `"v1"`}, body: "cached"},
+			cacheOnly:     true,
+			wantBody:      "cached",
+			wantFromCache: true,
+		},
+		{
+			name:      "cache only miss",
+			first:     serverResponse{status: 200, body: "uncached"},
+			cacheOnly: true,
+			wantErr:   ErrNotCached,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			responses := []serverResponse{test.first, test.second}
+			var sent []*http.Request
+			transport := CacheTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				sent = append(sent, req)
+				if len(sent) > len(responses) || (test.cacheOnly && len(sent) > 1) {
+					t.Fatalf("request %d sent, want %d", len(sent), len(responses))
+				}
+				response := responses[len(sent)-1]
+				header := http.Header{}
+				for name, value := range response.headers {
+					header.Set(name, value)
+				}
+				return &http.Response{
+					StatusCode: response.status,
+					Status:     http.StatusText(response.status),
+					Proto:      "HTTP/1.1",
+					ProtoMajor: 1,
+					ProtoMinor: 1,
+					Header:     header,
+					Body:       io.NopCloser(strings.NewReader(response.body)),
+					Request:    req,
+				}, nil
+			}), t.TempDir())
+
+			request := func(ctx context.Context, auth string) (*http.Response, error) {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/repos/owner/repo", nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Header.Set("Authorization", auth)
+				return transport.RoundTrip(req)
+			}
+
+			first, err := request(context.Background(), "token mine")
+			if err != nil {
+				t.Fatal(err)
+			}
+			io.ReadAll(first.Body)
+			first.Body.Close()
+
+			ctx := context.Background()
+			if test.cacheOnly {
+				ctx = WithCacheOnly(ctx)
+			}
+			auth := test.secondAuth
+			if auth == "" {
+				auth = "token mine"
+			}
+			second, err := request(ctx, auth)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("error %v, want %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer second.Body.Close()
+
+			if !test.cacheOnly {
+				for name, want := range test.wantSent {
+					if got := sent[1].Header.Get(name); got != want {
+						t.Errorf("sent %s %q, want %q", name, got, want)
+					}
+				}
+			}
+			if body, _ := io.ReadAll(second.Body); string(body) != test.wantBody {
+				t.Errorf("body %q, want %q", body, test.wantBody)
+			}
+			if fromCache := second.Header.Get("X-From-Cache") == "1"; fromCache != test.wantFromCache {
+				t.Errorf("from the cache %v, want %v", fromCache, test.wantFromCache)
+			}
+			if test.wantFromCache && second.StatusCode != http.StatusOK {
+				t.Errorf("status %d, want the cached 200", second.StatusCode)
+			}
+			for name, want := range test.wantHeaders {
+				if got := second.Header.Get(name); got != want {
+					t.Errorf("response %s %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
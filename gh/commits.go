@@ -0,0 +1,195 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// CommitSearch describes a page of a repository's history.
+type CommitSearch struct {
+	// Branch, tag or SHA the history leads up to, empty for the default branch.
+	Ref string
+	// Only list commits changing the file or directory at Path.
+	Path string
+	// Cursor of the page to list, empty for the first page.
+	After   string
+	PerPage int
+}
+
+// A commit as listed, with its signature and checks.
+type CommitSummary struct {
+	SHA     string
+	Subject string
+	// Login of the author, or their name without a GitHub account.
+	Author string
+	Date   time.Time
+	Signed bool
+	// Whether GitHub verified the signature.
+	Verified bool
+	// Combined state of the commit's statuses and check runs: "SUCCESS",
+	// "FAILURE", "ERROR", "PENDING", "EXPECTED" or empty without any.
+	CheckStatus string
+}
+
+// A page of commits.
+type CommitPage struct {
+	Commits []CommitSummary
+	// Cursor of the next page, empty if this is the last page.
+	EndCursor string
+}
+
+// The history is listed with GraphQL, which has the signature and checks of
+// each commit and can start from any ref, annotated tags included.
+const listCommitsQuery = `
+query($owner: String!, $name: String!, $expression: String!, $path: String, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    object(expression: $expression) {
+      ...history
+      ... on Tag { target { ...history } }
+    }
+  }
+}
+
+fragment history on Commit {
+  history(first: $first, after: $after, path: $path) {
+    pageInfo { endCursor hasNextPage }
+    nodes {
+      oid
+      messageHeadline
+      committedDate
+      author { name user { login } }
+      signature { isValid }
+      statusCheckRollup { state }
+    }
+  }
+}`
+
+type commitHistoryData struct {
+	History *struct {
+		PageInfo struct {
+			EndCursor   string
+			HasNextPage bool
+		}
+		Nodes []struct {
+			OID             string
+			MessageHeadline string
+			CommittedDate   time.Time
+			Author          struct {
+				Name string
+				User *struct {
+					Login string
+				}
+			}
+			Signature *struct {
+				IsValid bool
+			}
+			StatusCheckRollup *struct {
+				State string
+			}
+		}
+	}
+}
+
+type listCommitsData struct {
+	Repository struct {
+		Object *struct {
+			commitHistoryData
+			Target *commitHistoryData
+		}
+	}
+}
+
+func (s restRepoService) ListCommits(ctx context.Context, repo Repo, search CommitSearch) (CommitPage, error) {
+	expression := search.Ref
+	if expression == "" {
+		expression = "HEAD"
+	}
+	variables := map[string]any{
+		"owner":      repo.Owner,
+		"name":       repo.Name,
+		"expression": expression,
+		"first":      search.PerPage,
+	}
+	if search.Path != "" {
+		variables["path"] = search.Path
+	}
+	if search.After != "" {
+		variables["after"] = search.After
+	}
+
+	return withRateLimitRetry(ctx, s.limits, func() (CommitPage, error) {
+		var data listCommitsData
+		if err := graphQL(ctx, s.client, listCommitsQuery, variables, &data); err != nil {
+			return CommitPage{}, err
+		}
+		object := data.Repository.Object
+		if object == nil {
+			return CommitPage{}, fmt.Errorf("%s not found", expression)
+		}
+		history := object.History
+		if history == nil && object.Target != nil {
+			history = object.Target.History
+		}
+		if history == nil {
+			return CommitPage{}, fmt.Errorf("%s is not a commit", expression)
+		}
+
+		page := CommitPage{}
+		if history.PageInfo.HasNextPage {
+			page.EndCursor = history.PageInfo.EndCursor
+		}
+		for _, node := range history.Nodes {
+			commit := CommitSummary{
+				SHA:     node.OID,
+				Subject: node.MessageHeadline,
+				Author:  node.Author.Name,
+				Date:    node.CommittedDate,
+				Signed:  node.Signature != nil,
+			}
+			if node.Author.User != nil {
+				commit.Author = node.Author.User.Login
+			}
+			if node.Signature != nil {
+				commit.Verified = node.Signature.IsValid
+			}
+			if node.StatusCheckRollup != nil {
+				commit.CheckStatus = node.StatusCheckRollup.State
+			}
+			page.Commits = append(page.Commits, commit)
+		}
+		return page, nil
+	})
+}
+
+func (s restRepoService) GetCommit(ctx context.Context, repo Repo, sha string) (*github.RepositoryCommit, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (*github.RepositoryCommit, error) {
+		commit, _, err := s.client.Repositories.GetCommit(ctx, repo.Owner, repo.Name, sha, &github.ListOptions{
+			PerPage: 100,
+		})
+		return commit, err
+	})
+}
+
+func (s restRepoService) CompareCommits(ctx context.Context, repo Repo, base string, head string) (*github.CommitsComparison, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (*github.CommitsComparison, error) {
+		comparison, _, err := s.client.Repositories.CompareCommits(ctx, repo.Owner, repo.Name, base, head, &github.ListOptions{
+			PerPage: 100,
+		})
+		return comparison, err
+	})
+}
+
+func (s restRepoService) GetCommitDiff(ctx context.Context, repo Repo, base string, head string) (string, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (string, error) {
+		options := github.RawOptions{Type: github.Diff}
+		if base == "" {
+			diff, _, err := s.client.Repositories.GetCommitRaw(ctx, repo.Owner, repo.Name, head, options)
+			return diff, err
+		}
+		diff, _, err := s.client.Repositories.CompareCommitsRaw(ctx, repo.Owner, repo.Name, base, head, options)
+		return diff, err
+	})
+}
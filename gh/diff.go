@@ -0,0 +1,31 @@
+package gh
+
+import "strings"
+
+// FilePatch returns the hunks of the file at path in diff, a diff in the
+// unified format such as `GetDiff` returns, or an empty string if the diff
+// does not change its content.
+func FilePatch(diff string, path string) string {
+	lines := strings.Split(diff, "\n")
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "diff --git ") || !strings.HasSuffix(lines[i], " b/"+path) {
+			continue
+		}
+
+		hunks := make([]string, 0)
+		inHunks := false
+		for _, line := range lines[i+1:] {
+			if strings.HasPrefix(line, "diff --git ") {
+				break
+			}
+			if strings.HasPrefix(line, "@@") {
+				inHunks = true
+			}
+			if inHunks {
+				hunks = append(hunks, line)
+			}
+		}
+		return strings.TrimSuffix(strings.Join(hunks, "\n"), "\n")
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+// Package gh is the data-access layer between the UI and GitHub.
+//
+// Pages depend on the service interfaces defined here rather than on a
+// `*github.Client`, so the implementation behind them can be swapped.
+package gh
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// A GitHub repository, identified by owner and name.
+type Repo struct {
+	Owner string
+	Name  string
+}
+
+// ParseRepo parses a repository given as `owner/name`.
+func ParseRepo(fullName string) (Repo, error) {
+	owner, name, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return Repo{}, fmt.Errorf("invalid repository %q, expected owner/name", fullName)
+	}
+	return Repo{Owner: owner, Name: name}, nil
+}
+
+// String returns the repository as `owner/name`.
+func (r Repo) String() string {
+	return r.Owner + "/" + r.Name
+}
+
+// Client groups the services pages use to talk to GitHub.
+type Client struct {
+	Issues        IssueService
+	Pulls         PullRequestService
+	Repos         RepoService
+	Actions       ActionsService
+	Notifications NotificationService
+	Releases      ReleaseService
+	Search        SearchService
+
+	// Rate limit state of the client's requests.
+	RateLimits *RateLimits
+}
+
+// NewRESTClient creates a `Client` backed by GitHub's REST API, and its GraphQL
+// API where REST would take a request per item.
+//
+// limits should be the same `RateLimits` whose `Transport` is used by client's
+// HTTP client, so the recorded state covers every request made.
+func NewRESTClient(client *github.Client, limits *RateLimits) *Client {
+	return &Client{
+		Issues:        restIssueService{client: client, limits: limits},
+		Pulls:         restPullRequestService{client: client, limits: limits},
+		Repos:         restRepoService{client: client, limits: limits},
+		Actions:       restActionsService{client: client, limits: limits},
+		Notifications: restNotificationService{client: client, limits: limits},
+		Releases:      restReleaseService{client: client, limits: limits},
+		Search:        restSearchService{client: client, limits: limits},
+		RateLimits:    limits,
+	}
+}
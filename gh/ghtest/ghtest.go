@@ -0,0 +1,95 @@
+// Package ghtest provides in-memory fakes of the gh services for tests.
+//
+// The fakes answer from data the test sets on them and apply mutations to it,
+// so a page driven against them sees its own changes. Every call is recorded,
+// and a method can be made to fail with `FailWith` or to wait with `Hold`.
+package ghtest
+
+import (
+	"sync"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+)
+
+// An issue or pull request of a repository.
+type Item struct {
+	Repo   gh.Repo
+	Number int
+}
+
+// A call made to a fake, with its arguments after the context.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// Recorder records the calls made to a fake and the errors its methods fail with.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+	errs  map[string]error
+	held  map[string]chan struct{}
+}
+
+// FailWith makes method fail with err from now on, or succeed again if err is nil.
+func (r *Recorder) FailWith(method string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.errs == nil {
+		r.errs = map[string]error{}
+	}
+	r.errs[method] = err
+}
+
+// Hold makes calls to method wait, once recorded, until release is called, so
+// what a page shows while they are in flight can be checked.
+func (r *Recorder) Hold(method string) (release func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.held == nil {
+		r.held = map[string]chan struct{}{}
+	}
+	held := make(chan struct{})
+	r.held[method] = held
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if r.held[method] == held {
+				delete(r.held, method)
+			}
+			close(held)
+		})
+	}
+}
+
+// Calls returns the calls made to method, oldest first.
+func (r *Recorder) Calls(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, call := range r.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// enter locks the fake for a call to method, records it and returns the
+// error it should fail with. The caller unlocks with `exit`.
+func (r *Recorder) enter(method string, args ...any) error {
+	r.mu.Lock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+	if held := r.held[method]; held != nil {
+		r.mu.Unlock()
+		<-held
+		r.mu.Lock()
+	}
+	return r.errs[method]
+}
+
+func (r *Recorder) exit() {
+	r.mu.Unlock()
+}
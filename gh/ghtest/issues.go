@@ -0,0 +1,246 @@
+package ghtest
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+)
+
+// IssueService is an in-memory `gh.IssueService`.
+type IssueService struct {
+	Recorder
+
+	Issues     map[Item]*github.Issue
+	Timelines  map[Item][]*github.Timeline
+	Milestones map[gh.Repo][]*github.Milestone
+	// Events per timeline page, all of them in one page if 0.
+	TimelinePageSize int
+}
+
+var _ gh.IssueService = (*IssueService)(nil)
+
+// AddIssue stores issue in repo, giving it the next number if it has none.
+func (f *IssueService) AddIssue(repo gh.Repo, issue *github.Issue) *github.Issue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.addIssue(repo, issue)
+}
+
+func (f *IssueService) addIssue(repo gh.Repo, issue *github.Issue) *github.Issue {
+	if f.Issues == nil {
+		f.Issues = map[Item]*github.Issue{}
+	}
+	if issue.GetNumber() == 0 {
+		number := 1
+		for item := range f.Issues {
+			if item.Repo == repo && item.Number >= number {
+				number = item.Number + 1
+			}
+		}
+		issue.Number = github.Ptr(number)
+	}
+	if issue.State == nil {
+		issue.State = github.Ptr("open")
+	}
+	f.Issues[Item{Repo: repo, Number: issue.GetNumber()}] = issue
+	return issue
+}
+
+// SearchIssues understands the `repo:` and `is:` qualifiers and matches the
+// other terms without a qualifier against titles, ignoring other qualifiers.
+// Issues are listed by number, most recent first.
+func (f *IssueService) SearchIssues(ctx context.Context, search gh.IssueSearch) (gh.IssueSearchResult, error) {
+	defer f.exit()
+	if err := f.enter("SearchIssues", search); err != nil {
+		return gh.IssueSearchResult{}, err
+	}
+
+	var matches []*github.Issue
+	for item, issue := range f.Issues {
+		if matchesSearch(item.Repo, issue, search.Query) {
+			matches = append(matches, issue)
+		}
+	}
+	slices.SortFunc(matches, func(a, b *github.Issue) int {
+		return cmp.Compare(b.GetNumber(), a.GetNumber())
+	})
+
+	perPage := cmp.Or(search.PerPage, 30)
+	page := max(search.Page, 1)
+	result := gh.IssueSearchResult{
+		Total:    len(matches),
+		LastPage: max((len(matches)+perPage-1)/perPage, 1),
+	}
+	start := min((page-1)*perPage, len(matches))
+	result.Issues = matches[start:min(start+perPage, len(matches))]
+	return result, nil
+}
+
+func matchesSearch(repo gh.Repo, issue *github.Issue, query string) bool {
+	for _, term := range strings.Fields(query) {
+		qualifier, value, ok := strings.Cut(term, ":")
+		switch {
+		case !ok:
+			if !strings.Contains(strings.ToLower(issue.GetTitle()), strings.ToLower(term)) {
+				return false
+			}
+		case qualifier == "repo" && value != repo.String():
+			return false
+		case qualifier == "is" && (value == "open" || value == "closed") && issue.GetState() != value:
+			return false
+		case qualifier == "is" && value == "issue" && issue.IsPullRequest():
+			return false
+		case qualifier == "is" && value == "pr" && !issue.IsPullRequest():
+			return false
+		}
+	}
+	return true
+}
+
+func (f *IssueService) GetIssue(ctx context.Context, repo gh.Repo, number int) (*github.Issue, error) {
+	defer f.exit()
+	if err := f.enter("GetIssue", repo, number); err != nil {
+		return nil, err
+	}
+	issue, ok := f.Issues[Item{Repo: repo, Number: number}]
+	if !ok {
+		return nil, notFound("issue %s#%d", repo, number)
+	}
+	return issue, nil
+}
+
+func (f *IssueService) ListTimeline(ctx context.Context, repo gh.Repo, number int, page int) (gh.TimelinePage, error) {
+	defer f.exit()
+	if err := f.enter("ListTimeline", repo, number, page); err != nil {
+		return gh.TimelinePage{}, err
+	}
+	events := f.Timelines[Item{Repo: repo, Number: number}]
+	if f.TimelinePageSize == 0 {
+		return gh.TimelinePage{Events: events}, nil
+	}
+
+	start := min((max(page, 1)-1)*f.TimelinePageSize, len(events))
+	end := min(start+f.TimelinePageSize, len(events))
+	result := gh.TimelinePage{Events: events[start:end]}
+	if end < len(events) {
+		result.NextPage = max(page, 1) + 1
+	}
+	return result, nil
+}
+
+func (f *IssueService) CreateIssue(ctx context.Context, repo gh.Repo, request *github.IssueRequest) (*github.Issue, error) {
+	defer f.exit()
+	if err := f.enter("CreateIssue", repo, request); err != nil {
+		return nil, err
+	}
+	issue := &github.Issue{}
+	applyIssueRequest(issue, request)
+	return f.addIssue(repo, issue), nil
+}
+
+func (f *IssueService) EditIssue(ctx context.Context, repo gh.Repo, number int, request *github.IssueRequest) (*github.Issue, error) {
+	defer f.exit()
+	if err := f.enter("EditIssue", repo, number, request); err != nil {
+		return nil, err
+	}
+	issue, ok := f.Issues[Item{Repo: repo, Number: number}]
+	if !ok {
+		return nil, notFound("issue %s#%d", repo, number)
+	}
+	applyIssueRequest(issue, request)
+	return issue, nil
+}
+
+func applyIssueRequest(issue *github.Issue, request *github.IssueRequest) {
+	if request.Title != nil {
+		issue.Title = request.Title
+	}
+	if request.Body != nil {
+		issue.Body = request.Body
+	}
+	if request.State != nil {
+		issue.State = request.State
+	}
+	if request.StateReason != nil {
+		issue.StateReason = request.StateReason
+	}
+	if request.Labels != nil {
+		issue.Labels = nil
+		for _, name := range *request.Labels {
+			issue.Labels = append(issue.Labels, &github.Label{Name: github.Ptr(name)})
+		}
+	}
+	if request.Assignees != nil {
+		issue.Assignees = nil
+		for _, login := range *request.Assignees {
+			issue.Assignees = append(issue.Assignees, &github.User{Login: github.Ptr(login)})
+		}
+	}
+}
+
+func (f *IssueService) CreateComment(ctx context.Context, repo gh.Repo, number int, body string) (*github.IssueComment, error) {
+	defer f.exit()
+	if err := f.enter("CreateComment", repo, number, body); err != nil {
+		return nil, err
+	}
+	item := Item{Repo: repo, Number: number}
+	issue, ok := f.Issues[item]
+	if !ok {
+		return nil, notFound("issue %s#%d", repo, number)
+	}
+	if f.Timelines == nil {
+		f.Timelines = map[Item][]*github.Timeline{}
+	}
+	comment := &github.IssueComment{ID: github.Ptr(int64(len(f.Timelines[item]) + 1)), Body: github.Ptr(body)}
+	f.Timelines[item] = append(f.Timelines[item], &github.Timeline{
+		ID:    comment.ID,
+		Event: github.Ptr("commented"),
+		Body:  comment.Body,
+	})
+	issue.Comments = github.Ptr(issue.GetComments() + 1)
+	return comment, nil
+}
+
+func (f *IssueService) Lock(ctx context.Context, repo gh.Repo, number int, reason string) error {
+	return f.setLocked("Lock", repo, number, true, reason)
+}
+
+func (f *IssueService) Unlock(ctx context.Context, repo gh.Repo, number int) error {
+	return f.setLocked("Unlock", repo, number, false, "")
+}
+
+func (f *IssueService) setLocked(method string, repo gh.Repo, number int, locked bool, reason string) error {
+	defer f.exit()
+	if err := f.enter(method, repo, number, reason); err != nil {
+		return err
+	}
+	issue, ok := f.Issues[Item{Repo: repo, Number: number}]
+	if !ok {
+		return notFound("issue %s#%d", repo, number)
+	}
+	issue.Locked = github.Ptr(locked)
+	issue.ActiveLockReason = nil
+	if reason != "" {
+		issue.ActiveLockReason = github.Ptr(reason)
+	}
+	return nil
+}
+
+func (f *IssueService) ListMilestones(ctx context.Context, repo gh.Repo) ([]*github.Milestone, error) {
+	defer f.exit()
+	if err := f.enter("ListMilestones", repo); err != nil {
+		return nil, err
+	}
+	return f.Milestones[repo], nil
+}
+
+// notFound returns the error GitHub answers with for something missing.
+func notFound(format string, args ...any) error {
+	return fmt.Errorf("%s: 404 Not Found", fmt.Sprintf(format, args...))
+}
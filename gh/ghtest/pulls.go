@@ -0,0 +1,422 @@
+package ghtest
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+)
+
+// A ref of a repository.
+type Ref struct {
+	Repo gh.Repo
+	Ref  string
+}
+
+// PullRequestService is an in-memory `gh.PullRequestService`.
+type PullRequestService struct {
+	Recorder
+
+	PullRequests      map[Item]*github.PullRequest
+	Reviews           map[Item][]*github.PullRequestReview
+	Checks            map[Ref]gh.CommitChecks
+	Files             map[Item][]*github.CommitFile
+	Diffs             map[Item]string
+	Threads           map[Item][]gh.ReviewThread
+	PendingReviews    map[Item]gh.PendingReview
+	MergeRequirements map[Item]gh.MergeRequirements
+	// Branches deleted, in order.
+	DeletedBranches []Ref
+
+	// Number of comments added to pending reviews, for their IDs.
+	pendingComments int
+}
+
+var _ gh.PullRequestService = (*PullRequestService)(nil)
+
+// AddPullRequest stores pull in repo, giving it the next number if it has none.
+func (f *PullRequestService) AddPullRequest(repo gh.Repo, pull *github.PullRequest) *github.PullRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.PullRequests == nil {
+		f.PullRequests = map[Item]*github.PullRequest{}
+	}
+	if pull.GetNumber() == 0 {
+		number := 1
+		for item := range f.PullRequests {
+			if item.Repo == repo && item.Number >= number {
+				number = item.Number + 1
+			}
+		}
+		pull.Number = github.Ptr(number)
+	}
+	if pull.State == nil {
+		pull.State = github.Ptr("open")
+	}
+	f.PullRequests[Item{Repo: repo, Number: pull.GetNumber()}] = pull
+	return pull
+}
+
+// ListPullRequests lists the pull requests of repo in search.State by
+// number, most recent first. Cursors are offsets in that list.
+func (f *PullRequestService) ListPullRequests(ctx context.Context, repo gh.Repo, search gh.PullRequestSearch) (gh.PullRequestPage, error) {
+	defer f.exit()
+	if err := f.enter("ListPullRequests", repo, search); err != nil {
+		return gh.PullRequestPage{}, err
+	}
+
+	var summaries []gh.PullRequestSummary
+	for item, pull := range f.PullRequests {
+		summary := summarize(pull)
+		if item.Repo != repo || (search.State != "" && !strings.EqualFold(summary.State, search.State)) {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	slices.SortFunc(summaries, func(a, b gh.PullRequestSummary) int {
+		return cmp.Compare(b.Number, a.Number)
+	})
+
+	start := 0
+	if search.After != "" {
+		offset, err := strconv.Atoi(search.After)
+		if err != nil {
+			return gh.PullRequestPage{}, fmt.Errorf("invalid cursor %q", search.After)
+		}
+		start = min(offset, len(summaries))
+	}
+	end := min(start+cmp.Or(search.PerPage, 30), len(summaries))
+	page := gh.PullRequestPage{PullRequests: summaries[start:end], Total: len(summaries)}
+	if end < len(summaries) {
+		page.EndCursor = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+func summarize(pull *github.PullRequest) gh.PullRequestSummary {
+	state := strings.ToUpper(pull.GetState())
+	if pull.GetMerged() {
+		state = "MERGED"
+	}
+	return gh.PullRequestSummary{
+		Number:    pull.GetNumber(),
+		Title:     pull.GetTitle(),
+		Author:    pull.GetUser().GetLogin(),
+		State:     state,
+		IsDraft:   pull.GetDraft(),
+		Mergeable: "UNKNOWN",
+		UpdatedAt: pull.GetUpdatedAt().Time,
+	}
+}
+
+func (f *PullRequestService) GetPullRequest(ctx context.Context, repo gh.Repo, number int) (*github.PullRequest, error) {
+	defer f.exit()
+	if err := f.enter("GetPullRequest", repo, number); err != nil {
+		return nil, err
+	}
+	pull, ok := f.PullRequests[Item{Repo: repo, Number: number}]
+	if !ok {
+		return nil, notFound("pull request %s#%d", repo, number)
+	}
+	return pull, nil
+}
+
+func (f *PullRequestService) ListReviews(ctx context.Context, repo gh.Repo, number int) ([]*github.PullRequestReview, error) {
+	defer f.exit()
+	if err := f.enter("ListReviews", repo, number); err != nil {
+		return nil, err
+	}
+	return f.Reviews[Item{Repo: repo, Number: number}], nil
+}
+
+func (f *PullRequestService) GetChecks(ctx context.Context, repo gh.Repo, ref string) (gh.CommitChecks, error) {
+	defer f.exit()
+	if err := f.enter("GetChecks", repo, ref); err != nil {
+		return gh.CommitChecks{}, err
+	}
+	checks := f.Checks[Ref{Repo: repo, Ref: ref}]
+	// GitHub reports a combined status for every commit, with no statuses
+	// when none were set.
+	if checks.Status == nil {
+		checks.Status = &github.CombinedStatus{State: github.Ptr("pending")}
+	}
+	return checks, nil
+}
+
+func (f *PullRequestService) ListFiles(ctx context.Context, repo gh.Repo, number int) ([]*github.CommitFile, error) {
+	defer f.exit()
+	if err := f.enter("ListFiles", repo, number); err != nil {
+		return nil, err
+	}
+	return f.Files[Item{Repo: repo, Number: number}], nil
+}
+
+func (f *PullRequestService) GetDiff(ctx context.Context, repo gh.Repo, number int) (string, error) {
+	defer f.exit()
+	if err := f.enter("GetDiff", repo, number); err != nil {
+		return "", err
+	}
+	return f.Diffs[Item{Repo: repo, Number: number}], nil
+}
+
+func (f *PullRequestService) ListReviewThreads(ctx context.Context, repo gh.Repo, number int) ([]gh.ReviewThread, error) {
+	defer f.exit()
+	if err := f.enter("ListReviewThreads", repo, number); err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.Threads[Item{Repo: repo, Number: number}]), nil
+}
+
+func (f *PullRequestService) ReplyToReviewComment(ctx context.Context, repo gh.Repo, number int, commentID int64, body string) error {
+	defer f.exit()
+	if err := f.enter("ReplyToReviewComment", repo, number, commentID, body); err != nil {
+		return err
+	}
+	threads := f.Threads[Item{Repo: repo, Number: number}]
+	for i, thread := range threads {
+		for _, comment := range thread.Comments {
+			if comment.ID == commentID {
+				threads[i].Comments = append(thread.Comments, gh.ReviewThreadComment{
+					ID:   f.nextCommentID(),
+					Body: body,
+				})
+				return nil
+			}
+		}
+	}
+	return notFound("review comment %d", commentID)
+}
+
+// nextCommentID returns an ID above those of every review comment.
+func (f *PullRequestService) nextCommentID() int64 {
+	var id int64
+	for _, threads := range f.Threads {
+		for _, thread := range threads {
+			for _, comment := range thread.Comments {
+				id = max(id, comment.ID)
+			}
+		}
+	}
+	return id + 1
+}
+
+func (f *PullRequestService) SetReviewThreadResolved(ctx context.Context, threadID string, resolved bool) error {
+	defer f.exit()
+	if err := f.enter("SetReviewThreadResolved", threadID, resolved); err != nil {
+		return err
+	}
+	for _, threads := range f.Threads {
+		for i := range threads {
+			if threads[i].ID == threadID {
+				threads[i].IsResolved = resolved
+				return nil
+			}
+		}
+	}
+	return notFound("review thread %s", threadID)
+}
+
+func (f *PullRequestService) GetPendingReview(ctx context.Context, repo gh.Repo, number int) (gh.PendingReview, error) {
+	defer f.exit()
+	if err := f.enter("GetPendingReview", repo, number); err != nil {
+		return gh.PendingReview{}, err
+	}
+	pending := f.PendingReviews[Item{Repo: repo, Number: number}]
+	pending.Comments = slices.Clone(pending.Comments)
+	return pending, nil
+}
+
+// StartReview returns the pending review of the pull request, adding one if
+// there is none.
+func (f *PullRequestService) StartReview(ctx context.Context, repo gh.Repo, number int) (gh.PendingReview, error) {
+	defer f.exit()
+	if err := f.enter("StartReview", repo, number); err != nil {
+		return gh.PendingReview{}, err
+	}
+	item := Item{Repo: repo, Number: number}
+	if _, ok := f.PullRequests[item]; !ok {
+		return gh.PendingReview{}, notFound("pull request %s#%d", repo, number)
+	}
+	if pending, ok := f.PendingReviews[item]; ok {
+		pending.Comments = slices.Clone(pending.Comments)
+		return pending, nil
+	}
+	if f.PendingReviews == nil {
+		f.PendingReviews = map[Item]gh.PendingReview{}
+	}
+	pending := gh.PendingReview{ID: fmt.Sprintf("review-%s-%d", repo, number)}
+	f.PendingReviews[item] = pending
+	return pending, nil
+}
+
+// pendingReview returns the pull request the pending review with id is of.
+func (f *PullRequestService) pendingReview(id string) (Item, bool) {
+	for item, pending := range f.PendingReviews {
+		if pending.ID == id {
+			return item, true
+		}
+	}
+	return Item{}, false
+}
+
+// pendingComment returns the pull request and index of the pending comment with id.
+func (f *PullRequestService) pendingComment(id string) (Item, int, bool) {
+	for item, pending := range f.PendingReviews {
+		for i, comment := range pending.Comments {
+			if comment.ID == id {
+				return item, i, true
+			}
+		}
+	}
+	return Item{}, 0, false
+}
+
+func (f *PullRequestService) AddReviewComment(ctx context.Context, reviewID string, comment gh.ReviewComment) (gh.ReviewComment, error) {
+	defer f.exit()
+	if err := f.enter("AddReviewComment", reviewID, comment); err != nil {
+		return gh.ReviewComment{}, err
+	}
+	item, ok := f.pendingReview(reviewID)
+	if !ok {
+		return gh.ReviewComment{}, notFound("pending review %s", reviewID)
+	}
+	pending := f.PendingReviews[item]
+	f.pendingComments++
+	comment.ID = fmt.Sprintf("%s-comment-%d", reviewID, f.pendingComments)
+	pending.Comments = append(pending.Comments, comment)
+	f.PendingReviews[item] = pending
+	return comment, nil
+}
+
+func (f *PullRequestService) EditReviewComment(ctx context.Context, commentID string, body string) error {
+	defer f.exit()
+	if err := f.enter("EditReviewComment", commentID, body); err != nil {
+		return err
+	}
+	item, i, ok := f.pendingComment(commentID)
+	if !ok {
+		return notFound("pending review comment %s", commentID)
+	}
+	f.PendingReviews[item].Comments[i].Body = body
+	return nil
+}
+
+func (f *PullRequestService) DeleteReviewComment(ctx context.Context, commentID string) error {
+	defer f.exit()
+	if err := f.enter("DeleteReviewComment", commentID); err != nil {
+		return err
+	}
+	item, i, ok := f.pendingComment(commentID)
+	if !ok {
+		return notFound("pending review comment %s", commentID)
+	}
+	pending := f.PendingReviews[item]
+	pending.Comments = slices.Delete(pending.Comments, i, i+1)
+	f.PendingReviews[item] = pending
+	return nil
+}
+
+// SubmitReview adds the pending review to the reviews, and a thread for each
+// of its comments.
+func (f *PullRequestService) SubmitReview(ctx context.Context, reviewID string, event string, body string) error {
+	defer f.exit()
+	if err := f.enter("SubmitReview", reviewID, event, body); err != nil {
+		return err
+	}
+	item, ok := f.pendingReview(reviewID)
+	if !ok {
+		return notFound("pending review %s", reviewID)
+	}
+	pending := f.PendingReviews[item]
+	delete(f.PendingReviews, item)
+
+	state := map[string]string{"APPROVE": "APPROVED", "REQUEST_CHANGES": "CHANGES_REQUESTED"}[event]
+	if f.Reviews == nil {
+		f.Reviews = map[Item][]*github.PullRequestReview{}
+	}
+	f.Reviews[item] = append(f.Reviews[item], &github.PullRequestReview{
+		ID:     github.Ptr(int64(len(f.Reviews[item]) + 1)),
+		NodeID: github.Ptr(reviewID),
+		Body:   github.Ptr(body),
+		State:  github.Ptr(cmp.Or(state, "COMMENTED")),
+	})
+
+	if f.Threads == nil {
+		f.Threads = map[Item][]gh.ReviewThread{}
+	}
+	for _, comment := range pending.Comments {
+		f.Threads[item] = append(f.Threads[item], gh.ReviewThread{
+			ID:        fmt.Sprintf("thread-%d", f.nextCommentID()),
+			Path:      comment.Path,
+			Line:      comment.Line,
+			StartLine: comment.StartLine,
+			Side:      comment.Side,
+			Comments:  []gh.ReviewThreadComment{{ID: f.nextCommentID(), Body: comment.Body}},
+		})
+	}
+	return nil
+}
+
+func (f *PullRequestService) GetMergeRequirements(ctx context.Context, repo gh.Repo, number int) (gh.MergeRequirements, error) {
+	defer f.exit()
+	if err := f.enter("GetMergeRequirements", repo, number); err != nil {
+		return gh.MergeRequirements{}, err
+	}
+	requirements, ok := f.MergeRequirements[Item{Repo: repo, Number: number}]
+	if !ok {
+		return gh.MergeRequirements{}, notFound("pull request %s#%d", repo, number)
+	}
+	return requirements, nil
+}
+
+func (f *PullRequestService) Merge(ctx context.Context, repo gh.Repo, number int, method string, sha string) error {
+	defer f.exit()
+	if err := f.enter("Merge", repo, number, method, sha); err != nil {
+		return err
+	}
+	pull, ok := f.PullRequests[Item{Repo: repo, Number: number}]
+	if !ok {
+		return notFound("pull request %s#%d", repo, number)
+	}
+	if head := pull.GetHead().GetSHA(); head != "" && head != sha {
+		return fmt.Errorf("head branch was modified: 409 Conflict")
+	}
+	pull.State = github.Ptr("closed")
+	pull.Merged = github.Ptr(true)
+	return nil
+}
+
+func (f *PullRequestService) SetAutoMerge(ctx context.Context, pullRequestID string, method string) error {
+	defer f.exit()
+	if err := f.enter("SetAutoMerge", pullRequestID, method); err != nil {
+		return err
+	}
+	for item, requirements := range f.MergeRequirements {
+		if requirements.PullRequestID == pullRequestID {
+			requirements.AutoMergeMethod = method
+			f.MergeRequirements[item] = requirements
+			return nil
+		}
+	}
+	return notFound("pull request %s", pullRequestID)
+}
+
+func (f *PullRequestService) UpdateBranch(ctx context.Context, repo gh.Repo, number int, headSHA string) error {
+	defer f.exit()
+	return f.enter("UpdateBranch", repo, number, headSHA)
+}
+
+func (f *PullRequestService) DeleteBranch(ctx context.Context, repo gh.Repo, branch string) error {
+	defer f.exit()
+	if err := f.enter("DeleteBranch", repo, branch); err != nil {
+		return err
+	}
+	f.DeletedBranches = append(f.DeletedBranches, Ref{Repo: repo, Ref: branch})
+	return nil
+}
@@ -0,0 +1,70 @@
+package gh
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// GraphQLError is an error reported in the `errors` of a GraphQL response.
+type GraphQLError struct {
+	Messages []string
+}
+
+func (e GraphQLError) Error() string {
+	return "GraphQL: " + strings.Join(e.Messages, ", ")
+}
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   any `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphQL sends query to the GraphQL API of client's host, decoding the `data`
+// of the response into data.
+//
+// It goes through client so requests share its authentication, rate limit
+// handling and error types with the REST API.
+func graphQL(ctx context.Context, client *github.Client, query string, variables map[string]any, data any) error {
+	req, err := client.NewRequest(http.MethodPost, graphQLURL(client), graphQLRequest{
+		Query:     query,
+		Variables: variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	response := graphQLResponse{Data: data}
+	if _, err := client.Do(ctx, req, &response); err != nil {
+		return err
+	}
+	if len(response.Errors) > 0 {
+		messages := make([]string, len(response.Errors))
+		for i, e := range response.Errors {
+			messages[i] = e.Message
+		}
+		return GraphQLError{Messages: messages}
+	}
+	return nil
+}
+
+// graphQLURL returns the GraphQL endpoint of client's host: `/graphql` next to
+// the REST API on github.com, `/api/graphql` on GitHub Enterprise Server.
+func graphQLURL(client *github.Client) string {
+	base := *client.BaseURL
+	if strings.HasSuffix(base.Path, "/api/v3/") {
+		base.Path = strings.TrimSuffix(base.Path, "v3/") + "graphql"
+	} else {
+		base.Path = strings.TrimSuffix(base.Path, "/") + "/graphql"
+	}
+	return base.String()
+}
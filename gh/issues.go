@@ -0,0 +1,160 @@
+package gh
+
+import (
+	"context"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// IssueSearch describes a page of an issue search.
+type IssueSearch struct {
+	// Search query including qualifiers, e.g. `repo:owner/name is:open is:issue`.
+	Query string
+	// Field to sort by: "created", "updated", "comments" or "reactions".
+	Sort string
+	// "asc" or "desc".
+	Order   string
+	Page    int
+	PerPage int
+}
+
+// A page of issue search results.
+type IssueSearchResult struct {
+	Issues   []*github.Issue
+	LastPage int
+	Total    int
+}
+
+// A page of an issue's timeline.
+type TimelinePage struct {
+	Events []*github.Timeline
+	// 0 if this is the last page.
+	NextPage int
+}
+
+type IssueService interface {
+	// SearchIssues runs an issue search across GitHub.
+	SearchIssues(ctx context.Context, search IssueSearch) (IssueSearchResult, error)
+
+	// GetIssue returns a single issue or pull request by number.
+	GetIssue(ctx context.Context, repo Repo, number int) (*github.Issue, error)
+
+	// ListTimeline returns a page of the issue's timeline: comments and events
+	// such as labeling, assignment, references and closing, oldest first.
+	ListTimeline(ctx context.Context, repo Repo, number int, page int) (TimelinePage, error)
+
+	// CreateIssue opens a new issue.
+	CreateIssue(ctx context.Context, repo Repo, request *github.IssueRequest) (*github.Issue, error)
+
+	// EditIssue changes the fields of an issue set in request, including its
+	// state and state reason to close or reopen it.
+	EditIssue(ctx context.Context, repo Repo, number int, request *github.IssueRequest) (*github.Issue, error)
+
+	// CreateComment adds a comment to an issue.
+	CreateComment(ctx context.Context, repo Repo, number int, body string) (*github.IssueComment, error)
+
+	// Lock locks an issue's conversation. reason is one of "off-topic",
+	// "too heated", "resolved" or "spam", or empty for no reason.
+	Lock(ctx context.Context, repo Repo, number int, reason string) error
+
+	// Unlock unlocks an issue's conversation.
+	Unlock(ctx context.Context, repo Repo, number int) error
+
+	// ListMilestones returns the repository's open milestones.
+	ListMilestones(ctx context.Context, repo Repo) ([]*github.Milestone, error)
+}
+
+type restIssueService struct {
+	client *github.Client
+	limits *RateLimits
+}
+
+func (s restIssueService) SearchIssues(ctx context.Context, search IssueSearch) (IssueSearchResult, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (IssueSearchResult, error) {
+		result, response, err := s.client.Search.Issues(ctx, search.Query, &github.SearchOptions{
+			Sort:        search.Sort,
+			Order:       search.Order,
+			ListOptions: github.ListOptions{Page: search.Page, PerPage: search.PerPage},
+		})
+		if err != nil {
+			return IssueSearchResult{}, err
+		}
+
+		return IssueSearchResult{
+			Issues:   result.Issues,
+			LastPage: response.LastPage,
+			Total:    result.GetTotal(),
+		}, nil
+	})
+}
+
+func (s restIssueService) GetIssue(ctx context.Context, repo Repo, number int) (*github.Issue, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (*github.Issue, error) {
+		issue, _, err := s.client.Issues.Get(ctx, repo.Owner, repo.Name, number)
+		return issue, err
+	})
+}
+
+func (s restIssueService) ListTimeline(ctx context.Context, repo Repo, number int, page int) (TimelinePage, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (TimelinePage, error) {
+		events, response, err := s.client.Issues.ListIssueTimeline(ctx, repo.Owner, repo.Name, number, &github.ListOptions{
+			Page:    page,
+			PerPage: 50,
+		})
+		if err != nil {
+			return TimelinePage{}, err
+		}
+		return TimelinePage{Events: events, NextPage: response.NextPage}, nil
+	})
+}
+
+func (s restIssueService) CreateIssue(ctx context.Context, repo Repo, request *github.IssueRequest) (*github.Issue, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (*github.Issue, error) {
+		issue, _, err := s.client.Issues.Create(ctx, repo.Owner, repo.Name, request)
+		return issue, err
+	})
+}
+
+func (s restIssueService) EditIssue(ctx context.Context, repo Repo, number int, request *github.IssueRequest) (*github.Issue, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (*github.Issue, error) {
+		issue, _, err := s.client.Issues.Edit(ctx, repo.Owner, repo.Name, number, request)
+		return issue, err
+	})
+}
+
+func (s restIssueService) CreateComment(ctx context.Context, repo Repo, number int, body string) (*github.IssueComment, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (*github.IssueComment, error) {
+		comment, _, err := s.client.Issues.CreateComment(ctx, repo.Owner, repo.Name, number, &github.IssueComment{
+			Body: &body,
+		})
+		return comment, err
+	})
+}
+
+func (s restIssueService) Lock(ctx context.Context, repo Repo, number int, reason string) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (struct{}, error) {
+		_, err := s.client.Issues.Lock(ctx, repo.Owner, repo.Name, number, &github.LockIssueOptions{
+			LockReason: reason,
+		})
+		return struct{}{}, err
+	})
+	return err
+}
+
+func (s restIssueService) Unlock(ctx context.Context, repo Repo, number int) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (struct{}, error) {
+		_, err := s.client.Issues.Unlock(ctx, repo.Owner, repo.Name, number)
+		return struct{}{}, err
+	})
+	return err
+}
+
+func (s restIssueService) ListMilestones(ctx context.Context, repo Repo) ([]*github.Milestone, error) {
+	return withRateLimitRetry(ctx, s.limits, func() ([]*github.Milestone, error) {
+		milestones, _, err := s.client.Issues.ListMilestones(ctx, repo.Owner, repo.Name, &github.MilestoneListOptions{
+			State:       "open",
+			ListOptions: github.ListOptions{PerPage: 100},
+		})
+		return milestones, err
+	})
+}
@@ -0,0 +1,251 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// MergeRequirements is what a pull request needs before it can be merged,
+// and how the repository allows merging it.
+type MergeRequirements struct {
+	// GraphQL node ID of the pull request, used for auto-merge.
+	PullRequestID string
+	HeadSHA       string
+	HeadRef       string
+	// Whether the head branch is in a fork.
+	IsCrossRepository bool
+	// "CLEAN", "BLOCKED", "BEHIND", "DIRTY", "UNSTABLE", "HAS_HOOKS", "DRAFT"
+	// or "UNKNOWN" while GitHub computes it.
+	MergeStateStatus string
+	// "APPROVED", "CHANGES_REQUESTED", "REVIEW_REQUIRED" or empty when no
+	// review is required.
+	ReviewDecision string
+	// Approving reviews the base branch's protection requires, 0 if none or
+	// the protection cannot be read.
+	RequiredApprovals              int
+	RequiresConversationResolution bool
+	UnresolvedThreads              int
+	// Names of the required checks that failed and that did not finish yet.
+	FailingChecks []string
+	PendingChecks []string
+	// Method auto-merge is enabled with, empty if it is not.
+	AutoMergeMethod string
+	// Methods the repository allows: "merge", "squash" and "rebase".
+	MergeMethods        []string
+	AutoMergeAllowed    bool
+	DeleteBranchOnMerge bool
+}
+
+const getMergeRequirementsQuery = `
+query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    mergeCommitAllowed
+    squashMergeAllowed
+    rebaseMergeAllowed
+    autoMergeAllowed
+    deleteBranchOnMerge
+    pullRequest(number: $number) {
+      id
+      headRefOid
+      headRefName
+      isCrossRepository
+      mergeStateStatus
+      reviewDecision
+      autoMergeRequest { mergeMethod }
+      baseRef {
+        branchProtectionRule {
+          requiredApprovingReviewCount
+          requiresConversationResolution
+        }
+      }
+      reviewThreads(first: 100) {
+        nodes { isResolved }
+      }
+      commits(last: 1) {
+        nodes {
+          commit {
+            statusCheckRollup {
+              contexts(first: 100) {
+                nodes {
+                  __typename
+                  ... on CheckRun { name status conclusion isRequired(pullRequestNumber: $number) }
+                  ... on StatusContext { context state isRequired(pullRequestNumber: $number) }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type getMergeRequirementsData struct {
+	Repository struct {
+		MergeCommitAllowed  bool
+		SquashMergeAllowed  bool
+		RebaseMergeAllowed  bool
+		AutoMergeAllowed    bool
+		DeleteBranchOnMerge bool
+		PullRequest         struct {
+			ID                string
+			HeadRefOid        string
+			HeadRefName       string
+			IsCrossRepository bool
+			MergeStateStatus  string
+			ReviewDecision    string
+			AutoMergeRequest  *struct {
+				MergeMethod string
+			}
+			BaseRef *struct {
+				BranchProtectionRule *struct {
+					RequiredApprovingReviewCount   int
+					RequiresConversationResolution bool
+				}
+			}
+			ReviewThreads struct {
+				Nodes []struct {
+					IsResolved bool
+				}
+			}
+			Commits struct {
+				Nodes []struct {
+					Commit struct {
+						StatusCheckRollup *struct {
+							Contexts struct {
+								Nodes []struct {
+									Typename   string `json:"__typename"`
+									Name       string
+									Status     string
+									Conclusion string
+									Context    string
+									State      string
+									IsRequired bool
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func (s restPullRequestService) GetMergeRequirements(ctx context.Context, repo Repo, number int) (MergeRequirements, error) {
+	variables := map[string]any{"owner": repo.Owner, "name": repo.Name, "number": number}
+	return withRateLimitRetry(ctx, s.limits, func() (MergeRequirements, error) {
+		var data getMergeRequirementsData
+		if err := graphQL(ctx, s.client, getMergeRequirementsQuery, variables, &data); err != nil {
+			return MergeRequirements{}, err
+		}
+
+		repository := data.Repository
+		pull := repository.PullRequest
+		requirements := MergeRequirements{
+			PullRequestID:       pull.ID,
+			HeadSHA:             pull.HeadRefOid,
+			HeadRef:             pull.HeadRefName,
+			IsCrossRepository:   pull.IsCrossRepository,
+			MergeStateStatus:    pull.MergeStateStatus,
+			ReviewDecision:      pull.ReviewDecision,
+			AutoMergeAllowed:    repository.AutoMergeAllowed,
+			DeleteBranchOnMerge: repository.DeleteBranchOnMerge,
+		}
+		if pull.AutoMergeRequest != nil {
+			requirements.AutoMergeMethod = strings.ToLower(pull.AutoMergeRequest.MergeMethod)
+		}
+		if repository.MergeCommitAllowed {
+			requirements.MergeMethods = append(requirements.MergeMethods, "merge")
+		}
+		if repository.SquashMergeAllowed {
+			requirements.MergeMethods = append(requirements.MergeMethods, "squash")
+		}
+		if repository.RebaseMergeAllowed {
+			requirements.MergeMethods = append(requirements.MergeMethods, "rebase")
+		}
+		if pull.BaseRef != nil && pull.BaseRef.BranchProtectionRule != nil {
+			rule := pull.BaseRef.BranchProtectionRule
+			requirements.RequiredApprovals = rule.RequiredApprovingReviewCount
+			requirements.RequiresConversationResolution = rule.RequiresConversationResolution
+		}
+		for _, thread := range pull.ReviewThreads.Nodes {
+			if !thread.IsResolved {
+				requirements.UnresolvedThreads++
+			}
+		}
+
+		if commits := pull.Commits.Nodes; len(commits) > 0 && commits[0].Commit.StatusCheckRollup != nil {
+			for _, check := range commits[0].Commit.StatusCheckRollup.Contexts.Nodes {
+				if !check.IsRequired {
+					continue
+				}
+				if check.Typename == "StatusContext" {
+					switch check.State {
+					case "SUCCESS":
+					case "PENDING", "EXPECTED":
+						requirements.PendingChecks = append(requirements.PendingChecks, check.Context)
+					default:
+						requirements.FailingChecks = append(requirements.FailingChecks, check.Context)
+					}
+					continue
+				}
+				switch {
+				case check.Status != "COMPLETED":
+					requirements.PendingChecks = append(requirements.PendingChecks, check.Name)
+				case check.Conclusion != "SUCCESS" && check.Conclusion != "NEUTRAL" && check.Conclusion != "SKIPPED":
+					requirements.FailingChecks = append(requirements.FailingChecks, check.Name)
+				}
+			}
+		}
+		return requirements, nil
+	})
+}
+
+func (s restPullRequestService) Merge(ctx context.Context, repo Repo, number int, method string, sha string) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (*github.PullRequestMergeResult, error) {
+		result, _, err := s.client.PullRequests.Merge(ctx, repo.Owner, repo.Name, number, "", &github.PullRequestOptions{
+			MergeMethod: method,
+			SHA:         sha,
+		})
+		return result, err
+	})
+	return err
+}
+
+func (s restPullRequestService) SetAutoMerge(ctx context.Context, pullRequestID string, method string) error {
+	variables := map[string]any{"id": pullRequestID}
+	mutation := `mutation($id: ID!) { disablePullRequestAutoMerge(input: {pullRequestId: $id}) { clientMutationId } }`
+	if method != "" {
+		mutation = `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) { clientMutationId }
+}`
+		variables["method"] = strings.ToUpper(method)
+	}
+	_, err := withRateLimitRetry(ctx, s.limits, func() (struct{}, error) {
+		var data struct{}
+		return struct{}{}, graphQL(ctx, s.client, mutation, variables, &data)
+	})
+	return err
+}
+
+func (s restPullRequestService) UpdateBranch(ctx context.Context, repo Repo, number int, headSHA string) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (*github.PullRequestBranchUpdateResponse, error) {
+		response, _, err := s.client.PullRequests.UpdateBranch(ctx, repo.Owner, repo.Name, number, &github.PullRequestBranchUpdateOptions{
+			ExpectedHeadSHA: &headSHA,
+		})
+		// GitHub accepts the update and performs it in the background.
+		var accepted *github.AcceptedError
+		if errors.As(err, &accepted) {
+			return response, nil
+		}
+		return response, err
+	})
+	return err
+}
+
+func (s restPullRequestService) DeleteBranch(ctx context.Context, repo Repo, branch string) error {
+	return deleteBranch(ctx, s.client, s.limits, repo, branch)
+}
@@ -0,0 +1,118 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// The most notification pages read at once, of 50 threads each.
+const maxNotificationPages = 5
+
+// GitHub asks clients to poll notifications no more often than this, unless
+// `X-Poll-Interval` says otherwise.
+const defaultNotificationPollInterval = 60 * time.Second
+
+// The notification threads of the authenticated user, as of a poll.
+type NotificationPoll struct {
+	// Threads, most recently updated first. nil when nothing changed.
+	Threads []*github.Notification
+	// Whether the threads changed since the `Last-Modified` polled with.
+	Modified bool
+	// Passed to the next poll so GitHub only answers when something changed.
+	LastModified string
+	// How long to wait before polling again.
+	PollInterval time.Duration
+}
+
+type NotificationService interface {
+	// ListNotifications returns the notification threads across repositories,
+	// only the unread ones unless all is set. When lastModified is the
+	// `LastModified` of a previous poll and nothing changed since, the poll is
+	// not `Modified` and has no threads.
+	ListNotifications(ctx context.Context, all bool, lastModified string) (NotificationPoll, error)
+
+	// MarkThreadRead marks a thread as read, keeping it in the inbox.
+	MarkThreadRead(ctx context.Context, threadID string) error
+
+	// MarkThreadDone marks a thread as done, removing it from the inbox until
+	// something new happens on it.
+	MarkThreadDone(ctx context.Context, threadID string) error
+
+	// Unsubscribe stops notifications of a thread until the user is mentioned
+	// or takes part in it again.
+	Unsubscribe(ctx context.Context, threadID string) error
+}
+
+type restNotificationService struct {
+	client *github.Client
+	limits *RateLimits
+}
+
+func (s restNotificationService) ListNotifications(ctx context.Context, all bool, lastModified string) (NotificationPoll, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (NotificationPoll, error) {
+		poll := NotificationPoll{Modified: true, PollInterval: defaultNotificationPollInterval}
+		for page := 1; page != 0 && page <= maxNotificationPages; {
+			req, err := s.client.NewRequest(http.MethodGet, fmt.Sprintf("notifications?all=%t&per_page=50&page=%d", all, page), nil)
+			if err != nil {
+				return NotificationPoll{}, err
+			}
+			if page == 1 && lastModified != "" {
+				req.Header.Set("If-Modified-Since", lastModified)
+			}
+
+			var threads []*github.Notification
+			response, err := s.client.Do(ctx, req, &threads)
+			if page == 1 && response != nil {
+				if seconds, parseErr := strconv.Atoi(response.Header.Get("X-Poll-Interval")); parseErr == nil && seconds > 0 {
+					poll.PollInterval = time.Duration(seconds) * time.Second
+				}
+				poll.LastModified = response.Header.Get("Last-Modified")
+				// The disk cache answers a 304 with the response it revalidated,
+				// which carries the same Last-Modified.
+				if response.StatusCode == http.StatusNotModified ||
+					(err == nil && lastModified != "" && poll.LastModified == lastModified) {
+					poll.Modified = false
+					poll.LastModified = lastModified
+					return poll, nil
+				}
+			}
+			if err != nil {
+				return NotificationPoll{}, err
+			}
+
+			poll.Threads = append(poll.Threads, threads...)
+			page = response.NextPage
+		}
+		return poll, nil
+	})
+}
+
+func (s restNotificationService) MarkThreadRead(ctx context.Context, threadID string) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (*github.Response, error) {
+		return s.client.Activity.MarkThreadRead(ctx, threadID)
+	})
+	return err
+}
+
+func (s restNotificationService) MarkThreadDone(ctx context.Context, threadID string) error {
+	id, err := strconv.ParseInt(threadID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid thread ID %q", threadID)
+	}
+	_, err = withRateLimitRetry(ctx, s.limits, func() (*github.Response, error) {
+		return s.client.Activity.MarkThreadDone(ctx, id)
+	})
+	return err
+}
+
+func (s restNotificationService) Unsubscribe(ctx context.Context, threadID string) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (*github.Response, error) {
+		return s.client.Activity.DeleteThreadSubscription(ctx, threadID)
+	})
+	return err
+}
@@ -0,0 +1,207 @@
+package gh
+
+import (
+	"context"
+	"time"
+)
+
+// The overview of a repository.
+type RepoOverview struct {
+	Description   string
+	HomepageURL   string
+	Topics        []string
+	Stars         int
+	Forks         int
+	Watchers      int
+	DefaultBranch string
+	// SPDX identifier of the license, or its name when it has none, empty
+	// without a license.
+	License    string
+	IsArchived bool
+	IsFork     bool
+	// The largest languages of the repository, largest first.
+	Languages []Language
+	// Size in bytes of the code in all languages, including those left out
+	// of Languages.
+	LanguagesSize int
+	OpenIssues    int
+	OpenPulls     int
+	// nil without releases.
+	LatestRelease *ReleaseSummary
+	// Combined state of the checks of the last commit on the default branch:
+	// "SUCCESS", "FAILURE", "ERROR", "PENDING", "EXPECTED" or empty without any.
+	CheckStatus string
+	// SHA of the last commit on the default branch.
+	HeadSHA string
+}
+
+// A language of a repository and how much of it is written in it.
+type Language struct {
+	Name string
+	// Color of the language on GitHub, as `#rrggbb`, empty without one.
+	Color string
+	// Size in bytes of the code in the language.
+	Size int
+}
+
+// The name and date of a release.
+type ReleaseSummary struct {
+	Name        string
+	Tag         string
+	PublishedAt time.Time
+}
+
+// At most this many languages and topics are part of the overview.
+const (
+	overviewLanguages = 8
+	overviewTopics    = 20
+)
+
+const repoOverviewQuery = `
+query($owner: String!, $name: String!, $languages: Int!, $topics: Int!) {
+  repository(owner: $owner, name: $name) {
+    description
+    homepageUrl
+    stargazerCount
+    forkCount
+    watchers { totalCount }
+    isArchived
+    isFork
+    licenseInfo { spdxId name }
+    repositoryTopics(first: $topics) { nodes { topic { name } } }
+    languages(first: $languages, orderBy: {field: SIZE, direction: DESC}) {
+      totalSize
+      edges { size node { name color } }
+    }
+    issues(states: [OPEN]) { totalCount }
+    pullRequests(states: [OPEN]) { totalCount }
+    latestRelease { name tagName publishedAt }
+    defaultBranchRef {
+      name
+      target {
+        ... on Commit {
+          oid
+          statusCheckRollup { state }
+        }
+      }
+    }
+  }
+}`
+
+type repoOverviewData struct {
+	Repository struct {
+		Description    string
+		HomepageURL    string `json:"homepageUrl"`
+		StargazerCount int
+		ForkCount      int
+		Watchers       struct {
+			TotalCount int
+		}
+		IsArchived  bool
+		IsFork      bool
+		LicenseInfo *struct {
+			SpdxID string `json:"spdxId"`
+			Name   string
+		}
+		RepositoryTopics struct {
+			Nodes []struct {
+				Topic struct {
+					Name string
+				}
+			}
+		}
+		Languages struct {
+			TotalSize int
+			Edges     []struct {
+				Size int
+				Node struct {
+					Name  string
+					Color string
+				}
+			}
+		}
+		Issues struct {
+			TotalCount int
+		}
+		PullRequests struct {
+			TotalCount int
+		}
+		LatestRelease *struct {
+			Name        string
+			TagName     string
+			PublishedAt time.Time
+		}
+		DefaultBranchRef *struct {
+			Name   string
+			Target struct {
+				OID               string
+				StatusCheckRollup *struct {
+					State string
+				}
+			}
+		}
+	}
+}
+
+func (s restRepoService) GetOverview(ctx context.Context, repo Repo) (RepoOverview, error) {
+	variables := map[string]any{
+		"owner":     repo.Owner,
+		"name":      repo.Name,
+		"languages": overviewLanguages,
+		"topics":    overviewTopics,
+	}
+	data, err := withRateLimitRetry(ctx, s.limits, func() (repoOverviewData, error) {
+		var data repoOverviewData
+		err := graphQL(ctx, s.client, repoOverviewQuery, variables, &data)
+		return data, err
+	})
+	if err != nil {
+		return RepoOverview{}, err
+	}
+
+	r := data.Repository
+	overview := RepoOverview{
+		Description:   r.Description,
+		HomepageURL:   r.HomepageURL,
+		Stars:         r.StargazerCount,
+		Forks:         r.ForkCount,
+		Watchers:      r.Watchers.TotalCount,
+		IsArchived:    r.IsArchived,
+		IsFork:        r.IsFork,
+		LanguagesSize: r.Languages.TotalSize,
+		OpenIssues:    r.Issues.TotalCount,
+		OpenPulls:     r.PullRequests.TotalCount,
+	}
+	for _, node := range r.RepositoryTopics.Nodes {
+		overview.Topics = append(overview.Topics, node.Topic.Name)
+	}
+	for _, edge := range r.Languages.Edges {
+		overview.Languages = append(overview.Languages, Language{
+			Name:  edge.Node.Name,
+			Color: edge.Node.Color,
+			Size:  edge.Size,
+		})
+	}
+	if license := r.LicenseInfo; license != nil {
+		overview.License = license.SpdxID
+		// Licenses GitHub does not recognize have the identifier NOASSERTION.
+		if overview.License == "" || overview.License == "NOASSERTION" {
+			overview.License = license.Name
+		}
+	}
+	if release := r.LatestRelease; release != nil {
+		overview.LatestRelease = &ReleaseSummary{
+			Name:        release.Name,
+			Tag:         release.TagName,
+			PublishedAt: release.PublishedAt,
+		}
+	}
+	if branch := r.DefaultBranchRef; branch != nil {
+		overview.DefaultBranch = branch.Name
+		overview.HeadSHA = branch.Target.OID
+		if branch.Target.StatusCheckRollup != nil {
+			overview.CheckStatus = branch.Target.StatusCheckRollup.State
+		}
+	}
+	return overview, nil
+}
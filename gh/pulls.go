@@ -0,0 +1,300 @@
+package gh
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// PullRequestSearch describes a page of a repository's pull requests.
+type PullRequestSearch struct {
+	// "open", "closed" or "merged".
+	State string
+	// Only list pull requests requesting a review from the authenticated user.
+	ReviewRequested bool
+	// Cursor of the page to list, empty for the first page.
+	After   string
+	PerPage int
+}
+
+// A pull request as listed, with the state of its reviews, checks and merge.
+type PullRequestSummary struct {
+	Number int
+	Title  string
+	Author string
+	// "OPEN", "CLOSED" or "MERGED".
+	State   string
+	IsDraft bool
+	// "APPROVED", "CHANGES_REQUESTED", "REVIEW_REQUIRED" or empty when no
+	// review is required.
+	ReviewDecision string
+	// Combined state of the head commit's statuses and check runs: "SUCCESS",
+	// "FAILURE", "ERROR", "PENDING", "EXPECTED" or empty without any.
+	CheckStatus string
+	// "MERGEABLE", "CONFLICTING" or "UNKNOWN" while GitHub computes it.
+	Mergeable string
+	UpdatedAt time.Time
+}
+
+// A page of pull requests.
+type PullRequestPage struct {
+	PullRequests []PullRequestSummary
+	Total        int
+	// Cursor of the next page, empty if this is the last page.
+	EndCursor string
+}
+
+// The statuses and check runs reported on a commit.
+type CommitChecks struct {
+	Status    *github.CombinedStatus
+	CheckRuns []*github.CheckRun
+}
+
+type PullRequestService interface {
+	// ListPullRequests returns a page of the repository's pull requests, most
+	// recently updated first.
+	ListPullRequests(ctx context.Context, repo Repo, search PullRequestSearch) (PullRequestPage, error)
+
+	// GetPullRequest returns a single pull request by number.
+	GetPullRequest(ctx context.Context, repo Repo, number int) (*github.PullRequest, error)
+
+	// ListReviews returns the reviews submitted on a pull request, oldest first.
+	ListReviews(ctx context.Context, repo Repo, number int) ([]*github.PullRequestReview, error)
+
+	// GetChecks returns the combined status and the check runs of ref.
+	GetChecks(ctx context.Context, repo Repo, ref string) (CommitChecks, error)
+
+	// ListFiles returns the files changed by a pull request. GitHub leaves out
+	// the patch of files whose diff is too large, see `GetDiff`.
+	ListFiles(ctx context.Context, repo Repo, number int) ([]*github.CommitFile, error)
+
+	// GetDiff returns the whole diff of a pull request in the unified format.
+	GetDiff(ctx context.Context, repo Repo, number int) (string, error)
+
+	// ListReviewThreads returns the review threads of a pull request with their comments.
+	ListReviewThreads(ctx context.Context, repo Repo, number int) ([]ReviewThread, error)
+
+	// ReplyToReviewComment adds a reply to the thread of a review comment.
+	ReplyToReviewComment(ctx context.Context, repo Repo, number int, commentID int64, body string) error
+
+	// SetReviewThreadResolved resolves or unresolves a review thread.
+	SetReviewThreadResolved(ctx context.Context, threadID string, resolved bool) error
+
+	// GetPendingReview returns the viewer's pending review of a pull request
+	// with its comments, one without an ID if there is none.
+	GetPendingReview(ctx context.Context, repo Repo, number int) (PendingReview, error)
+
+	// StartReview returns the viewer's pending review of a pull request,
+	// creating it if there is none.
+	StartReview(ctx context.Context, repo Repo, number int) (PendingReview, error)
+
+	// AddReviewComment adds a comment to a pending review, returning it with its ID.
+	AddReviewComment(ctx context.Context, reviewID string, comment ReviewComment) (ReviewComment, error)
+
+	// EditReviewComment replaces the body of a comment of a pending review.
+	EditReviewComment(ctx context.Context, commentID string, body string) error
+
+	// DeleteReviewComment removes a comment from a pending review.
+	DeleteReviewComment(ctx context.Context, commentID string) error
+
+	// SubmitReview submits a pending review as "COMMENT", "APPROVE" or
+	// "REQUEST_CHANGES", with body as its summary.
+	SubmitReview(ctx context.Context, reviewID string, event string, body string) error
+
+	// GetMergeRequirements returns what a pull request still needs before it
+	// can be merged, and how it can be merged.
+	GetMergeRequirements(ctx context.Context, repo Repo, number int) (MergeRequirements, error)
+
+	// Merge merges a pull request with method, "merge", "squash" or "rebase",
+	// as long as its head is still sha.
+	Merge(ctx context.Context, repo Repo, number int, method string, sha string) error
+
+	// SetAutoMerge enables auto-merge with method, or disables it if method is empty.
+	SetAutoMerge(ctx context.Context, pullRequestID string, method string) error
+
+	// UpdateBranch merges the base branch into the head branch, as long as
+	// its head is still headSHA.
+	UpdateBranch(ctx context.Context, repo Repo, number int, headSHA string) error
+
+	// DeleteBranch deletes a branch of the repository.
+	DeleteBranch(ctx context.Context, repo Repo, branch string) error
+}
+
+type restPullRequestService struct {
+	client *github.Client
+	limits *RateLimits
+}
+
+// The pull request list needs review, check and merge state GitHub only
+// exposes per pull request over REST, so it is fetched in one GraphQL search.
+const listPullRequestsQuery = `
+query($query: String!, $first: Int!, $after: String) {
+  search(query: $query, type: ISSUE, first: $first, after: $after) {
+    issueCount
+    pageInfo { endCursor hasNextPage }
+    nodes {
+      ... on PullRequest {
+        number
+        title
+        author { login }
+        state
+        isDraft
+        reviewDecision
+        mergeable
+        updatedAt
+        commits(last: 1) {
+          nodes { commit { statusCheckRollup { state } } }
+        }
+      }
+    }
+  }
+}`
+
+type listPullRequestsData struct {
+	Search struct {
+		IssueCount int
+		PageInfo   struct {
+			EndCursor   string
+			HasNextPage bool
+		}
+		Nodes []struct {
+			Number int
+			Title  string
+			Author struct {
+				Login string
+			}
+			State          string
+			IsDraft        bool
+			ReviewDecision string
+			Mergeable      string
+			UpdatedAt      time.Time
+			Commits        struct {
+				Nodes []struct {
+					Commit struct {
+						StatusCheckRollup struct {
+							State string
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func (s restPullRequestService) ListPullRequests(ctx context.Context, repo Repo, search PullRequestSearch) (PullRequestPage, error) {
+	qualifiers := []string{"repo:" + repo.String(), "is:pr", "is:" + search.State, "sort:updated-desc"}
+	if search.ReviewRequested {
+		qualifiers = append(qualifiers, "review-requested:@me")
+	}
+	variables := map[string]any{
+		"query": strings.Join(qualifiers, " "),
+		"first": search.PerPage,
+	}
+	if search.After != "" {
+		variables["after"] = search.After
+	}
+
+	return withRateLimitRetry(ctx, s.limits, func() (PullRequestPage, error) {
+		var data listPullRequestsData
+		if err := graphQL(ctx, s.client, listPullRequestsQuery, variables, &data); err != nil {
+			return PullRequestPage{}, err
+		}
+
+		page := PullRequestPage{Total: data.Search.IssueCount}
+		if data.Search.PageInfo.HasNextPage {
+			page.EndCursor = data.Search.PageInfo.EndCursor
+		}
+		for _, node := range data.Search.Nodes {
+			summary := PullRequestSummary{
+				Number:         node.Number,
+				Title:          node.Title,
+				Author:         node.Author.Login,
+				State:          node.State,
+				IsDraft:        node.IsDraft,
+				ReviewDecision: node.ReviewDecision,
+				Mergeable:      node.Mergeable,
+				UpdatedAt:      node.UpdatedAt,
+			}
+			if commits := node.Commits.Nodes; len(commits) > 0 {
+				summary.CheckStatus = commits[0].Commit.StatusCheckRollup.State
+			}
+			page.PullRequests = append(page.PullRequests, summary)
+		}
+		return page, nil
+	})
+}
+
+func (s restPullRequestService) GetPullRequest(ctx context.Context, repo Repo, number int) (*github.PullRequest, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (*github.PullRequest, error) {
+		pull, _, err := s.client.PullRequests.Get(ctx, repo.Owner, repo.Name, number)
+		return pull, err
+	})
+}
+
+func (s restPullRequestService) ListReviews(ctx context.Context, repo Repo, number int) ([]*github.PullRequestReview, error) {
+	return withRateLimitRetry(ctx, s.limits, func() ([]*github.PullRequestReview, error) {
+		reviews, _, err := s.client.PullRequests.ListReviews(ctx, repo.Owner, repo.Name, number, &github.ListOptions{
+			PerPage: 100,
+		})
+		return reviews, err
+	})
+}
+
+func (s restPullRequestService) GetChecks(ctx context.Context, repo Repo, ref string) (CommitChecks, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (CommitChecks, error) {
+		status, _, err := s.client.Repositories.GetCombinedStatus(ctx, repo.Owner, repo.Name, ref, &github.ListOptions{
+			PerPage: 100,
+		})
+		if err != nil {
+			return CommitChecks{}, err
+		}
+		runs, _, err := s.client.Checks.ListCheckRunsForRef(ctx, repo.Owner, repo.Name, ref, &github.ListCheckRunsOptions{
+			ListOptions: github.ListOptions{PerPage: 100},
+		})
+		if err != nil {
+			return CommitChecks{}, err
+		}
+		return CommitChecks{Status: status, CheckRuns: runs.CheckRuns}, nil
+	})
+}
+
+// GitHub lists at most 3000 files of a pull request.
+const maxFilePages = 30
+
+func (s restPullRequestService) ListFiles(ctx context.Context, repo Repo, number int) ([]*github.CommitFile, error) {
+	type filesPage struct {
+		files    []*github.CommitFile
+		nextPage int
+	}
+
+	files := make([]*github.CommitFile, 0)
+	for page := 1; page != 0 && page <= maxFilePages; {
+		result, err := withRateLimitRetry(ctx, s.limits, func() (filesPage, error) {
+			pageFiles, response, err := s.client.PullRequests.ListFiles(ctx, repo.Owner, repo.Name, number, &github.ListOptions{
+				Page:    page,
+				PerPage: 100,
+			})
+			if err != nil {
+				return filesPage{}, err
+			}
+			return filesPage{files: pageFiles, nextPage: response.NextPage}, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, result.files...)
+		page = result.nextPage
+	}
+	return files, nil
+}
+
+func (s restPullRequestService) GetDiff(ctx context.Context, repo Repo, number int) (string, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (string, error) {
+		diff, _, err := s.client.PullRequests.GetRaw(ctx, repo.Owner, repo.Name, number, github.RawOptions{
+			Type: github.Diff,
+		})
+		return diff, err
+	})
+}
@@ -0,0 +1,198 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// The longest ghtui waits for a rate limit to reset before giving up on a request.
+const maxRateLimitWait = 5 * time.Minute
+
+// Secondary rate limit responses without a `Retry-After` header ask to wait at
+// least a minute.
+const defaultSecondaryRateLimitWait = time.Minute
+
+// How often a request hitting a rate limit is retried.
+const maxRateLimitRetries = 3
+
+// RateLimits records the primary and secondary rate limit state GitHub reports
+// on every response, and lets requests wait for limits to reset.
+//
+// Safe for concurrent use.
+type RateLimits struct {
+	mu sync.Mutex
+	// Primary rate limits by resource, e.g. "core", "search" or "graphql".
+	rates map[string]github.Rate
+	// Requests should not be made before this time after a secondary rate limit.
+	secondaryReset time.Time
+	// When requests are held back, the time they will resume.
+	waitingUntil time.Time
+}
+
+func NewRateLimits() *RateLimits {
+	return &RateLimits{rates: map[string]github.Rate{}}
+}
+
+// RateLimitStatus is a snapshot of `RateLimits`.
+type RateLimitStatus struct {
+	// Primary rate limits by resource.
+	Rates map[string]github.Rate
+	// Zero unless a secondary rate limit is in effect.
+	SecondaryReset time.Time
+	// Zero unless requests are currently held back by a rate limit.
+	WaitingUntil time.Time
+}
+
+// Status returns the current rate limit state.
+func (l *RateLimits) Status() RateLimitStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rates := make(map[string]github.Rate, len(l.rates))
+	for resource, rate := range l.rates {
+		rates[resource] = rate
+	}
+	status := RateLimitStatus{Rates: rates}
+	now := time.Now()
+	if l.secondaryReset.After(now) {
+		status.SecondaryReset = l.secondaryReset
+	}
+	if l.waitingUntil.After(now) {
+		status.WaitingUntil = l.waitingUntil
+	}
+	return status
+}
+
+// Transport wraps base so the rate limit headers of every response, REST or
+// GraphQL, are recorded.
+func (l *RateLimits) Transport(base http.RoundTripper) http.RoundTripper {
+	return rateLimitTransport{base: base, limits: l}
+}
+
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	limits *RateLimits
+}
+
+func (t rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.limits.record(resp)
+	}
+	return resp, err
+}
+
+func (l *RateLimits) record(resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if resource := resp.Header.Get("X-Ratelimit-Resource"); resource != "" {
+		rate := github.Rate{Resource: resource}
+		rate.Limit, _ = strconv.Atoi(resp.Header.Get("X-Ratelimit-Limit"))
+		rate.Remaining, _ = strconv.Atoi(resp.Header.Get("X-Ratelimit-Remaining"))
+		rate.Used, _ = strconv.Atoi(resp.Header.Get("X-Ratelimit-Used"))
+		if reset, _ := strconv.ParseInt(resp.Header.Get("X-Ratelimit-Reset"), 10, 64); reset != 0 {
+			rate.Reset = github.Timestamp{Time: time.Unix(reset, 0)}
+		}
+		l.rates[resource] = rate
+	}
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		seconds, _ := strconv.Atoi(retryAfter)
+		l.secondaryReset = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+}
+
+// waitFor blocks until `until`, marking requests as held back in the meantime.
+func (l *RateLimits) waitFor(ctx context.Context, until time.Time) error {
+	wait := time.Until(until)
+	if wait <= 0 {
+		return nil
+	}
+	if wait > maxRateLimitWait {
+		return fmt.Errorf("rate limited until %s", until.Format(time.Kitchen))
+	}
+
+	l.mu.Lock()
+	if until.After(l.waitingUntil) {
+		l.waitingUntil = until
+	}
+	l.mu.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// resumeAt returns the time requests may be made again, in the past if they
+// are not held back.
+func (l *RateLimits) resumeAt() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.secondaryReset.After(l.waitingUntil) {
+		return l.secondaryReset
+	}
+	return l.waitingUntil
+}
+
+// retryAfter returns when a request that failed with err can be retried, and
+// whether err is a rate limit error at all.
+func retryAfter(err error) (time.Time, bool) {
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		// One extra second so the reset has happened on GitHub's side too.
+		return rateLimitErr.Rate.Reset.Add(time.Second), true
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return time.Now().Add(*abuseErr.RetryAfter), true
+		}
+		return time.Now().Add(defaultSecondaryRateLimitWait), true
+	}
+
+	return time.Time{}, false
+}
+
+// withRateLimitRetry calls call, and when it fails because of a primary or
+// secondary rate limit waits for the limit to reset and tries again.
+//
+// Calls made while another request is waiting are queued behind it.
+func withRateLimitRetry[T any](ctx context.Context, limits *RateLimits, call func() (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		if err := limits.waitFor(ctx, limits.resumeAt()); err != nil {
+			var zero T
+			return zero, err
+		}
+
+		result, err := call()
+		if err == nil || attempt == maxRateLimitRetries {
+			return result, err
+		}
+
+		until, ok := retryAfter(err)
+		if !ok {
+			return result, err
+		}
+		if waitErr := limits.waitFor(ctx, until); waitErr != nil {
+			return result, err
+		}
+	}
+}
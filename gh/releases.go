@@ -0,0 +1,151 @@
+package gh
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// At most this many pages of 100 releases are listed.
+const maxReleasePages = 3
+
+// A release to create.
+type ReleaseDraft struct {
+	Tag string
+	// Branch or SHA the tag is created from when it does not exist yet, empty
+	// for the default branch.
+	Target     string
+	Name       string
+	Body       string
+	Prerelease bool
+}
+
+type ReleaseService interface {
+	// ListReleases returns the releases of the repository, newest first,
+	// drafts included when the user can push to it.
+	ListReleases(ctx context.Context, repo Repo) ([]*github.RepositoryRelease, error)
+
+	// GenerateNotes returns the name and notes GitHub generates for a release
+	// of tag from the changes since previousTag, or since the last release
+	// when previousTag is empty.
+	GenerateNotes(ctx context.Context, repo Repo, tag string, target string, previousTag string) (string, string, error)
+
+	// CreateDraft creates a draft release.
+	CreateDraft(ctx context.Context, repo Repo, draft ReleaseDraft) (*github.RepositoryRelease, error)
+
+	// PublishRelease publishes a draft release.
+	PublishRelease(ctx context.Context, repo Repo, id int64) error
+
+	// DownloadAsset writes the content of a release asset to w, calling
+	// progress with the number of bytes written so far as it goes.
+	DownloadAsset(ctx context.Context, repo Repo, id int64, w io.Writer, progress func(written int64)) error
+}
+
+type restReleaseService struct {
+	client *github.Client
+	limits *RateLimits
+}
+
+func (s restReleaseService) ListReleases(ctx context.Context, repo Repo) ([]*github.RepositoryRelease, error) {
+	releases := make([]*github.RepositoryRelease, 0)
+	for page := 1; page != 0 && page <= maxReleasePages; {
+		type releasesPage struct {
+			releases []*github.RepositoryRelease
+			nextPage int
+		}
+		result, err := withRateLimitRetry(ctx, s.limits, func() (releasesPage, error) {
+			pageReleases, response, err := s.client.Repositories.ListReleases(ctx, repo.Owner, repo.Name, &github.ListOptions{
+				Page:    page,
+				PerPage: 100,
+			})
+			if err != nil {
+				return releasesPage{}, err
+			}
+			return releasesPage{releases: pageReleases, nextPage: response.NextPage}, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, result.releases...)
+		page = result.nextPage
+	}
+	return releases, nil
+}
+
+func (s restReleaseService) GenerateNotes(ctx context.Context, repo Repo, tag string, target string, previousTag string) (string, string, error) {
+	options := &github.GenerateNotesOptions{TagName: tag}
+	if target != "" {
+		options.TargetCommitish = github.Ptr(target)
+	}
+	if previousTag != "" {
+		options.PreviousTagName = github.Ptr(previousTag)
+	}
+	notes, err := withRateLimitRetry(ctx, s.limits, func() (*github.RepositoryReleaseNotes, error) {
+		notes, _, err := s.client.Repositories.GenerateReleaseNotes(ctx, repo.Owner, repo.Name, options)
+		return notes, err
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return notes.Name, notes.Body, nil
+}
+
+func (s restReleaseService) CreateDraft(ctx context.Context, repo Repo, draft ReleaseDraft) (*github.RepositoryRelease, error) {
+	release := &github.RepositoryRelease{
+		TagName:    github.Ptr(draft.Tag),
+		Name:       github.Ptr(draft.Name),
+		Body:       github.Ptr(draft.Body),
+		Draft:      github.Ptr(true),
+		Prerelease: github.Ptr(draft.Prerelease),
+	}
+	if draft.Target != "" {
+		release.TargetCommitish = github.Ptr(draft.Target)
+	}
+	return withRateLimitRetry(ctx, s.limits, func() (*github.RepositoryRelease, error) {
+		created, _, err := s.client.Repositories.CreateRelease(ctx, repo.Owner, repo.Name, release)
+		return created, err
+	})
+}
+
+func (s restReleaseService) PublishRelease(ctx context.Context, repo Repo, id int64) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (struct{}, error) {
+		_, _, err := s.client.Repositories.EditRelease(ctx, repo.Owner, repo.Name, id, &github.RepositoryRelease{
+			Draft: github.Ptr(false),
+		})
+		return struct{}{}, err
+	})
+	return err
+}
+
+func (s restReleaseService) DownloadAsset(ctx context.Context, repo Repo, id int64, w io.Writer, progress func(written int64)) error {
+	// Assets are served from elsewhere, which the API redirects to with the
+	// authorization in the URL, so the redirect is followed without the
+	// client's credentials.
+	body, err := withRateLimitRetry(ctx, s.limits, func() (io.ReadCloser, error) {
+		body, _, err := s.client.Repositories.DownloadReleaseAsset(ctx, repo.Owner, repo.Name, id, http.DefaultClient)
+		return body, err
+	})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	_, err = io.Copy(w, &progressReader{reader: body, progress: progress})
+	return err
+}
+
+// progressReader reports how much was read from reader as it is read.
+type progressReader struct {
+	reader   io.Reader
+	read     int64
+	progress func(read int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	r.progress(r.read)
+	return n, err
+}
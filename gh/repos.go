@@ -0,0 +1,273 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// The contents API lists at most this many entries of a directory.
+const contentsListLimit = 1000
+
+// At most this many pages of 100 repositories of the user are listed.
+const maxUserRepoPages = 5
+
+// An entry of a directory in a repository.
+type ContentEntry struct {
+	Name string
+	Path string
+	// "file", "dir", "symlink" or "submodule".
+	Type string
+	// Size in bytes of files.
+	Size int
+}
+
+// A repository's README.
+type Readme struct {
+	Markdown string
+	// URL of the README on GitHub, which relative links in it are relative to.
+	HTMLURL string
+}
+
+// A file of a repository at some ref.
+type RepoFile struct {
+	Path string
+	Size int
+	// URL of the file on GitHub.
+	HTMLURL string
+	// Where a symbolic link points to.
+	Target string
+	// nil when the file is too large for the contents API, over 1 MB.
+	Content []byte
+}
+
+// The refs files of a repository can be browsed at.
+type RepoRefs struct {
+	DefaultBranch string
+	Branches      []string
+	Tags          []string
+}
+
+type RepoService interface {
+	// GetReadme returns the repository's README on its default branch, with
+	// empty markdown if it has none.
+	GetReadme(ctx context.Context, repo Repo) (Readme, error)
+
+	// ListDirectory returns the entries of the directory at dir, "" for the
+	// root, on ref, directories first. An empty ref is the default branch.
+	ListDirectory(ctx context.Context, repo Repo, ref string, dir string) ([]ContentEntry, error)
+
+	// GetFile returns the file at path on ref. An empty ref is the default branch.
+	GetFile(ctx context.Context, repo Repo, ref string, path string) (RepoFile, error)
+
+	// ListCommits returns a page of the history of a ref, newest first.
+	ListCommits(ctx context.Context, repo Repo, search CommitSearch) (CommitPage, error)
+
+	// GetCommit returns a commit with its full message and the files it
+	// changed. GitHub leaves out the patch of files whose diff is too large,
+	// see `GetCommitDiff`.
+	GetCommit(ctx context.Context, repo Repo, sha string) (*github.RepositoryCommit, error)
+
+	// CompareCommits returns the commits and changes from base to head.
+	CompareCommits(ctx context.Context, repo Repo, base string, head string) (*github.CommitsComparison, error)
+
+	// GetCommitDiff returns the diff of head in the unified format, or from
+	// base to head when base is not empty.
+	GetCommitDiff(ctx context.Context, repo Repo, base string, head string) (string, error)
+
+	// ListBranches returns the branches of the repository, compared with its
+	// default branch, and its tags.
+	ListBranches(ctx context.Context, repo Repo) (BranchList, error)
+
+	// CreateBranch creates branch at the commit of from, a branch, tag or SHA.
+	CreateBranch(ctx context.Context, repo Repo, branch string, from string) error
+
+	// RenameBranch renames branch, retargeting its pull requests.
+	RenameBranch(ctx context.Context, repo Repo, branch string, newName string) error
+
+	// DeleteBranch deletes a branch of the repository.
+	DeleteBranch(ctx context.Context, repo Repo, branch string) error
+
+	// GetOverview returns what the repository is about and how it is doing:
+	// its details, languages, counts of open issues and pull requests, latest
+	// release and the checks of its default branch.
+	GetOverview(ctx context.Context, repo Repo) (RepoOverview, error)
+
+	// ListUserRepos returns the repositories the authenticated user owns,
+	// collaborates on or can access through their organizations, the most
+	// recently pushed to first.
+	ListUserRepos(ctx context.Context) ([]*github.Repository, error)
+}
+
+type restRepoService struct {
+	client *github.Client
+	limits *RateLimits
+}
+
+func (s restRepoService) GetReadme(ctx context.Context, repo Repo) (Readme, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (Readme, error) {
+		content, response, err := s.client.Repositories.GetReadme(ctx, repo.Owner, repo.Name, nil)
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return Readme{}, nil
+		}
+		if err != nil {
+			return Readme{}, err
+		}
+		markdown, err := content.GetContent()
+		if err != nil {
+			return Readme{}, err
+		}
+		return Readme{Markdown: markdown, HTMLURL: content.GetHTMLURL()}, nil
+	})
+}
+
+func (s restRepoService) ListDirectory(ctx context.Context, repo Repo, ref string, dir string) ([]ContentEntry, error) {
+	return withRateLimitRetry(ctx, s.limits, func() ([]ContentEntry, error) {
+		options := &github.RepositoryContentGetOptions{Ref: ref}
+		_, contents, _, err := s.client.Repositories.GetContents(ctx, repo.Owner, repo.Name, dir, options)
+		if err != nil {
+			return nil, err
+		}
+		if contents == nil {
+			return nil, fmt.Errorf("%s is not a directory", dir)
+		}
+
+		entries := make([]ContentEntry, 0, len(contents))
+		if len(contents) < contentsListLimit {
+			for _, content := range contents {
+				entries = append(entries, ContentEntry{
+					Name: content.GetName(),
+					Path: content.GetPath(),
+					Type: content.GetType(),
+					Size: content.GetSize(),
+				})
+			}
+		} else {
+			// The Git Trees API lists every entry of large directories, by the
+			// SHA of the tree, or the ref for the root.
+			sha := ref
+			if dir != "" {
+				sha, err = s.treeSHA(ctx, repo, ref, dir)
+				if err != nil {
+					return nil, err
+				}
+			} else if sha == "" {
+				sha = "HEAD"
+			}
+			tree, _, err := s.client.Git.GetTree(ctx, repo.Owner, repo.Name, sha, false)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range tree.Entries {
+				kind := "file"
+				switch {
+				case entry.GetType() == "tree":
+					kind = "dir"
+				case entry.GetType() == "commit":
+					kind = "submodule"
+				case entry.GetMode() == "120000":
+					kind = "symlink"
+				}
+				entries = append(entries, ContentEntry{
+					Name: entry.GetPath(),
+					Path: path.Join(dir, entry.GetPath()),
+					Type: kind,
+					Size: entry.GetSize(),
+				})
+			}
+		}
+
+		slices.SortStableFunc(entries, func(a ContentEntry, b ContentEntry) int {
+			if (a.Type == "dir") != (b.Type == "dir") {
+				if a.Type == "dir" {
+					return -1
+				}
+				return 1
+			}
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+		return entries, nil
+	})
+}
+
+// treeSHA returns the SHA of the tree of dir on ref, from the listing of its parent.
+func (s restRepoService) treeSHA(ctx context.Context, repo Repo, ref string, dir string) (string, error) {
+	parent := path.Dir(dir)
+	if parent == "." {
+		parent = ""
+	}
+	_, contents, _, err := s.client.Repositories.GetContents(ctx, repo.Owner, repo.Name, parent, &github.RepositoryContentGetOptions{
+		Ref: ref,
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, content := range contents {
+		if content.GetPath() == dir {
+			return content.GetSHA(), nil
+		}
+	}
+	return "", fmt.Errorf("%s not found", dir)
+}
+
+func (s restRepoService) GetFile(ctx context.Context, repo Repo, ref string, filePath string) (RepoFile, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (RepoFile, error) {
+		file, _, _, err := s.client.Repositories.GetContents(ctx, repo.Owner, repo.Name, filePath, &github.RepositoryContentGetOptions{
+			Ref: ref,
+		})
+		if err != nil {
+			return RepoFile{}, err
+		}
+		if file == nil {
+			return RepoFile{}, fmt.Errorf("%s is a directory", filePath)
+		}
+
+		result := RepoFile{
+			Path:    file.GetPath(),
+			Size:    file.GetSize(),
+			HTMLURL: file.GetHTMLURL(),
+			Target:  file.GetTarget(),
+		}
+		if file.GetEncoding() == "none" || file.GetType() == "symlink" {
+			return result, nil
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			return RepoFile{}, err
+		}
+		result.Content = []byte(content)
+		return result, nil
+	})
+}
+
+func (s restRepoService) ListUserRepos(ctx context.Context) ([]*github.Repository, error) {
+	repos := make([]*github.Repository, 0)
+	for page := 1; page != 0 && page <= maxUserRepoPages; {
+		type reposPage struct {
+			repos    []*github.Repository
+			nextPage int
+		}
+		result, err := withRateLimitRetry(ctx, s.limits, func() (reposPage, error) {
+			pageRepos, response, err := s.client.Repositories.ListByAuthenticatedUser(ctx, &github.RepositoryListByAuthenticatedUserOptions{
+				Affiliation: "owner,collaborator,organization_member",
+				Sort:        "pushed",
+				ListOptions: github.ListOptions{Page: page, PerPage: 100},
+			})
+			if err != nil {
+				return reposPage{}, err
+			}
+			return reposPage{repos: pageRepos, nextPage: response.NextPage}, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, result.repos...)
+		page = result.nextPage
+	}
+	return repos, nil
+}
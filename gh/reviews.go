@@ -0,0 +1,390 @@
+package gh
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// A review thread: comments attached to a line or range of lines of a pull
+// request's diff.
+type ReviewThread struct {
+	// GraphQL node ID, used to resolve the thread.
+	ID         string
+	IsResolved bool
+	// Whether the lines commented on changed since.
+	IsOutdated bool
+	Path       string
+	// Line the thread is on, and the first line of a range, 0 if not a range.
+	// Both are 0 for outdated threads.
+	Line      int
+	StartLine int
+	// "LEFT" for lines of the base, "RIGHT" for lines of the head.
+	Side     string
+	Comments []ReviewThreadComment
+}
+
+type ReviewThreadComment struct {
+	// REST ID, used to reply to the comment.
+	ID        int64
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// A comment of a review being written, on a line or range of lines of the diff.
+type ReviewComment struct {
+	// GraphQL node ID, empty until the comment is added to a pending review.
+	ID   string
+	Path string
+	Body string
+	// "LEFT" for lines of the base, "RIGHT" for lines of the head.
+	Side string
+	Line int
+	// First line of a range, 0 for a single line.
+	StartSide string
+	StartLine int
+}
+
+// A review being written, only visible to its author until it is submitted.
+type PendingReview struct {
+	// GraphQL node ID, empty when there is no pending review.
+	ID       string
+	Comments []ReviewComment
+}
+
+const listReviewThreadsQuery = `
+query($owner: String!, $name: String!, $number: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $after) {
+        pageInfo { endCursor hasNextPage }
+        nodes {
+          id
+          isResolved
+          isOutdated
+          path
+          line
+          startLine
+          diffSide
+          comments(first: 100) { ...comments }
+        }
+      }
+    }
+  }
+}
+
+fragment comments on PullRequestReviewCommentConnection {
+  pageInfo { endCursor hasNextPage }
+  nodes { databaseId author { login } body createdAt state }
+}`
+
+// Comments of a thread past the first page listed with it.
+const listReviewThreadCommentsQuery = `
+query($id: ID!, $after: String) {
+  node(id: $id) {
+    ... on PullRequestReviewThread {
+      comments(first: 100, after: $after) { ...comments }
+    }
+  }
+}
+
+fragment comments on PullRequestReviewCommentConnection {
+  pageInfo { endCursor hasNextPage }
+  nodes { databaseId author { login } body createdAt state }
+}`
+
+type reviewThreadCommentsData struct {
+	PageInfo struct {
+		EndCursor   string
+		HasNextPage bool
+	}
+	Nodes []struct {
+		DatabaseID int64
+		Author     struct {
+			Login string
+		}
+		Body      string
+		CreatedAt time.Time
+		State     string
+	}
+}
+
+func (c reviewThreadCommentsData) comments() []ReviewThreadComment {
+	comments := make([]ReviewThreadComment, 0, len(c.Nodes))
+	for _, comment := range c.Nodes {
+		comments = append(comments, ReviewThreadComment{
+			ID:        comment.DatabaseID,
+			Author:    comment.Author.Login,
+			Body:      comment.Body,
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+	return comments
+}
+
+type listReviewThreadsData struct {
+	Repository struct {
+		PullRequest struct {
+			ReviewThreads struct {
+				PageInfo struct {
+					EndCursor   string
+					HasNextPage bool
+				}
+				Nodes []struct {
+					ID         string
+					IsResolved bool
+					IsOutdated bool
+					Path       string
+					Line       int
+					StartLine  int
+					DiffSide   string
+					Comments   reviewThreadCommentsData
+				}
+			}
+		}
+	}
+}
+
+type listReviewThreadCommentsData struct {
+	Node struct {
+		Comments reviewThreadCommentsData
+	}
+}
+
+func (s restPullRequestService) ListReviewThreads(ctx context.Context, repo Repo, number int) ([]ReviewThread, error) {
+	threads := make([]ReviewThread, 0)
+	variables := map[string]any{"owner": repo.Owner, "name": repo.Name, "number": number}
+	for {
+		data, err := withRateLimitRetry(ctx, s.limits, func() (listReviewThreadsData, error) {
+			var data listReviewThreadsData
+			err := graphQL(ctx, s.client, listReviewThreadsQuery, variables, &data)
+			return data, err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		page := data.Repository.PullRequest.ReviewThreads
+		for _, node := range page.Nodes {
+			// Threads of the viewer's pending review are its comments.
+			if len(node.Comments.Nodes) > 0 && node.Comments.Nodes[0].State == "PENDING" {
+				continue
+			}
+			thread := ReviewThread{
+				ID:         node.ID,
+				IsResolved: node.IsResolved,
+				IsOutdated: node.IsOutdated,
+				Path:       node.Path,
+				Line:       node.Line,
+				StartLine:  node.StartLine,
+				Side:       node.DiffSide,
+				Comments:   node.Comments.comments(),
+			}
+			if node.Comments.PageInfo.HasNextPage {
+				rest, err := s.listReviewThreadComments(ctx, node.ID, node.Comments.PageInfo.EndCursor)
+				if err != nil {
+					return nil, err
+				}
+				thread.Comments = append(thread.Comments, rest...)
+			}
+			threads = append(threads, thread)
+		}
+		if !page.PageInfo.HasNextPage {
+			return threads, nil
+		}
+		variables["after"] = page.PageInfo.EndCursor
+	}
+}
+
+// listReviewThreadComments returns the comments of the thread with id that
+// come after the cursor after.
+func (s restPullRequestService) listReviewThreadComments(ctx context.Context, id string, after string) ([]ReviewThreadComment, error) {
+	comments := make([]ReviewThreadComment, 0)
+	variables := map[string]any{"id": id, "after": after}
+	for {
+		data, err := withRateLimitRetry(ctx, s.limits, func() (listReviewThreadCommentsData, error) {
+			var data listReviewThreadCommentsData
+			err := graphQL(ctx, s.client, listReviewThreadCommentsQuery, variables, &data)
+			return data, err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		page := data.Node.Comments
+		comments = append(comments, page.comments()...)
+		if !page.PageInfo.HasNextPage {
+			return comments, nil
+		}
+		variables["after"] = page.PageInfo.EndCursor
+	}
+}
+
+func (s restPullRequestService) ReplyToReviewComment(ctx context.Context, repo Repo, number int, commentID int64, body string) error {
+	_, err := withRateLimitRetry(ctx, s.limits, func() (*github.PullRequestComment, error) {
+		comment, _, err := s.client.PullRequests.CreateCommentInReplyTo(ctx, repo.Owner, repo.Name, number, body, commentID)
+		return comment, err
+	})
+	return err
+}
+
+func (s restPullRequestService) SetReviewThreadResolved(ctx context.Context, threadID string, resolved bool) error {
+	mutation := `mutation($id: ID!) { unresolveReviewThread(input: {threadId: $id}) { thread { id } } }`
+	if resolved {
+		mutation = `mutation($id: ID!) { resolveReviewThread(input: {threadId: $id}) { thread { id } } }`
+	}
+	_, err := withRateLimitRetry(ctx, s.limits, func() (struct{}, error) {
+		var data struct{}
+		return struct{}{}, graphQL(ctx, s.client, mutation, map[string]any{"id": threadID}, &data)
+	})
+	return err
+}
+
+func (s restPullRequestService) GetPendingReview(ctx context.Context, repo Repo, number int) (PendingReview, error) {
+	return withRateLimitRetry(ctx, s.limits, func() (PendingReview, error) {
+		var pending *github.PullRequestReview
+		options := &github.ListOptions{PerPage: 100}
+		for pending == nil {
+			reviews, response, err := s.client.PullRequests.ListReviews(ctx, repo.Owner, repo.Name, number, options)
+			if err != nil {
+				return PendingReview{}, err
+			}
+			for _, review := range reviews {
+				if review.GetState() == "PENDING" {
+					pending = review
+				}
+			}
+			if response.NextPage == 0 {
+				break
+			}
+			options.Page = response.NextPage
+		}
+		if pending == nil {
+			return PendingReview{}, nil
+		}
+
+		review := PendingReview{ID: pending.GetNodeID(), Comments: make([]ReviewComment, 0)}
+		options = &github.ListOptions{PerPage: 100}
+		for {
+			comments, response, err := s.client.PullRequests.ListReviewComments(ctx, repo.Owner, repo.Name, number, pending.GetID(), options)
+			if err != nil {
+				return PendingReview{}, err
+			}
+			for _, comment := range comments {
+				review.Comments = append(review.Comments, ReviewComment{
+					ID:        comment.GetNodeID(),
+					Path:      comment.GetPath(),
+					Body:      comment.GetBody(),
+					Side:      comment.GetSide(),
+					Line:      comment.GetLine(),
+					StartSide: comment.GetStartSide(),
+					StartLine: comment.GetStartLine(),
+				})
+			}
+			if response.NextPage == 0 {
+				return review, nil
+			}
+			options.Page = response.NextPage
+		}
+	})
+}
+
+func (s restPullRequestService) StartReview(ctx context.Context, repo Repo, number int) (PendingReview, error) {
+	pending, err := s.GetPendingReview(ctx, repo, number)
+	if err != nil || pending.ID != "" {
+		return pending, err
+	}
+	return withRateLimitRetry(ctx, s.limits, func() (PendingReview, error) {
+		// A review created without an event stays pending.
+		created, _, err := s.client.PullRequests.CreateReview(ctx, repo.Owner, repo.Name, number, &github.PullRequestReviewRequest{})
+		if err != nil {
+			return PendingReview{}, err
+		}
+		return PendingReview{ID: created.GetNodeID(), Comments: make([]ReviewComment, 0)}, nil
+	})
+}
+
+const addReviewCommentMutation = `
+mutation($review: ID!, $path: String!, $body: String!, $side: DiffSide!, $line: Int!, $startSide: DiffSide, $startLine: Int) {
+  addPullRequestReviewThread(input: {
+    pullRequestReviewId: $review, path: $path, body: $body,
+    side: $side, line: $line, startSide: $startSide, startLine: $startLine
+  }) {
+    thread {
+      comments(first: 1) { nodes { id } }
+    }
+  }
+}`
+
+type addReviewCommentData struct {
+	AddPullRequestReviewThread struct {
+		Thread struct {
+			Comments struct {
+				Nodes []struct {
+					ID string
+				}
+			}
+		}
+	}
+}
+
+func (s restPullRequestService) AddReviewComment(ctx context.Context, reviewID string, comment ReviewComment) (ReviewComment, error) {
+	variables := map[string]any{
+		"review": reviewID,
+		"path":   comment.Path,
+		"body":   comment.Body,
+		"side":   comment.Side,
+		"line":   comment.Line,
+	}
+	if comment.StartLine != 0 {
+		variables["startSide"] = comment.StartSide
+		variables["startLine"] = comment.StartLine
+	}
+	return withRateLimitRetry(ctx, s.limits, func() (ReviewComment, error) {
+		var data addReviewCommentData
+		if err := graphQL(ctx, s.client, addReviewCommentMutation, variables, &data); err != nil {
+			return ReviewComment{}, err
+		}
+		if nodes := data.AddPullRequestReviewThread.Thread.Comments.Nodes; len(nodes) > 0 {
+			comment.ID = nodes[0].ID
+		}
+		return comment, nil
+	})
+}
+
+func (s restPullRequestService) EditReviewComment(ctx context.Context, commentID string, body string) error {
+	mutation := `mutation($id: ID!, $body: String!) {
+  updatePullRequestReviewComment(input: {pullRequestReviewCommentId: $id, body: $body}) { pullRequestReviewComment { id } }
+}`
+	_, err := withRateLimitRetry(ctx, s.limits, func() (struct{}, error) {
+		var data struct{}
+		return struct{}{}, graphQL(ctx, s.client, mutation, map[string]any{"id": commentID, "body": body}, &data)
+	})
+	return err
+}
+
+func (s restPullRequestService) DeleteReviewComment(ctx context.Context, commentID string) error {
+	mutation := `mutation($id: ID!) { deletePullRequestReviewComment(input: {id: $id}) { pullRequestReview { id } } }`
+	_, err := withRateLimitRetry(ctx, s.limits, func() (struct{}, error) {
+		var data struct{}
+		return struct{}{}, graphQL(ctx, s.client, mutation, map[string]any{"id": commentID}, &data)
+	})
+	return err
+}
+
+func (s restPullRequestService) SubmitReview(ctx context.Context, reviewID string, event string, body string) error {
+	mutation := `mutation($id: ID!, $event: PullRequestReviewEvent!, $body: String) {
+  submitPullRequestReview(input: {pullRequestReviewId: $id, event: $event, body: $body}) { pullRequestReview { id } }
+}`
+	variables := map[string]any{"id": reviewID, "event": event}
+	if body != "" {
+		variables["body"] = body
+	}
+	_, err := withRateLimitRetry(ctx, s.limits, func() (struct{}, error) {
+		var data struct{}
+		return struct{}{}, graphQL(ctx, s.client, mutation, variables, &data)
+	})
+	return err
+}
@@ -0,0 +1,68 @@
+package gh
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v69/github"
+)
+
+func TestListReviewThreadsPages(t *testing.T) {
+	comment := func(id int, state string) string {
+		return `{"databaseId": ` + strconv.Itoa(id) + `, "author": {"login": "octocat"}, "body": "b", "createdAt": "2024-01-01T00:00:00Z", "state": "` + state + `"}`
+	}
+	responses := map[string]string{
+		// First page of threads: one with a second page of comments, one pending.
+		"": `{"data": {"repository": {"pullRequest": {"reviewThreads": {
+			"pageInfo": {"endCursor": "t1", "hasNextPage": true},
+			"nodes": [
+				{"id": "A", "path": "a.go", "comments": {"pageInfo": {"endCursor": "c1", "hasNextPage": true}, "nodes": [` + comment(1, "SUBMITTED") + `]}},
+				{"id": "P", "path": "p.go", "comments": {"pageInfo": {"hasNextPage": false}, "nodes": [` + comment(2, "PENDING") + `]}}
+			]}}}}}`,
+		"t1": `{"data": {"repository": {"pullRequest": {"reviewThreads": {
+			"pageInfo": {"hasNextPage": false},
+			"nodes": [
+				{"id": "B", "path": "b.go", "comments": {"pageInfo": {"hasNextPage": false}, "nodes": [` + comment(3, "SUBMITTED") + `]}}
+			]}}}}}`,
+		"c1": `{"data": {"node": {"comments": {
+			"pageInfo": {"hasNextPage": false},
+			"nodes": [` + comment(4, "SUBMITTED") + `]}}}}`,
+	}
+
+	client := github.NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var request struct {
+			Variables map[string]any
+		}
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+		after, _ := request.Variables["after"].(string)
+		body, ok := responses[after]
+		if !ok {
+			t.Fatalf("unexpected request after %q", after)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})})
+	service := restPullRequestService{client: client, limits: NewRateLimits()}
+
+	threads, err := service.ListReviewThreads(context.Background(), Repo{Owner: "owner", Name: "repo"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(threads) != 2 || threads[0].ID != "A" || threads[1].ID != "B" {
+		t.Fatalf("threads %+v, want A and B", threads)
+	}
+	if comments := threads[0].Comments; len(comments) != 2 || comments[0].ID != 1 || comments[1].ID != 4 {
+		t.Errorf("comments of A %+v, want 1 and 4", comments)
+	}
+}
@@ -0,0 +1,178 @@
+package gh
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// At most this many items of each section of `MyWork` are listed.
+const myWorkSectionSize = 30
+
+// The searches listing each section of `MyWork`.
+const (
+	reviewRequestedQuery = "is:open is:pr archived:false review-requested:@me sort:updated-desc"
+	myPullRequestsQuery  = "is:open is:pr archived:false author:@me sort:updated-desc"
+	assignedIssuesQuery  = "is:open is:issue archived:false assignee:@me sort:updated-desc"
+	mentionsQuery        = "mentions:@me sort:updated-desc"
+)
+
+// An issue or pull request found across repositories.
+type WorkItem struct {
+	Repo        Repo
+	Number      int
+	Title       string
+	URL         string
+	PullRequest bool
+	// "OPEN", "CLOSED" or, for pull requests, "MERGED".
+	State     string
+	IsDraft   bool
+	Author    string
+	UpdatedAt time.Time
+	// Combined state of the checks of a pull request's last commit:
+	// "SUCCESS", "FAILURE", "ERROR", "PENDING", "EXPECTED" or empty without any.
+	CheckStatus string
+	// Whether a pull request is "APPROVED", has "CHANGES_REQUESTED" or has
+	// "REVIEW_REQUIRED", empty when reviews are not required.
+	ReviewDecision string
+}
+
+// The open issues and pull requests waiting on the authenticated user, and
+// the latest ones mentioning them, most recently updated first.
+type MyWork struct {
+	ReviewRequested []WorkItem
+	PullRequests    []WorkItem
+	AssignedIssues  []WorkItem
+	Mentions        []WorkItem
+}
+
+type SearchService interface {
+	// GetMyWork searches every repository the user can access for the pull
+	// requests awaiting their review, their own open pull requests, the open
+	// issues assigned to them and what mentions them.
+	GetMyWork(ctx context.Context) (MyWork, error)
+}
+
+type restSearchService struct {
+	client *github.Client
+	limits *RateLimits
+}
+
+// The four sections are searched in one request.
+const myWorkQuery = `
+query($reviewRequested: String!, $pullRequests: String!, $assignedIssues: String!, $mentions: String!, $first: Int!) {
+  reviewRequested: search(query: $reviewRequested, type: ISSUE, first: $first) { nodes { ...item } }
+  pullRequests: search(query: $pullRequests, type: ISSUE, first: $first) { nodes { ...item } }
+  assignedIssues: search(query: $assignedIssues, type: ISSUE, first: $first) { nodes { ...item } }
+  mentions: search(query: $mentions, type: ISSUE, first: $first) { nodes { ...item } }
+}
+
+fragment item on SearchResultItem {
+  __typename
+  ... on Issue {
+    number title url state updatedAt
+    author { login }
+    repository { nameWithOwner }
+  }
+  ... on PullRequest {
+    number title url state updatedAt isDraft reviewDecision
+    author { login }
+    repository { nameWithOwner }
+    commits(last: 1) { nodes { commit { statusCheckRollup { state } } } }
+  }
+}`
+
+type workItemData struct {
+	Typename  string `json:"__typename"`
+	Number    int
+	Title     string
+	URL       string
+	State     string
+	UpdatedAt time.Time
+	IsDraft   bool
+	// Null when reviews are not required.
+	ReviewDecision *string
+	Author         *struct {
+		Login string
+	}
+	Repository struct {
+		NameWithOwner string
+	}
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State string
+				}
+			}
+		}
+	}
+}
+
+type searchResultData struct {
+	Nodes []workItemData
+}
+
+type myWorkData struct {
+	ReviewRequested searchResultData
+	PullRequests    searchResultData
+	AssignedIssues  searchResultData
+	Mentions        searchResultData
+}
+
+func (s restSearchService) GetMyWork(ctx context.Context) (MyWork, error) {
+	variables := map[string]any{
+		"reviewRequested": reviewRequestedQuery,
+		"pullRequests":    myPullRequestsQuery,
+		"assignedIssues":  assignedIssuesQuery,
+		"mentions":        mentionsQuery,
+		"first":           myWorkSectionSize,
+	}
+	data, err := withRateLimitRetry(ctx, s.limits, func() (myWorkData, error) {
+		var data myWorkData
+		err := graphQL(ctx, s.client, myWorkQuery, variables, &data)
+		return data, err
+	})
+	if err != nil {
+		return MyWork{}, err
+	}
+	return MyWork{
+		ReviewRequested: data.ReviewRequested.workItems(),
+		PullRequests:    data.PullRequests.workItems(),
+		AssignedIssues:  data.AssignedIssues.workItems(),
+		Mentions:        data.Mentions.workItems(),
+	}, nil
+}
+
+func (d searchResultData) workItems() []WorkItem {
+	items := make([]WorkItem, 0, len(d.Nodes))
+	for _, node := range d.Nodes {
+		repo, err := ParseRepo(node.Repository.NameWithOwner)
+		// Search results the token cannot see come back empty.
+		if err != nil {
+			continue
+		}
+		item := WorkItem{
+			Repo:        repo,
+			Number:      node.Number,
+			Title:       node.Title,
+			URL:         node.URL,
+			PullRequest: node.Typename == "PullRequest",
+			State:       node.State,
+			IsDraft:     node.IsDraft,
+			UpdatedAt:   node.UpdatedAt,
+		}
+		if node.Author != nil {
+			item.Author = node.Author.Login
+		}
+		if node.ReviewDecision != nil {
+			item.ReviewDecision = *node.ReviewDecision
+		}
+		if commits := node.Commits.Nodes; len(commits) > 0 && commits[0].Commit.StatusCheckRollup != nil {
+			item.CheckStatus = commits[0].Commit.StatusCheckRollup.State
+		}
+		items = append(items, item)
+	}
+	return items
+}
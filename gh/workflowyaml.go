@@ -0,0 +1,112 @@
+package gh
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// An input of a workflow run manually through `workflow_dispatch`.
+type WorkflowInput struct {
+	Name        string
+	Description string
+	// "string", "boolean", "choice", "number" or "environment".
+	Type     string
+	Default  string
+	Required bool
+	// Choices of a "choice" input.
+	Options []string
+}
+
+// The part of a workflow file read for its triggers.
+type workflowFile struct {
+	On workflowTriggers `yaml:"on"`
+}
+
+// The events of a workflow's `on`, given as a single event, a sequence of
+// them, or a mapping of each to its configuration.
+type workflowTriggers struct {
+	dispatchable bool
+	// Inputs of `workflow_dispatch`, nil unless it is configured as a mapping.
+	inputs []WorkflowInput
+}
+
+func (t *workflowTriggers) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		var event string
+		if err := node.Decode(&event); err != nil {
+			return err
+		}
+		t.dispatchable = event == "workflow_dispatch"
+	case yaml.SequenceNode:
+		var events []string
+		if err := node.Decode(&events); err != nil {
+			return err
+		}
+		for _, event := range events {
+			if event == "workflow_dispatch" {
+				t.dispatchable = true
+			}
+		}
+	case yaml.MappingNode:
+		var events map[string]struct {
+			Inputs workflowInputs `yaml:"inputs"`
+		}
+		if err := node.Decode(&events); err != nil {
+			return err
+		}
+		dispatch, ok := events["workflow_dispatch"]
+		if !ok {
+			return nil
+		}
+		t.dispatchable = true
+		t.inputs = append(make([]WorkflowInput, 0, len(dispatch.Inputs)), dispatch.Inputs...)
+	}
+	return nil
+}
+
+// The inputs of `workflow_dispatch`, in the order the file declares them.
+type workflowInputs []WorkflowInput
+
+func (inputs *workflowInputs) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var spec struct {
+			Description string   `yaml:"description"`
+			Type        string   `yaml:"type"`
+			Default     string   `yaml:"default"`
+			Required    bool     `yaml:"required"`
+			Options     []string `yaml:"options"`
+		}
+		if err := node.Content[i+1].Decode(&spec); err != nil {
+			return err
+		}
+		input := WorkflowInput{
+			Name:        node.Content[i].Value,
+			Description: strings.TrimSpace(spec.Description),
+			Type:        spec.Type,
+			Default:     spec.Default,
+			Required:    spec.Required,
+			Options:     spec.Options,
+		}
+		if input.Type == "" {
+			input.Type = "string"
+		}
+		*inputs = append(*inputs, input)
+	}
+	return nil
+}
+
+// parseDispatchInputs returns whether a workflow file can be run through
+// `workflow_dispatch`, and the inputs it then asks for. A file that is not
+// valid YAML cannot.
+func parseDispatchInputs(workflow string) ([]WorkflowInput, bool) {
+	var file workflowFile
+	if err := yaml.Unmarshal([]byte(workflow), &file); err != nil {
+		return nil, false
+	}
+	return file.On.inputs, file.On.dispatchable
+}
@@ -0,0 +1,147 @@
+package gh
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDispatchInputs(t *testing.T) {
+	tests := []struct {
+		name         string
+		workflow     string
+		wantInputs   []WorkflowInput
+		dispatchable bool
+	}{
+		{
+			name:         "single event",
+			workflow:     "on: workflow_dispatch\njobs: {}\n",
+			dispatchable: true,
+		},
+		{
+			name:     "other events",
+			workflow: "on: [push, pull_request]\n",
+		},
+		{
+			name:         "flow sequence",
+			workflow:     "on: [push, \"workflow_dispatch\"]\n",
+			dispatchable: true,
+		},
+		{
+			name:         "block sequence",
+			workflow:     "on:\n  - push\n  - workflow_dispatch\n",
+			dispatchable: true,
+		},
+		{
+			name:     "no trigger",
+			workflow: "name: CI\n",
+		},
+		{
+			name:         "dispatch without configuration",
+			workflow:     "on:\n  push:\n  workflow_dispatch:\n",
+			dispatchable: true,
+			wantInputs:   []WorkflowInput{},
+		},
+		{
+			name: "block mapping with inputs",
+			workflow: `name: Deploy
+on:
+  push:
+    branches: [main]
+  workflow_dispatch: # run by hand
+    inputs:
+      environment:
+        description: |
+          Where to deploy,
+          staging by default
+        type: choice
+        options:
+          - staging
+          - "production"
+        default: staging
+      dry-run:
+        type: boolean
+        required: true
+      note:
+        description: 'Shown in the # summary'
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+`,
+			dispatchable: true,
+			wantInputs: []WorkflowInput{
+				{
+					Name:        "environment",
+					Description: "Where to deploy,\nstaging by default",
+					Type:        "choice",
+					Default:     "staging",
+					Options:     []string{"staging", "production"},
+				},
+				{Name: "dry-run", Type: "boolean", Required: true},
+				{Name: "note", Description: "Shown in the # summary", Type: "string"},
+			},
+		},
+		{
+			name:         "flow mapping",
+			workflow:     "on: {push: {branches: [main]}, workflow_dispatch: {}}\n",
+			dispatchable: true,
+			wantInputs:   []WorkflowInput{},
+		},
+		{
+			name:         "flow mapping with inputs",
+			workflow:     "on: {workflow_dispatch: {inputs: {level: {type: choice, options: [low, high], required: true}, tag: {description: 'a, b'}}}}\n",
+			dispatchable: true,
+			wantInputs: []WorkflowInput{
+				{Name: "level", Type: "choice", Required: true, Options: []string{"low", "high"}},
+				{Name: "tag", Description: "a, b", Type: "string"},
+			},
+		},
+		{
+			name:         "flow mapping over several lines",
+			workflow:     "on: {\n  push: {},\n  workflow_dispatch: {inputs: {tag: {default: v1}}}\n}\njobs: {}\n",
+			dispatchable: true,
+			wantInputs:   []WorkflowInput{{Name: "tag", Type: "string", Default: "v1"}},
+		},
+		{
+			name:         "flow mapping nested in a block mapping",
+			workflow:     "on:\n  workflow_dispatch:\n    inputs: {tag: {required: false}}\n",
+			dispatchable: true,
+			wantInputs:   []WorkflowInput{{Name: "tag", Type: "string"}},
+		},
+		{
+			name:     "flow mapping without dispatch",
+			workflow: "on: {push: {branches: [main]}}\n",
+		},
+		{
+			name: "aliases and scalars over several lines",
+			workflow: `on:
+  workflow_call:
+    inputs: &inputs
+      "target: host":
+        description: Where the build
+          is deployed
+        default: false
+  workflow_dispatch:
+    inputs: *inputs
+`,
+			dispatchable: true,
+			wantInputs: []WorkflowInput{
+				{Name: "target: host", Description: "Where the build is deployed", Type: "string", Default: "false"},
+			},
+		},
+		{
+			name:     "invalid document",
+			workflow: "on: [workflow_dispatch\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inputs, dispatchable := parseDispatchInputs(test.workflow)
+			if dispatchable != test.dispatchable {
+				t.Errorf("dispatchable %v, want %v", dispatchable, test.dispatchable)
+			}
+			if !reflect.DeepEqual(inputs, test.wantInputs) {
+				t.Errorf("inputs\n%+v\nwant\n%+v", inputs, test.wantInputs)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package gitrepo
+
+import (
+	"bufio"
+	"os"
+	"sort"
+	"strings"
+)
+
+// gitConfig is the subset of a parsed git config file ghtui cares about,
+// keyed by "section.subsection.key" with every value of a repeated key kept in order.
+type gitConfig map[string][]string
+
+// readGitConfig parses the git config file at path. A missing file yields an empty config.
+//
+// Only the plain `[section "subsection"]` and `key = value` syntax is supported,
+// `include` directives are ignored.
+func readGitConfig(path string) (gitConfig, error) {
+	cfg := gitConfig{}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.LastIndex(line, "]")
+			if end == -1 {
+				continue
+			}
+			section = parseSectionHeader(line[1:end])
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = unquoteValue(strings.TrimSpace(value))
+		cfg[section+"."+key] = append(cfg[section+"."+key], value)
+	}
+	return cfg, scanner.Err()
+}
+
+// parseSectionHeader turns `remote "origin"` into `remote.origin`. Section names are
+// case-insensitive while subsection names are not.
+func parseSectionHeader(header string) string {
+	name, subsection, found := strings.Cut(header, " ")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !found {
+		// Deprecated `[section.subsection]` syntax.
+		return name
+	}
+	subsection = strings.TrimSpace(subsection)
+	subsection = strings.TrimPrefix(subsection, `"`)
+	subsection = strings.TrimSuffix(subsection, `"`)
+	return name + "." + subsection
+}
+
+// unquoteValue removes a trailing comment outside quotes, then the quotes
+// around value.
+func unquoteValue(value string) string {
+	quoted := false
+	for i, r := range value {
+		if r == '"' {
+			quoted = !quoted
+		} else if (r == '#' || r == ';') && !quoted {
+			value = strings.TrimSpace(value[:i])
+			break
+		}
+	}
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+	}
+	return value
+}
+
+// merge adds all values of other after the values already in cfg, mirroring how
+// git layers the global config below the repository config.
+func (cfg gitConfig) merge(other gitConfig) {
+	for key, values := range other {
+		cfg[key] = append(cfg[key], values...)
+	}
+}
+
+// subsections returns the subsection names of section that define key, sorted by name.
+func (cfg gitConfig) subsections(section string, key string) []string {
+	prefix := section + "."
+	suffix := "." + key
+	names := make([]string, 0)
+	for k := range cfg {
+		if strings.HasPrefix(k, prefix) && strings.HasSuffix(k, suffix) && len(k) > len(prefix)+len(suffix) {
+			names = append(names, k[len(prefix):len(k)-len(suffix)])
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,222 @@
+package gitrepo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var ErrNotARepository = errors.New("not inside a git repository")
+
+// A git remote pointing at a GitHub repository.
+type Remote struct {
+	// Name of the remote, e.g. "origin".
+	Name string
+	// URL of the remote after `insteadOf` rewrites.
+	URL string
+
+	Host  string
+	Owner string
+	Repo  string
+}
+
+// FullName returns the repository as `owner/name`.
+func (r Remote) FullName() string {
+	return r.Owner + "/" + r.Repo
+}
+
+// FindGitDir walks up from dir looking for a `.git` directory or file and returns
+// the directory holding the repository's config. Worktrees and submodules, whose
+// `.git` is a file pointing elsewhere, are followed.
+func FindGitDir(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		candidate := filepath.Join(dir, ".git")
+		info, err := os.Stat(candidate)
+		if err == nil {
+			if info.IsDir() {
+				return candidate, nil
+			}
+			return resolveGitFile(candidate)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNotARepository
+		}
+		dir = parent
+	}
+}
+
+// resolveGitFile follows a `.git` file containing `gitdir: <path>` and, for
+// worktrees, the `commondir` file to the directory holding the shared config.
+func resolveGitFile(path string) (string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(contents)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("%s: unrecognized .git file", path)
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+
+	commonDir, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir, nil
+	}
+	common := strings.TrimSpace(string(commonDir))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(gitDir, common)
+	}
+	return filepath.Clean(common), nil
+}
+
+// Remotes returns every remote of the repository in gitDir that points at one of
+// the given hosts, as decided by isGitHubHost. `url.<base>.insteadOf` rules from
+// the repository and the user's global git config are applied to remote URLs.
+func Remotes(gitDir string, isGitHubHost func(host string) bool) ([]Remote, error) {
+	cfg, err := readGitConfig(filepath.Join(gitDir, "config"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range globalConfigPaths() {
+		global, err := readGitConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		cfg.merge(global)
+	}
+
+	rewrites := insteadOfRules(cfg)
+	remotes := make([]Remote, 0)
+	for _, name := range cfg.subsections("remote", "url") {
+		urls := cfg["remote."+name+".url"]
+		rawURL := rewriteURL(urls[0], rewrites)
+		host, owner, repo, err := ParseURL(rawURL)
+		if err != nil || !isGitHubHost(host) {
+			continue
+		}
+		remotes = append(remotes, Remote{
+			Name:  name,
+			URL:   rawURL,
+			Host:  host,
+			Owner: owner,
+			Repo:  repo,
+		})
+	}
+	return remotes, nil
+}
+
+// PickRemote chooses the remote to use from remotes, going through preference
+// in order and returning the first remote whose name matches.
+//
+// If none match, the only remote is returned, or `ok` is false when the choice is ambiguous.
+func PickRemote(remotes []Remote, preference []string) (remote Remote, ok bool) {
+	for _, name := range preference {
+		for _, r := range remotes {
+			if r.Name == name {
+				return r, true
+			}
+		}
+	}
+	if len(remotes) == 1 {
+		return remotes[0], true
+	}
+	return Remote{}, false
+}
+
+func globalConfigPaths() []string {
+	paths := make([]string, 0, 2)
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	home, err := os.UserHomeDir()
+	if xdg == "" && err == nil {
+		xdg = filepath.Join(home, ".config")
+	}
+	if xdg != "" {
+		paths = append(paths, filepath.Join(xdg, "git", "config"))
+	}
+	if err == nil {
+		paths = append(paths, filepath.Join(home, ".gitconfig"))
+	}
+	return paths
+}
+
+type insteadOfRule struct {
+	prefix string
+	base   string
+}
+
+// insteadOfRules collects `url.<base>.insteadOf` rules, longest prefix first so
+// the most specific rule wins as it does in git.
+func insteadOfRules(cfg gitConfig) []insteadOfRule {
+	rules := make([]insteadOfRule, 0)
+	for _, base := range cfg.subsections("url", "insteadof") {
+		for _, prefix := range cfg["url."+base+".insteadof"] {
+			rules = append(rules, insteadOfRule{prefix: prefix, base: base})
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return len(rules[i].prefix) > len(rules[j].prefix)
+	})
+	return rules
+}
+
+func rewriteURL(rawURL string, rules []insteadOfRule) string {
+	for _, rule := range rules {
+		if strings.HasPrefix(rawURL, rule.prefix) {
+			return rule.base + rawURL[len(rule.prefix):]
+		}
+	}
+	return rawURL
+}
+
+// ParseURL extracts the host, owner and repository name from a git remote URL.
+//
+// Supports scp-like SSH (`git@github.com:owner/repo.git`), `ssh://`, `git://`,
+// `http://` and `https://` URLs.
+func ParseURL(rawURL string) (host string, owner string, repo string, err error) {
+	var path string
+	if !strings.Contains(rawURL, "://") {
+		// scp-like syntax, [user@]host:path
+		userHost, p, found := strings.Cut(rawURL, ":")
+		if !found {
+			return "", "", "", fmt.Errorf("unsupported remote URL %q", rawURL)
+		}
+		if i := strings.LastIndex(userHost, "@"); i != -1 {
+			userHost = userHost[i+1:]
+		}
+		host = userHost
+		path = p
+	} else {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return "", "", "", err
+		}
+		switch u.Scheme {
+		case "ssh", "git", "git+ssh", "ssh+git", "http", "https":
+		default:
+			return "", "", "", fmt.Errorf("unsupported remote URL scheme %q", u.Scheme)
+		}
+		host = u.Hostname()
+		path = u.Path
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("remote URL %q does not point at a repository", rawURL)
+	}
+	return strings.ToLower(host), parts[0], parts[1], nil
+}
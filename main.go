@@ -1,27 +1,103 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/alex-laycalvert/ghtui/app"
+	"github.com/alex-laycalvert/ghtui/config"
+	"github.com/alex-laycalvert/ghtui/ui/theme"
 )
 
+const usage = `Usage:
+  ghtui [flags] [[host/]owner/repo]
+  ghtui config [flags]
+
+Commands:
+  config    Print the config file location and the resolved settings
+
+Flags:
+`
+
 func main() {
-	if len(os.Args) < 3 {
-		fmt.Println("Usage: gtui <repo> <token>")
-		os.Exit(1)
+	flags := flag.NewFlagSet("ghtui", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", "", "path to the config file (default $XDG_CONFIG_HOME/ghtui/config.toml)")
+	repoName := flags.String("repo", "", "repository to open, as owner/name or host/owner/name")
+	hostname := flags.String("hostname", "", "GitHub host for repositories given without one (default github.com)")
+	themeName := flags.String("theme", "", "color theme, one of "+strings.Join(theme.Names(), ", "))
+	tokenFile := flags.String("token-file", "", "read the GitHub token from this file")
+
+	args := os.Args[1:]
+	command := ""
+	if len(args) > 0 && args[0] == "config" {
+		command = args[0]
+		args = args[1:]
 	}
-	repoName := os.Args[1]
-	token := os.Args[2]
+	flags.Parse(args)
 
-	app, err := app.New(token, repoName)
+	cfg, err := config.Load(*configPath)
+	checkErr(err)
+	if *themeName != "" {
+		cfg.Theme = *themeName
+	}
+	if *hostname != "" {
+		cfg.Host = *hostname
+	}
+	if *tokenFile != "" {
+		cfg.OverrideTokenFile(*tokenFile)
+	}
+
+	switch {
+	case command == "config":
+		printConfig(cfg)
+		return
+	case flags.NArg() > 1:
+		flags.Usage()
+		os.Exit(2)
+	case flags.NArg() == 1:
+		*repoName = flags.Arg(0)
+	}
+
+	app, err := app.New(cfg, *repoName)
 	checkErr(err)
 
 	err = app.Run()
 	checkErr(err)
 }
 
+func printConfig(cfg *config.Config) {
+	path := cfg.Path
+	if path == "" {
+		defaultPath, err := config.DefaultPath()
+		checkErr(err)
+		path = defaultPath + " (not found, using defaults)"
+	}
+	fmt.Println("config:", path)
+	fmt.Println("repo:  ", cfg.Repo)
+	fmt.Println("host:  ", cfg.Host)
+	fmt.Println("theme: ", cfg.Theme)
+	fmt.Println("pages: ", strings.Join(cfg.Pages, ", "))
+	fmt.Println("pinned:", strings.Join(cfg.Pinned, ", "))
+
+	for name := range cfg.Hosts {
+		host := cfg.HostSettings(name)
+		fmt.Printf("hosts.%s: api %s, uploads %s\n", name, host.APIURL, host.UploadURL)
+	}
+
+	_, source, err := cfg.ResolveToken(cfg.Host)
+	if err != nil {
+		fmt.Println("token: ", err)
+	} else {
+		fmt.Println("token: ", "from", source)
+	}
+}
+
 func checkErr(err error) {
 	if err != nil {
 		fmt.Println("Error:", err)
@@ -0,0 +1,116 @@
+package components
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ComposerScissors separates the text being composed from the instructions and
+// context below it, everything from it on is dropped when the editor is closed.
+const ComposerScissors = "<!-- ------------------------ >8 ------------------------ -->"
+
+// How to finish composing, below the instructions of every template.
+const composerUsage = "Everything from the line above on is ignored, do not change it." +
+	"\nQuit the editor without saving to cancel."
+
+type ComposerDoneMsg struct {
+	ID string
+	// The composed text with the instructions and context removed, which may
+	// be empty.
+	Text string
+	// Whether the editor was quit without saving, in which case there is no text.
+	Cancelled bool
+	Err       error
+}
+
+// ComposerTemplate returns the initial contents of the editor: text to start
+// from, then below the scissors line instructions as comments and context.
+func ComposerTemplate(text string, instructions string, context string) string {
+	template := strings.Builder{}
+	template.WriteString(text)
+	template.WriteString("\n\n")
+	template.WriteString(ComposerScissors + "\n")
+	for _, line := range strings.Split(instructions+"\n"+composerUsage, "\n") {
+		template.WriteString("<!-- " + line + " -->\n")
+	}
+	if context != "" {
+		template.WriteString("\n" + context + "\n")
+	}
+	return template.String()
+}
+
+// Compose suspends the program and opens `$VISUAL` or `$EDITOR`, falling back
+// to vi, on a temporary file holding template. Once the editor exits it sends
+// `ComposerDoneMsg` with the given id and the text written, or cancelled when
+// the file was left unsaved.
+func Compose(id string, template string) tea.Cmd {
+	file, err := os.CreateTemp("", "ghtui-*.md")
+	if err != nil {
+		return composerDone(id, "", err)
+	}
+	path := file.Name()
+	_, err = file.WriteString(template)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	var written os.FileInfo
+	if err == nil {
+		written, err = os.Stat(path)
+	}
+	if err != nil {
+		os.Remove(path)
+		return composerDone(id, "", err)
+	}
+
+	editor := strings.Fields(editorCommand())
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return ComposerDoneMsg{ID: id, Err: err}
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return ComposerDoneMsg{ID: id, Err: err}
+		}
+		saved, err := os.Stat(path)
+		if err != nil {
+			return ComposerDoneMsg{ID: id, Err: err}
+		}
+		if string(content) == template && saved.ModTime().Equal(written.ModTime()) {
+			return ComposerDoneMsg{ID: id, Cancelled: true}
+		}
+		return ComposerDoneMsg{ID: id, Text: stripComposerTemplate(string(content))}
+	})
+}
+
+func composerDone(id string, text string, err error) tea.Cmd {
+	return func() tea.Msg {
+		return ComposerDoneMsg{ID: id, Text: text, Err: err}
+	}
+}
+
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
+// stripComposerTemplate drops the scissors line and what follows it, returning
+// the text above trimmed. Comments the user wrote are kept.
+func stripComposerTemplate(content string) string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == ComposerScissors {
+			break
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,44 @@
+package components
+
+import "testing"
+
+func TestStripComposerTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		written string
+		want    string
+	}{
+		{
+			name:    "untouched template",
+			written: ComposerTemplate("Body", "Write the body above.", "# Context"),
+			want:    "Body",
+		},
+		{
+			name:    "comments of the user",
+			written: ComposerTemplate("<!-- keep me -->\nBody", "Write the body above.", ""),
+			want:    "<!-- keep me -->\nBody",
+		},
+		{
+			name:    "emptied text",
+			written: ComposerTemplate("", "Write the body above.", "# Context"),
+			want:    "",
+		},
+		{
+			name:    "windows line endings",
+			written: "Body\r\n\r\n" + ComposerScissors + "\r\n",
+			want:    "Body",
+		},
+		{
+			name:    "scissors line deleted by the user",
+			written: "Body\n<!-- an instruction -->\n",
+			want:    "Body\n<!-- an instruction -->",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := stripComposerTemplate(test.written); got != test.want {
+				t.Errorf("stripComposerTemplate() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type diffLineKind int
+
+const (
+	contextLine diffLineKind = iota
+	addedLine
+	removedLine
+	// "\ No newline at end of file"
+	noNewlineLine
+)
+
+type diffLine struct {
+	kind      diffLineKind
+	text      string
+	oldNumber int
+	newNumber int
+	// Runes of text that differ from the line it replaces or is replaced by,
+	// nil when the whole line is changed.
+	emphasis []bool
+}
+
+type diffHunk struct {
+	header string
+	lines  []diffLine
+}
+
+var (
+	hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+	addedLineColor       = lipgloss.AdaptiveColor{Light: "#e6ffec", Dark: "#0f2e1a"}
+	addedEmphasisColor   = lipgloss.AdaptiveColor{Light: "#abf2bc", Dark: "#1d5c33"}
+	removedLineColor     = lipgloss.AdaptiveColor{Light: "#ffebe9", Dark: "#3b1419"}
+	removedEmphasisColor = lipgloss.AdaptiveColor{Light: "#ffc1c0", Dark: "#6e2128"}
+	diffGutterStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// Width tabs are expanded to, so intraline highlights line up with what is shown.
+const diffTabWidth = 4
+
+// Line pairs with more tokens than this are not diffed word by word.
+const maxIntralineTokens = 300
+
+// parsePatch parses the hunks of a unified diff of a single file, as found in
+// the `patch` of a file GitHub reports as changed.
+func parsePatch(patch string) []diffHunk {
+	hunks := make([]diffHunk, 0)
+	var oldNumber, newNumber int
+	for _, raw := range strings.Split(patch, "\n") {
+		if match := hunkHeaderPattern.FindStringSubmatch(raw); match != nil {
+			oldNumber, _ = strconv.Atoi(match[1])
+			newNumber, _ = strconv.Atoi(match[2])
+			hunks = append(hunks, diffHunk{header: raw})
+			continue
+		}
+		if len(hunks) == 0 {
+			continue
+		}
+
+		hunk := &hunks[len(hunks)-1]
+		switch {
+		case strings.HasPrefix(raw, "+"):
+			hunk.lines = append(hunk.lines, diffLine{kind: addedLine, text: expandTabs(raw[1:]), newNumber: newNumber})
+			newNumber++
+		case strings.HasPrefix(raw, "-"):
+			hunk.lines = append(hunk.lines, diffLine{kind: removedLine, text: expandTabs(raw[1:]), oldNumber: oldNumber})
+			oldNumber++
+		case strings.HasPrefix(raw, `\`):
+			hunk.lines = append(hunk.lines, diffLine{kind: noNewlineLine, text: raw})
+		default:
+			text := ""
+			if raw != "" {
+				text = expandTabs(raw[1:])
+			}
+			hunk.lines = append(hunk.lines, diffLine{kind: contextLine, text: text, oldNumber: oldNumber, newNumber: newNumber})
+			oldNumber++
+			newNumber++
+		}
+	}
+
+	for i := range hunks {
+		markIntraline(hunks[i].lines)
+	}
+	return hunks
+}
+
+// markIntraline pairs the removed and added lines of each change, first with
+// first, and marks the words that differ between them.
+func markIntraline(lines []diffLine) {
+	for i := 0; i < len(lines); {
+		if lines[i].kind != removedLine {
+			i++
+			continue
+		}
+		start := i
+		for i < len(lines) && lines[i].kind == removedLine {
+			i++
+		}
+		middle := i
+		for i < len(lines) && lines[i].kind == addedLine {
+			i++
+		}
+
+		removed, added := lines[start:middle], lines[middle:i]
+		for j := 0; j < min(len(removed), len(added)); j++ {
+			removed[j].emphasis, added[j].emphasis = wordDiff(removed[j].text, added[j].text)
+		}
+	}
+}
+
+// wordDiff returns the runes of a and b outside their longest common sequence
+// of words. Both are nil when the lines have no word in common.
+func wordDiff(a string, b string) ([]bool, []bool) {
+	aTokens, bTokens := diffTokens(a), diffTokens(b)
+	if len(aTokens) > maxIntralineTokens || len(bTokens) > maxIntralineTokens {
+		return nil, nil
+	}
+
+	// lengths[i][j] is the length of the longest common sequence of aTokens[i:] and bTokens[j:].
+	lengths := make([][]int, len(aTokens)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(bTokens)+1)
+	}
+	for i := len(aTokens) - 1; i >= 0; i-- {
+		for j := len(bTokens) - 1; j >= 0; j-- {
+			if aTokens[i] == bTokens[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	aCommon := make([]bool, len(aTokens))
+	bCommon := make([]bool, len(bTokens))
+	shared := false
+	for i, j := 0, 0; i < len(aTokens) && j < len(bTokens); {
+		switch {
+		case aTokens[i] == bTokens[j]:
+			aCommon[i], bCommon[j] = true, true
+			shared = shared || strings.TrimSpace(aTokens[i]) != ""
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	if !shared {
+		return nil, nil
+	}
+	return tokenEmphasis(aTokens, aCommon), tokenEmphasis(bTokens, bCommon)
+}
+
+// diffTokens splits text into words and single other characters.
+func diffTokens(text string) []string {
+	tokens := make([]string, 0)
+	word := strings.Builder{}
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			word.WriteRune(r)
+			continue
+		}
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+		tokens = append(tokens, string(r))
+	}
+	if word.Len() > 0 {
+		tokens = append(tokens, word.String())
+	}
+	return tokens
+}
+
+func tokenEmphasis(tokens []string, common []bool) []bool {
+	emphasis := make([]bool, 0)
+	for i, token := range tokens {
+		for range []rune(token) {
+			emphasis = append(emphasis, !common[i])
+		}
+	}
+	return emphasis
+}
+
+func expandTabs(text string) string {
+	if !strings.Contains(text, "\t") {
+		return text
+	}
+	expanded := strings.Builder{}
+	column := 0
+	for _, r := range text {
+		if r == '\t' {
+			spaces := diffTabWidth - column%diffTabWidth
+			expanded.WriteString(strings.Repeat(" ", spaces))
+			column += spaces
+			continue
+		}
+		expanded.WriteRune(r)
+		column++
+	}
+	return expanded.String()
+}
+
+// renderCode renders text cut or padded to width, each rune in its syntax
+// color, on background and emphasized runes on emphasisBackground.
+func renderCode(
+	text string,
+	colors []string,
+	emphasis []bool,
+	background lipgloss.TerminalColor,
+	emphasisBackground lipgloss.TerminalColor,
+	width int,
+) string {
+	runes := []rune(text)
+	if len(runes) > width {
+		runes = runes[:max(0, width)]
+	}
+
+	rendered := strings.Builder{}
+	for start := 0; start < len(runes); {
+		color, emphasized := runeStyle(colors, emphasis, start)
+		end := start + 1
+		for end < len(runes) {
+			nextColor, nextEmphasized := runeStyle(colors, emphasis, end)
+			if nextColor != color || nextEmphasized != emphasized {
+				break
+			}
+			end++
+		}
+
+		style := lipgloss.NewStyle().Background(background)
+		if emphasized {
+			style = style.Background(emphasisBackground)
+		}
+		if color != "" {
+			style = style.Foreground(lipgloss.Color(color))
+		}
+		rendered.WriteString(style.Render(string(runes[start:end])))
+		start = end
+	}
+	if padding := width - len(runes); padding > 0 {
+		rendered.WriteString(lipgloss.NewStyle().Background(background).Render(strings.Repeat(" ", padding)))
+	}
+	return rendered.String()
+}
+
+func runeStyle(colors []string, emphasis []bool, i int) (string, bool) {
+	color := ""
+	if i < len(colors) {
+		color = colors[i]
+	}
+	return color, i < len(emphasis) && emphasis[i]
+}
+
+// lineNumber formats a line number for the gutter, blank for 0.
+func lineNumber(number int) string {
+	if number == 0 {
+		return fmt.Sprintf("%4s", "")
+	}
+	return fmt.Sprintf("%4d", number)
+}
@@ -0,0 +1,176 @@
+package components
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  []diffHunk
+	}{
+		{
+			name:  "change between context",
+			patch: "@@ -10,3 +10,3 @@ func main() {\n \tfmt.Println(1)\n-\treturn\n+\tos.Exit(0)\n }",
+			want: []diffHunk{{
+				header: "@@ -10,3 +10,3 @@ func main() {",
+				lines: []diffLine{
+					{kind: contextLine, text: "    fmt.Println(1)", oldNumber: 10, newNumber: 10},
+					{kind: removedLine, text: "    return", oldNumber: 11},
+					{kind: addedLine, text: "    os.Exit(0)", newNumber: 11},
+					{kind: contextLine, text: "}", oldNumber: 12, newNumber: 12},
+				},
+			}},
+		},
+		{
+			name:  "several hunks",
+			patch: "@@ -1,2 +1,3 @@\n a\n+b\n c\n@@ -20 +21,0 @@\n-z",
+			want: []diffHunk{
+				{
+					header: "@@ -1,2 +1,3 @@",
+					lines: []diffLine{
+						{kind: contextLine, text: "a", oldNumber: 1, newNumber: 1},
+						{kind: addedLine, text: "b", newNumber: 2},
+						{kind: contextLine, text: "c", oldNumber: 2, newNumber: 3},
+					},
+				},
+				{
+					header: "@@ -20 +21,0 @@",
+					lines:  []diffLine{{kind: removedLine, text: "z", oldNumber: 20}},
+				},
+			},
+		},
+		{
+			name:  "no newline at the end",
+			patch: "@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+a",
+			want: []diffHunk{{
+				header: "@@ -1 +1 @@",
+				lines: []diffLine{
+					{kind: removedLine, text: "a", oldNumber: 1},
+					{kind: noNewlineLine, text: `\ No newline at end of file`},
+					{kind: addedLine, text: "a", newNumber: 1},
+				},
+			}},
+		},
+		{
+			name:  "empty context line and file header",
+			patch: "diff --git a/x b/x\n--- a/x\n+++ b/x\n@@ -3,2 +3,2 @@\n\n x",
+			want: []diffHunk{{
+				header: "@@ -3,2 +3,2 @@",
+				lines: []diffLine{
+					{kind: contextLine, text: "", oldNumber: 3, newNumber: 3},
+					{kind: contextLine, text: "x", oldNumber: 4, newNumber: 4},
+				},
+			}},
+		},
+		{
+			name:  "no hunks",
+			patch: "",
+			want:  []diffHunk{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := parsePatch(test.patch)
+			// Emphasis is tested with wordDiff.
+			for i := range got {
+				for j := range got[i].lines {
+					got[i].lines[j].emphasis = nil
+				}
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("hunks\n%+v\nwant\n%+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParsePatchPairsChangedLines(t *testing.T) {
+	hunks := parsePatch("@@ -1,3 +1,2 @@\n-a := 1\n-b := 2\n+a := 3\n c")
+	lines := hunks[0].lines
+	if got := marks(lines[0].emphasis); got != "     ^" {
+		t.Errorf("first removed line marked %q, want its value", got)
+	}
+	if got := marks(lines[2].emphasis); got != "     ^" {
+		t.Errorf("added line marked %q, want its value", got)
+	}
+	if lines[1].emphasis != nil {
+		t.Errorf("second removed line marked %q, want it unpaired", marks(lines[1].emphasis))
+	}
+}
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		a     string
+		b     string
+		wantA string
+		wantB string
+	}{
+		{
+			name:  "one word changed",
+			a:     "return nil, err",
+			b:     "return user, err",
+			wantA: "       ^^^     ",
+			wantB: "       ^^^^     ",
+		},
+		{
+			name:  "word added",
+			a:     "foo(a)",
+			b:     "foo(a, b)",
+			wantA: "      ",
+			wantB: "     ^^^ ",
+		},
+		{
+			name:  "identical lines",
+			a:     "x := 1",
+			b:     "x := 1",
+			wantA: "      ",
+			wantB: "      ",
+		},
+		{
+			name: "nothing but spaces in common",
+			a:    "alpha beta",
+			b:    "gamma delta",
+		},
+		{
+			name:  "multibyte runes",
+			a:     "héllo wörld",
+			b:     "héllo world",
+			wantA: "      ^^^^^",
+			wantB: "      ^^^^^",
+		},
+		{
+			name: "too long to compare",
+			a:    strings.Repeat("a ", maxIntralineTokens),
+			b:    strings.Repeat("a ", maxIntralineTokens) + "b",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, b := wordDiff(test.a, test.b)
+			if got := marks(a); got != test.wantA {
+				t.Errorf("a marked\n%q\n%q, want\n%q", test.a, got, test.wantA)
+			}
+			if got := marks(b); got != test.wantB {
+				t.Errorf("b marked\n%q\n%q, want\n%q", test.b, got, test.wantB)
+			}
+		})
+	}
+}
+
+// marks renders emphasis as ^ under the emphasized runes, empty for nil.
+func marks(emphasis []bool) string {
+	text := strings.Builder{}
+	for _, emphasized := range emphasis {
+		if emphasized {
+			text.WriteString("^")
+		} else {
+			text.WriteString(" ")
+		}
+	}
+	return text.String()
+}
@@ -0,0 +1,921 @@
+package components
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/go-github/v69/github"
+	"github.com/google/uuid"
+
+	"github.com/alex-laycalvert/ghtui/ui/highlight"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+// Patches longer than this are only rendered once asked for.
+const maxRenderedPatchLines = 2000
+
+var (
+	diffTreeStyle = lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder()).
+			BorderForeground(lipgloss.Color("62")).
+			UnsetBorderTop().
+			UnsetBorderLeft().
+			UnsetBorderBottom()
+	diffFileHeaderStyle = lipgloss.NewStyle().Bold(true)
+	diffHunkHeaderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	diffNoticeStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true)
+	diffHelpStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	treeDirectoryStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	// Selected file while the diff rather than the tree has focus.
+	treeSelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	diffCursorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	annotationStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	fileStatusStyles  = map[string]lipgloss.Style{
+		"added":    lipgloss.NewStyle().Foreground(lipgloss.Color("42")),
+		"removed":  lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+		"modified": lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+		"renamed":  lipgloss.NewStyle().Foreground(lipgloss.Color("39")),
+	}
+)
+
+// DiffViewerModel shows the files changed by a pull request or commit: a file
+// tree on the left and the selected file's diff, syntax highlighted with
+// changed words emphasized, on the right.
+//
+// Tab moves between the tree and the diff, s toggles between the unified and
+// split layouts, z folds the hunk under the cursor and Z all of them.
+// A file's diff is only parsed once it is selected. Diffs longer than
+// `maxRenderedPatchLines` are rendered when L is pressed, and for files whose
+// patch GitHub left out L sends `DiffViewerLoadPatchMsg` for the owner to
+// answer with `DiffViewerSetPatchMsg`.
+//
+// When the files are commentable, c on the line under the cursor, or on the
+// range selected with v, sends `DiffViewerCommentMsg`, and S does the same
+// for a suggestion. In the split layout, < and > choose whether the old lines
+// next to new ones are commented on instead of the new ones. Annotations set with `DiffViewerSetAnnotationsMsg` are
+// shown under the lines they are on, and keys the viewer does not handle
+// pressed on one are sent back as `DiffViewerAnnotationKeyMsg`.
+type DiffViewerModel struct {
+	id     string
+	width  int
+	height int
+
+	files []*github.CommitFile
+	tree  []diffTreeEntry
+	// Index into tree of the selected file.
+	selected    int
+	treeOffset  int
+	diffFocused bool
+	split       bool
+	commentable bool
+	// Whether the old lines of split rows are commented on.
+	oldSide bool
+	style   *chroma.Style
+	// Annotations by file name.
+	annotations map[string][]DiffAnnotation
+
+	// Folded hunks, rendered large diffs, patches being loaded and errors
+	// loading them, by file name.
+	folded      map[string]map[int]bool
+	expanded    map[string]bool
+	loading     map[string]bool
+	patchErrors map[string]string
+
+	// Rendered diff of the selected file, the first row shown, the row under
+	// the cursor, the other end of the selected range, -1 without one, and the
+	// row each hunk's header is on.
+	rows     []diffRow
+	offset   int
+	cursor   int
+	anchor   int
+	hunkRows []int
+}
+
+// A rendered row of a diff.
+type diffRow struct {
+	text string
+	// Side and number of the line the row shows, 0 if it is not a line of
+	// code. In the split layout, the new line if there is one.
+	side string
+	line int
+	code string
+	// In the split layout, number and code of the old line next to the new
+	// one, 0 if there is none.
+	oldLine int
+	oldCode string
+	// ID of the annotation the row is part of.
+	annotation string
+}
+
+// A line of the file tree, either a directory or a file.
+type diffTreeEntry struct {
+	text string
+	// nil for directories.
+	file *github.CommitFile
+}
+
+type DiffViewerSetFilesMsg struct {
+	Files []*github.CommitFile
+	// Whether lines of the files can be commented on.
+	Commentable bool
+}
+
+// A note shown under a line of a file's diff, such as a review thread.
+type DiffAnnotation struct {
+	ID   string
+	Path string
+	// "LEFT" for lines of the old file, "RIGHT" for lines of the new one.
+	Side string
+	// Annotations on 0, or on lines the diff does not show, are shown after
+	// the file's hunks.
+	Line  int
+	Lines []string
+}
+
+// Replaces the annotations of all files.
+type DiffViewerSetAnnotationsMsg struct {
+	Annotations []DiffAnnotation
+}
+
+// Sent by the diff viewer to comment on a line or a range of lines.
+type DiffViewerCommentMsg struct {
+	ID   string
+	Path string
+	// Last line of the range, "LEFT" for lines of the old file and "RIGHT"
+	// for lines of the new one.
+	Side string
+	Line int
+	// First line of the range, 0 for a single line.
+	StartSide string
+	StartLine int
+	// Code of the new lines in the range.
+	Code []string
+	// Whether the comment suggests a change to the lines.
+	Suggestion bool
+}
+
+// Sent by the diff viewer for a key it does not handle, pressed on an annotation.
+type DiffViewerAnnotationKeyMsg struct {
+	ID           string
+	AnnotationID string
+	Key          string
+}
+
+// Sent by the diff viewer for a file whose patch GitHub left out.
+type DiffViewerLoadPatchMsg struct {
+	ID   string
+	File *github.CommitFile
+}
+
+type DiffViewerSetPatchMsg struct {
+	Filename string
+	Patch    string
+	Err      error
+}
+
+func NewDiffViewerComponent(width int, height int) DiffViewerModel {
+	return DiffViewerModel{
+		id:          "diffViewer_" + uuid.NewString(),
+		width:       width,
+		height:      height,
+		style:       highlight.Style(),
+		annotations: map[string][]DiffAnnotation{},
+		anchor:      -1,
+		folded:      map[string]map[int]bool{},
+		expanded:    map[string]bool{},
+		loading:     map[string]bool{},
+		patchErrors: map[string]string{},
+	}
+}
+
+func (m DiffViewerModel) ID() string {
+	return m.id
+}
+
+func (m DiffViewerModel) Init() tea.Cmd {
+	return nil
+}
+
+// CapturesInput keeps tab, which moves between the tree and the diff, from
+// switching pages.
+func (m DiffViewerModel) CapturesInput() bool {
+	return true
+}
+
+func (m DiffViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case utils.UpdateSizeMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+
+		if msg.Width > 0 {
+			m.width = msg.Width
+		}
+		if msg.Height > 0 {
+			m.height = msg.Height
+		}
+		m.render()
+		return m, nil
+	case DiffViewerSetFilesMsg:
+		m.files = msg.Files
+		m.commentable = msg.Commentable
+		m.tree = buildDiffTree(msg.Files)
+		m.selected = -1
+		m.selectFile(1)
+		m.treeOffset = 0
+		m.diffFocused = false
+		m.folded = map[string]map[int]bool{}
+		m.expanded = map[string]bool{}
+		m.loading = map[string]bool{}
+		m.patchErrors = map[string]string{}
+		m.annotations = map[string][]DiffAnnotation{}
+		m.offset = 0
+		m.cursor = 0
+		m.anchor = -1
+		m.render()
+		return m, nil
+	case DiffViewerSetAnnotationsMsg:
+		m.annotations = map[string][]DiffAnnotation{}
+		for _, annotation := range msg.Annotations {
+			m.annotations[annotation.Path] = append(m.annotations[annotation.Path], annotation)
+		}
+		m.render()
+		return m, nil
+	case DiffViewerSetPatchMsg:
+		delete(m.loading, msg.Filename)
+		for _, file := range m.files {
+			if file.GetFilename() != msg.Filename {
+				continue
+			}
+			if msg.Err != nil {
+				m.patchErrors[msg.Filename] = msg.Err.Error()
+			} else {
+				file.Patch = &msg.Patch
+				m.expanded[msg.Filename] = true
+			}
+		}
+		m.render()
+		return m, nil
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+func (m DiffViewerModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	file := m.selectedFile()
+	half := max(1, m.diffHeight()/2)
+
+	switch keypress := msg.String(); keypress {
+	case "s":
+		m.split = !m.split
+		m.render()
+		return m, nil
+	case "L":
+		if file == nil {
+			return m, nil
+		}
+		if file.Patch == nil && file.GetChanges() > 0 {
+			if m.loading[file.GetFilename()] {
+				return m, nil
+			}
+			m.loading[file.GetFilename()] = true
+			delete(m.patchErrors, file.GetFilename())
+			m.render()
+			id := m.id
+			return m, func() tea.Msg {
+				return DiffViewerLoadPatchMsg{ID: id, File: file}
+			}
+		}
+		m.expanded[file.GetFilename()] = true
+		m.render()
+		return m, nil
+	case "tab":
+		m.diffFocused = !m.diffFocused
+		return m, nil
+	case "]":
+		m.moveSelection(1)
+		return m, nil
+	case "[":
+		m.moveSelection(-1)
+		return m, nil
+	}
+
+	if !m.diffFocused {
+		switch msg.String() {
+		case "j", "down":
+			m.moveSelection(1)
+		case "k", "up":
+			m.moveSelection(-1)
+		case "enter", "l", "right":
+			m.diffFocused = true
+		}
+		return m, nil
+	}
+
+	switch keypress := msg.String(); keypress {
+	case "j", "down":
+		m.moveCursor(1)
+	case "k", "up":
+		m.moveCursor(-1)
+	case "ctrl+d", "pgdown":
+		m.scroll(half)
+		m.moveCursor(half)
+	case "ctrl+u", "pgup":
+		m.scroll(-half)
+		m.moveCursor(-half)
+	case "g":
+		m.moveCursor(-len(m.rows))
+	case "G":
+		m.moveCursor(len(m.rows))
+	case "n":
+		for _, row := range m.hunkRows {
+			if row > m.cursor {
+				m.jumpTo(row)
+				break
+			}
+		}
+	case "N":
+		for i := len(m.hunkRows) - 1; i >= 0; i-- {
+			if m.hunkRows[i] < m.cursor {
+				m.jumpTo(m.hunkRows[i])
+				break
+			}
+		}
+	case "z":
+		hunk := m.currentHunk()
+		if file == nil || hunk < 0 {
+			return m, nil
+		}
+		folded := m.foldedHunks(file)
+		folded[hunk] = !folded[hunk]
+		m.anchor = -1
+		m.render()
+		m.jumpTo(m.hunkRows[hunk])
+	case "Z":
+		if file == nil {
+			return m, nil
+		}
+		folded := m.foldedHunks(file)
+		foldAll := false
+		for i := range m.hunkRows {
+			if !folded[i] {
+				foldAll = true
+			}
+		}
+		for i := range m.hunkRows {
+			folded[i] = foldAll
+		}
+		m.offset = 0
+		m.cursor = 0
+		m.anchor = -1
+		m.render()
+	case "v":
+		if m.anchor >= 0 {
+			m.anchor = -1
+		} else if m.commentable && m.cursorRow().line > 0 {
+			m.anchor = m.cursor
+		}
+	case "c", "S":
+		if !m.commentable || file == nil {
+			return m, nil
+		}
+		comment, ok := m.selectedComment()
+		if !ok {
+			return m, nil
+		}
+		comment.ID = m.id
+		comment.Path = file.GetFilename()
+		comment.Suggestion = keypress == "S"
+		m.anchor = -1
+		return m, func() tea.Msg {
+			return comment
+		}
+	case "<", ">":
+		m.oldSide = keypress == "<"
+	case "h", "left":
+		m.diffFocused = false
+	default:
+		annotation := m.cursorRow().annotation
+		if annotation == "" {
+			return m, nil
+		}
+		id := m.id
+		return m, func() tea.Msg {
+			return DiffViewerAnnotationKeyMsg{ID: id, AnnotationID: annotation, Key: keypress}
+		}
+	}
+	return m, nil
+}
+
+func (m DiffViewerModel) View() string {
+	if len(m.files) == 0 {
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			Render(diffNoticeStyle.Render("No files changed."))
+	}
+
+	treeWidth := m.treeWidth()
+	height := m.diffHeight()
+
+	treeLines := make([]string, 0, height)
+	for i := m.treeOffset; i < len(m.tree) && i < m.treeOffset+height; i++ {
+		entry := m.tree[i]
+		line := lipgloss.NewStyle().MaxWidth(treeWidth).Render(entry.text)
+		switch {
+		case i == m.selected && !m.diffFocused:
+			line = selectedListItemStyle.Width(treeWidth).Render(line)
+		case i == m.selected:
+			line = treeSelectedStyle.Render(line)
+		}
+		treeLines = append(treeLines, line)
+	}
+	tree := diffTreeStyle.
+		Width(treeWidth).
+		Height(height).
+		Render(strings.Join(treeLines, "\n"))
+
+	end := min(len(m.rows), m.offset+height)
+	diffLines := make([]string, 0, height)
+	first, last := m.selectedRange()
+	for i := min(m.offset, end); i < end; i++ {
+		marker := " "
+		switch {
+		case !m.diffFocused:
+		case i == m.cursor:
+			marker = diffCursorStyle.Render("▌")
+		case i >= first && i <= last:
+			marker = diffCursorStyle.Render("│")
+		}
+		diffLines = append(diffLines, marker+m.rows[i].text)
+	}
+	diff := lipgloss.NewStyle().
+		Width(m.diffWidth()).
+		Height(height).
+		Render(strings.Join(diffLines, "\n"))
+
+	layout := "split"
+	if m.split {
+		layout = "unified"
+		if m.commentable && m.oldSide {
+			layout += " · > comment on new lines"
+		} else if m.commentable {
+			layout += " · < comment on old lines"
+		}
+	}
+	help := fmt.Sprintf("%d files · tab tree/diff · [ ] file · n/N hunk · z/Z fold · s %s · L load large diff", len(m.files), layout)
+	if m.commentable {
+		help = fmt.Sprintf("%d files · tab tree/diff · [ ] file · n/N hunk · z/Z fold · s %s · v range · c comment · S suggest", len(m.files), layout)
+	}
+	help = diffHelpStyle.Width(m.width).MaxHeight(1).Render(help)
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, tree, diff),
+		help,
+	)
+}
+
+func (m DiffViewerModel) treeWidth() int {
+	return min(40, max(20, m.width/4))
+}
+
+// diffWidth is the width of the diff, next to the tree and its border.
+func (m DiffViewerModel) diffWidth() int {
+	return max(0, m.width-m.treeWidth()-diffTreeStyle.GetHorizontalFrameSize())
+}
+
+// codeWidth is the width of the diff's rows, next to the cursor.
+func (m DiffViewerModel) codeWidth() int {
+	return max(0, m.diffWidth()-1)
+}
+
+// diffHeight is the height of the tree and diff, above the help line.
+func (m DiffViewerModel) diffHeight() int {
+	return max(0, m.height-1)
+}
+
+func (m DiffViewerModel) selectedFile() *github.CommitFile {
+	if m.selected < 0 || m.selected >= len(m.tree) {
+		return nil
+	}
+	return m.tree[m.selected].file
+}
+
+// selectFile moves the selection to the next file of the tree in direction,
+// skipping directories. It stays put if there is none.
+func (m *DiffViewerModel) selectFile(direction int) bool {
+	for i := m.selected + direction; i >= 0 && i < len(m.tree); i += direction {
+		if m.tree[i].file != nil {
+			m.selected = i
+			return true
+		}
+	}
+	return false
+}
+
+func (m *DiffViewerModel) moveSelection(direction int) {
+	if !m.selectFile(direction) {
+		return
+	}
+
+	height := m.diffHeight()
+	if m.selected < m.treeOffset {
+		m.treeOffset = m.selected
+		// Keep the directory the file is in visible.
+		for m.treeOffset > 0 && m.tree[m.treeOffset-1].file == nil {
+			m.treeOffset--
+		}
+	} else if m.selected >= m.treeOffset+height {
+		m.treeOffset = m.selected - height + 1
+	}
+	m.offset = 0
+	m.cursor = 0
+	m.anchor = -1
+	m.render()
+}
+
+func (m *DiffViewerModel) scroll(lines int) {
+	m.offset = max(0, min(m.offset+lines, len(m.rows)-m.diffHeight()))
+}
+
+// moveCursor moves the cursor by rows, scrolling to keep it visible.
+func (m *DiffViewerModel) moveCursor(rows int) {
+	m.cursor = max(0, min(m.cursor+rows, len(m.rows)-1))
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if height := m.diffHeight(); m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+}
+
+// jumpTo moves the cursor to row and scrolls it to the top.
+func (m *DiffViewerModel) jumpTo(row int) {
+	m.cursor = row
+	m.offset = row
+	m.scroll(0)
+	m.moveCursor(0)
+}
+
+func (m DiffViewerModel) cursorRow() diffRow {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return diffRow{}
+	}
+	return m.rows[m.cursor]
+}
+
+// selectedRange returns the first and last rows of the selected range, the
+// cursor's row without one.
+func (m DiffViewerModel) selectedRange() (int, int) {
+	if m.anchor < 0 {
+		return m.cursor, m.cursor
+	}
+	return min(m.anchor, m.cursor), max(m.anchor, m.cursor)
+}
+
+// selectedComment returns the lines of code a comment would be on, false if
+// the selection has none.
+func (m DiffViewerModel) selectedComment() (DiffViewerCommentMsg, bool) {
+	first, last := m.selectedRange()
+	comment := DiffViewerCommentMsg{}
+	for i := first; i <= last && i < len(m.rows); i++ {
+		row := m.rows[i]
+		if m.split && m.oldSide && row.oldLine > 0 {
+			row.side, row.line, row.code = "LEFT", row.oldLine, row.oldCode
+		}
+		if row.line == 0 {
+			continue
+		}
+		if comment.Line == 0 {
+			comment.StartSide, comment.StartLine = row.side, row.line
+		}
+		comment.Side, comment.Line = row.side, row.line
+		if row.side == "RIGHT" {
+			comment.Code = append(comment.Code, row.code)
+		}
+	}
+	if comment.Line == 0 {
+		return comment, false
+	}
+	if comment.StartSide == comment.Side && comment.StartLine == comment.Line {
+		comment.StartSide, comment.StartLine = "", 0
+	}
+	return comment, true
+}
+
+// currentHunk returns the hunk the cursor is in, -1 if the diff has none.
+func (m DiffViewerModel) currentHunk() int {
+	current := -1
+	for i, row := range m.hunkRows {
+		if row > m.cursor && current >= 0 {
+			break
+		}
+		current = i
+	}
+	return current
+}
+
+func (m DiffViewerModel) foldedHunks(file *github.CommitFile) map[int]bool {
+	folded, ok := m.folded[file.GetFilename()]
+	if !ok {
+		folded = map[int]bool{}
+		m.folded[file.GetFilename()] = folded
+	}
+	return folded
+}
+
+// render renders the diff of the selected file into rows.
+func (m *DiffViewerModel) render() {
+	m.rows = nil
+	m.hunkRows = nil
+	defer func() {
+		m.moveCursor(0)
+		m.scroll(0)
+		if m.anchor >= len(m.rows) {
+			m.anchor = -1
+		}
+	}()
+
+	file := m.selectedFile()
+	if file == nil {
+		return
+	}
+
+	width := m.codeWidth()
+	name := file.GetFilename()
+	header := diffFileHeaderStyle.Render(name)
+	if previous := file.GetPreviousFilename(); previous != "" {
+		header += diffHelpStyle.Render(" ← " + previous)
+	}
+	header += diffHelpStyle.Render(fmt.Sprintf(" · %s · +%d −%d", file.GetStatus(), file.GetAdditions(), file.GetDeletions()))
+	m.rows = append(m.rows, diffRow{text: lipgloss.NewStyle().MaxWidth(width).Render(header)}, diffRow{})
+
+	notice := ""
+	patch := file.GetPatch()
+	switch {
+	case m.loading[name]:
+		notice = "Loading diff…"
+	case m.patchErrors[name] != "":
+		notice = "Failed to load the diff: " + m.patchErrors[name] + ". Press L to try again."
+	case file.Patch == nil && file.GetStatus() == "renamed" && file.GetChanges() == 0:
+		notice = "File renamed without changes."
+	case file.Patch == nil && file.GetChanges() == 0:
+		notice = "Binary file not shown."
+	case file.Patch == nil:
+		notice = "GitHub does not show diffs this large. Press L to load it."
+	case strings.Count(patch, "\n") >= maxRenderedPatchLines && !m.expanded[name]:
+		notice = fmt.Sprintf("Large diff, %d lines. Press L to render it.", strings.Count(patch, "\n")+1)
+	}
+	if notice != "" {
+		m.rows = append(m.rows, diffRow{text: diffNoticeStyle.Render(notice)})
+		m.rows = append(m.rows, m.annotationRows(m.annotations[name])...)
+		return
+	}
+
+	// Annotations are shown under the first line they are on, the others after the hunks.
+	onLine := map[string][]DiffAnnotation{}
+	for _, annotation := range m.annotations[name] {
+		key := fmt.Sprintf("%s:%d", annotation.Side, annotation.Line)
+		onLine[key] = append(onLine[key], annotation)
+	}
+	annotate := func(side string, line int) []diffRow {
+		key := fmt.Sprintf("%s:%d", side, line)
+		rows := m.annotationRows(onLine[key])
+		delete(onLine, key)
+		return rows
+	}
+
+	lexer := highlight.Lexer(name)
+	folded := m.foldedHunks(file)
+	for i, hunk := range parsePatch(patch) {
+		m.hunkRows = append(m.hunkRows, len(m.rows))
+		marker := "▾ "
+		if folded[i] {
+			marker = "▸ "
+		}
+		hunkHeader := marker + hunk.header
+		if folded[i] {
+			hunkHeader += fmt.Sprintf(" · %d lines folded", len(hunk.lines))
+		}
+		m.rows = append(m.rows, diffRow{text: diffHunkHeaderStyle.MaxWidth(width).Render(hunkHeader)})
+		if folded[i] {
+			continue
+		}
+
+		if m.split {
+			m.rows = append(m.rows, m.renderSplit(hunk, lexer, annotate)...)
+		} else {
+			m.rows = append(m.rows, m.renderUnified(hunk, lexer, annotate)...)
+		}
+	}
+
+	remaining := make([]DiffAnnotation, 0)
+	for _, annotation := range m.annotations[name] {
+		if _, ok := onLine[fmt.Sprintf("%s:%d", annotation.Side, annotation.Line)]; ok {
+			remaining = append(remaining, annotation)
+		}
+	}
+	if len(remaining) > 0 {
+		m.rows = append(m.rows, diffRow{}, diffRow{text: diffNoticeStyle.Render("Not on a line shown above:")})
+		m.rows = append(m.rows, m.annotationRows(remaining)...)
+	}
+}
+
+// annotationRows renders annotations under a bar.
+func (m DiffViewerModel) annotationRows(annotations []DiffAnnotation) []diffRow {
+	rows := make([]diffRow, 0)
+	for _, annotation := range annotations {
+		for _, line := range annotation.Lines {
+			rows = append(rows, diffRow{
+				text:       lipgloss.NewStyle().MaxWidth(m.codeWidth()).Render(annotationStyle.Render("    ┃ ") + line),
+				annotation: annotation.ID,
+			})
+		}
+	}
+	return rows
+}
+
+func (m DiffViewerModel) renderUnified(hunk diffHunk, lexer chroma.Lexer, annotate func(string, int) []diffRow) []diffRow {
+	width := m.codeWidth()
+	codeWidth := max(0, width-lipgloss.Width(lineNumber(0))*2-3)
+
+	rows := make([]diffRow, 0, len(hunk.lines))
+	for _, line := range hunk.lines {
+		if line.kind == noNewlineLine {
+			rows = append(rows, diffRow{text: diffNoticeStyle.MaxWidth(width).Render(line.text)})
+			continue
+		}
+
+		background, emphasis := diffLineColors(line.kind)
+		gutter := diffGutterStyle.Render(lineNumber(line.oldNumber) + " " + lineNumber(line.newNumber) + " ")
+		sign := lipgloss.NewStyle().Background(background).Render(diffSign(line.kind))
+		code := renderCode(line.text, highlight.Colors(lexer, m.style, line.text), line.emphasis, background, emphasis, codeWidth)
+		row := diffRow{text: gutter + sign + code, side: "RIGHT", line: line.newNumber, code: line.text}
+		if line.kind == removedLine {
+			row.side, row.line = "LEFT", line.oldNumber
+		}
+		rows = append(rows, row)
+		if line.kind != addedLine {
+			rows = append(rows, annotate("LEFT", line.oldNumber)...)
+		}
+		if line.kind != removedLine {
+			rows = append(rows, annotate("RIGHT", line.newNumber)...)
+		}
+	}
+	return rows
+}
+
+func (m DiffViewerModel) renderSplit(hunk diffHunk, lexer chroma.Lexer, annotate func(string, int) []diffRow) []diffRow {
+	width := m.codeWidth()
+	half := max(0, (width-1)/2)
+	codeWidth := max(0, half-lipgloss.Width(lineNumber(0))-2)
+
+	// side renders line on the old, left, or new, right side.
+	side := func(line *diffLine, old bool) string {
+		if line == nil {
+			return strings.Repeat(" ", half)
+		}
+		number := line.newNumber
+		if old {
+			number = line.oldNumber
+		}
+		background, emphasis := diffLineColors(line.kind)
+		return diffGutterStyle.Render(lineNumber(number)+" ") +
+			lipgloss.NewStyle().Background(background).Render(diffSign(line.kind)) +
+			renderCode(line.text, highlight.Colors(lexer, m.style, line.text), line.emphasis, background, emphasis, codeWidth)
+	}
+	separator := diffGutterStyle.Render("│")
+
+	rows := make([]diffRow, 0, len(hunk.lines))
+	// row renders a row of left and right lines, followed by their annotations.
+	row := func(left *diffLine, right *diffLine) {
+		rendered := diffRow{text: side(left, true) + separator + side(right, false)}
+		if right != nil {
+			rendered.side, rendered.line, rendered.code = "RIGHT", right.newNumber, right.text
+			if left != nil {
+				rendered.oldLine, rendered.oldCode = left.oldNumber, left.text
+			}
+		} else {
+			rendered.side, rendered.line, rendered.code = "LEFT", left.oldNumber, left.text
+		}
+		rows = append(rows, rendered)
+		if left != nil {
+			rows = append(rows, annotate("LEFT", left.oldNumber)...)
+		}
+		if right != nil {
+			rows = append(rows, annotate("RIGHT", right.newNumber)...)
+		}
+	}
+
+	for i := 0; i < len(hunk.lines); {
+		line := &hunk.lines[i]
+		switch line.kind {
+		case noNewlineLine:
+			rows = append(rows, diffRow{text: diffNoticeStyle.MaxWidth(width).Render(line.text)})
+			i++
+		case contextLine:
+			row(line, line)
+			i++
+		default:
+			// A change: removed lines on the left next to the lines added in their place.
+			removed := make([]*diffLine, 0)
+			for i < len(hunk.lines) && hunk.lines[i].kind == removedLine {
+				removed = append(removed, &hunk.lines[i])
+				i++
+			}
+			added := make([]*diffLine, 0)
+			for i < len(hunk.lines) && hunk.lines[i].kind == addedLine {
+				added = append(added, &hunk.lines[i])
+				i++
+			}
+			for j := 0; j < max(len(removed), len(added)); j++ {
+				var left, right *diffLine
+				if j < len(removed) {
+					left = removed[j]
+				}
+				if j < len(added) {
+					right = added[j]
+				}
+				row(left, right)
+			}
+		}
+	}
+	return rows
+}
+
+func diffLineColors(kind diffLineKind) (lipgloss.TerminalColor, lipgloss.TerminalColor) {
+	switch kind {
+	case addedLine:
+		return addedLineColor, addedEmphasisColor
+	case removedLine:
+		return removedLineColor, removedEmphasisColor
+	default:
+		return lipgloss.NoColor{}, lipgloss.NoColor{}
+	}
+}
+
+func diffSign(kind diffLineKind) string {
+	switch kind {
+	case addedLine:
+		return "+"
+	case removedLine:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+// buildDiffTree lists files sorted by path under their directories.
+func buildDiffTree(files []*github.CommitFile) []diffTreeEntry {
+	sorted := make([]*github.CommitFile, len(files))
+	copy(sorted, files)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetFilename() < sorted[j].GetFilename()
+	})
+
+	entries := make([]diffTreeEntry, 0, len(sorted))
+	var previous []string
+	for _, file := range sorted {
+		dir := path.Dir(file.GetFilename())
+		var parts []string
+		if dir != "." {
+			parts = strings.Split(dir, "/")
+		}
+
+		common := 0
+		for common < len(parts) && common < len(previous) && parts[common] == previous[common] {
+			common++
+		}
+		for depth := common; depth < len(parts); depth++ {
+			entries = append(entries, diffTreeEntry{
+				text: strings.Repeat("  ", depth) + treeDirectoryStyle.Render(parts[depth]+"/"),
+			})
+		}
+		previous = parts
+
+		status := file.GetStatus()
+		mark := strings.ToUpper(status[:min(1, len(status))])
+		if style, ok := fileStatusStyles[status]; ok {
+			mark = style.Render(mark)
+		}
+		entries = append(entries, diffTreeEntry{
+			text: fmt.Sprintf(
+				"%s%s %s %s",
+				strings.Repeat("  ", len(parts)),
+				mark,
+				path.Base(file.GetFilename()),
+				diffHelpStyle.Render(fmt.Sprintf("+%d −%d", file.GetAdditions(), file.GetDeletions())),
+			),
+			file: file,
+		})
+	}
+	return entries
+}
@@ -0,0 +1,50 @@
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/ui/uitest"
+)
+
+func TestDiffViewerCommentsOnSideOfSplitRow(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []string
+		wantSide string
+	}{
+		{name: "new line", keys: []string{"c"}, wantSide: "RIGHT"},
+		{name: "old line", keys: []string{"<", "c"}, wantSide: "LEFT"},
+		{name: "back to the new line", keys: []string{"<", ">", "c"}, wantSide: "RIGHT"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var model tea.Model = NewDiffViewerComponent(120, 40)
+			model, _ = model.Update(DiffViewerSetFilesMsg{
+				Files: []*github.CommitFile{{
+					Filename: github.Ptr("main.go"),
+					Patch:    github.Ptr("@@ -3,1 +3,1 @@\n-old\n+new"),
+				}},
+				Commentable: true,
+			})
+			// The header, a blank row and the hunk's header come before the change.
+			keys := append([]string{"s", "enter", "j", "j", "j"}, test.keys...)
+			var cmd tea.Cmd
+			for _, key := range keys {
+				model, cmd = model.Update(uitest.KeyMsg(key))
+			}
+			if cmd == nil {
+				t.Fatal("c sent no comment")
+			}
+			comment, ok := cmd().(DiffViewerCommentMsg)
+			if !ok {
+				t.Fatalf("c sent %T, want DiffViewerCommentMsg", cmd())
+			}
+			if comment.Side != test.wantSide || comment.Line != 3 || comment.StartLine != 0 {
+				t.Errorf("comment on %s line %d from %d, want %s line 3", comment.Side, comment.Line, comment.StartLine, test.wantSide)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package components
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/alex-laycalvert/ghtui/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+var (
+	errorTitleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("9"))
+	errorHintStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241"))
+)
+
+// ErrorPanelModel shows a `utils.ErrorMsg` to the user with a hint to retry.
+// Retrying itself is up to the page owning the panel.
+type ErrorPanelModel struct {
+	id    string
+	width int
+
+	err utils.ErrorMsg
+}
+
+type ErrorPanelSetErrorMsg struct {
+	Err utils.ErrorMsg
+}
+
+func NewErrorPanelComponent(width int) ErrorPanelModel {
+	return ErrorPanelModel{
+		id:    "errorPanel_" + uuid.NewString(),
+		width: width,
+	}
+}
+
+func (m ErrorPanelModel) ID() string {
+	return m.id
+}
+
+func (m ErrorPanelModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m ErrorPanelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case utils.UpdateSizeMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+
+		if msg.Width > 0 {
+			m.width = msg.Width
+		}
+		return m, nil
+	case ErrorPanelSetErrorMsg:
+		m.err = msg.Err
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m ErrorPanelModel) View() string {
+	title := "Error"
+	if m.err.Status != 0 {
+		title = fmt.Sprintf("Error %d %s", m.err.Status, http.StatusText(m.err.Status))
+	}
+
+	hint := "Press r to try again"
+	if m.err.Retryable {
+		hint = "This is likely temporary, press r to retry"
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		AlignHorizontal(lipgloss.Center).
+		Render(lipgloss.JoinVertical(
+			lipgloss.Center,
+			errorTitleStyle.Render(title),
+			"",
+			lipgloss.NewStyle().Width(min(m.width, 80)).Render(m.err.Error()),
+			"",
+			errorHintStyle.Render(hint),
+		))
+}
@@ -0,0 +1,174 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+
+	"github.com/alex-laycalvert/ghtui/ui/highlight"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+// How far h and l scroll long lines sideways.
+const fileViewerScrollColumns = 8
+
+// FileViewerModel shows a source file with line numbers and syntax highlighting.
+//
+// j and k scroll, ctrl+d and ctrl+u by half a screen, g and G to the top and
+// bottom, and h and l scroll long lines sideways.
+type FileViewerModel struct {
+	id     string
+	width  int
+	height int
+
+	path  string
+	style *chroma.Style
+	// Lines of the file with tabs expanded, and the color of each of their runes.
+	lines  [][]rune
+	colors [][]string
+	offset int
+	column int
+}
+
+type FileViewerSetFileMsg struct {
+	// Path of the file, to pick its syntax.
+	Path    string
+	Content string
+}
+
+func NewFileViewerComponent(width int, height int) FileViewerModel {
+	return FileViewerModel{
+		id:     "fileViewer_" + uuid.NewString(),
+		width:  width,
+		height: height,
+		style:  highlight.Style(),
+	}
+}
+
+func (m FileViewerModel) ID() string {
+	return m.id
+}
+
+func (m FileViewerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m FileViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case utils.UpdateSizeMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+
+		if msg.Width > 0 {
+			m.width = msg.Width
+		}
+		if msg.Height > 0 {
+			m.height = msg.Height
+		}
+		m.scroll(0)
+		return m, nil
+	case FileViewerSetFileMsg:
+		m.path = msg.Path
+		m.lines, m.colors = highlightFile(msg.Path, m.style, msg.Content)
+		m.offset = 0
+		m.column = 0
+		return m, nil
+	case tea.KeyMsg:
+		half := max(1, m.height/2)
+		switch msg.String() {
+		case "j", "down":
+			m.scroll(1)
+		case "k", "up":
+			m.scroll(-1)
+		case "ctrl+d", "pgdown":
+			m.scroll(half)
+		case "ctrl+u", "pgup":
+			m.scroll(-half)
+		case "g":
+			m.offset = 0
+		case "G":
+			m.scroll(len(m.lines))
+		case "l", "right":
+			m.column += fileViewerScrollColumns
+		case "h", "left":
+			m.column = max(0, m.column-fileViewerScrollColumns)
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m FileViewerModel) View() string {
+	gutterWidth := len(fmt.Sprint(len(m.lines)))
+	codeWidth := max(0, m.width-gutterWidth-1)
+
+	rows := make([]string, 0, m.height)
+	for i := m.offset; i < len(m.lines) && i < m.offset+m.height; i++ {
+		line, colors := m.lines[i], m.colors[i]
+		if m.column < len(line) {
+			line, colors = line[m.column:], colors[min(m.column, len(colors)):]
+		} else {
+			line, colors = nil, nil
+		}
+		gutter := diffGutterStyle.Render(fmt.Sprintf("%*d ", gutterWidth, i+1))
+		rows = append(rows, gutter+renderCode(string(line), colors, nil, lipgloss.NoColor{}, lipgloss.NoColor{}, codeWidth))
+	}
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Render(strings.Join(rows, "\n"))
+}
+
+func (m *FileViewerModel) scroll(lines int) {
+	m.offset = max(0, min(m.offset+lines, len(m.lines)-m.height))
+}
+
+// highlightFile splits content into lines and colors them, tokenising the
+// file as a whole so constructs spanning lines are colored throughout.
+func highlightFile(path string, style *chroma.Style, content string) ([][]rune, [][]string) {
+	content = strings.TrimSuffix(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	colors := highlight.Colors(highlight.Lexer(path), style, content)
+
+	lines := make([][]rune, 0)
+	lineColors := make([][]string, 0)
+	start := 0
+	for _, text := range strings.Split(content, "\n") {
+		count := len([]rune(text))
+		end := min(start+count, len(colors))
+		line, colored := expandLineTabs([]rune(text), colors[min(start, end):end])
+		lines = append(lines, line)
+		lineColors = append(lineColors, colored)
+		// Skip the newline.
+		start += count + 1
+	}
+	return lines, lineColors
+}
+
+// expandLineTabs expands the tabs of line to spaces, repeating the color of
+// each tab for its spaces.
+func expandLineTabs(line []rune, colors []string) ([]rune, []string) {
+	expanded := make([]rune, 0, len(line))
+	expandedColors := make([]string, 0, len(line))
+	for i, r := range line {
+		color := ""
+		if i < len(colors) {
+			color = colors[i]
+		}
+		if r != '\t' {
+			expanded = append(expanded, r)
+			expandedColors = append(expandedColors, color)
+			continue
+		}
+		for spaces := diffTabWidth - len(expanded)%diffTabWidth; spaces > 0; spaces-- {
+			expanded = append(expanded, ' ')
+			expandedColors = append(expandedColors, color)
+		}
+	}
+	return expanded, expandedColors
+}
@@ -0,0 +1,178 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/alex-laycalvert/ghtui/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+var (
+	formStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("62")).
+			Padding(0, 1)
+	formTitleStyle = lipgloss.NewStyle().
+			Bold(true).
+			MarginBottom(1)
+	formLabelStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241"))
+	formFocusedLabelStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("205"))
+	formHintStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			MarginTop(1)
+)
+
+// A single line field of a `FormModel`.
+type FormField struct {
+	Label string
+	Value string
+	// Shown below the fields while this field is focused.
+	Hint string
+}
+
+// FormModel is a set of single line text fields filled in one after another.
+//
+// Tab, shift+tab and the arrow keys move between fields, enter submits with
+// `FormSubmitMsg` and esc cancels with `FormCancelMsg`. A form is reused by
+// sending it `FormResetMsg` with new fields.
+type FormModel struct {
+	id    string
+	width int
+
+	title  string
+	fields []FormField
+	focus  int
+}
+
+type FormResetMsg struct {
+	Title  string
+	Fields []FormField
+}
+
+type FormSubmitMsg struct {
+	ID string
+	// Values of the fields, in the order they were given.
+	Values []string
+}
+
+type FormCancelMsg struct {
+	ID string
+}
+
+func NewFormComponent(width int) FormModel {
+	return FormModel{
+		id:    "form_" + uuid.NewString(),
+		width: width,
+	}
+}
+
+func (m FormModel) ID() string {
+	return m.id
+}
+
+func (m FormModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m FormModel) CapturesInput() bool {
+	return true
+}
+
+func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case FormResetMsg:
+		m.title = msg.Title
+		m.fields = msg.Fields
+		m.focus = 0
+		return m, nil
+	case utils.UpdateSizeMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+
+		if msg.Width > 0 {
+			m.width = msg.Width
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if len(m.fields) == 0 {
+			return m, nil
+		}
+
+		field := &m.fields[m.focus]
+		switch keypress := msg.String(); keypress {
+		case "enter":
+			values := make([]string, len(m.fields))
+			for i, field := range m.fields {
+				values[i] = strings.TrimSpace(field.Value)
+			}
+			id := m.id
+			return m, func() tea.Msg {
+				return FormSubmitMsg{ID: id, Values: values}
+			}
+		case "esc":
+			id := m.id
+			return m, func() tea.Msg {
+				return FormCancelMsg{ID: id}
+			}
+		case "tab", "down":
+			m.focus = (m.focus + 1) % len(m.fields)
+			return m, nil
+		case "shift+tab", "up":
+			m.focus = (m.focus - 1 + len(m.fields)) % len(m.fields)
+			return m, nil
+		case "backspace":
+			runes := []rune(field.Value)
+			if len(runes) > 0 {
+				field.Value = string(runes[:len(runes)-1])
+			}
+			return m, nil
+		case "ctrl+u":
+			field.Value = ""
+			return m, nil
+		case "ctrl+w":
+			trimmed := strings.TrimRight(field.Value, " ")
+			field.Value = trimmed[:strings.LastIndex(trimmed, " ")+1]
+			return m, nil
+		default:
+			if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+				field.Value += string(msg.Runes)
+			}
+			return m, nil
+		}
+	}
+
+	return m, nil
+}
+
+func (m FormModel) View() string {
+	innerWidth := max(0, m.width-formStyle.GetHorizontalFrameSize())
+	rows := []string{formTitleStyle.Render(m.title)}
+
+	for i, field := range m.fields {
+		labelStyle := formLabelStyle
+		cursor := ""
+		if i == m.focus {
+			labelStyle = formFocusedLabelStyle
+			cursor = "█"
+		}
+		// Multi-line values are shown on one line.
+		value := strings.ReplaceAll(field.Value, "\n", "⏎")
+		line := labelStyle.Render(field.Label+": ") + value + cursor
+		rows = append(rows, lipgloss.NewStyle().Width(innerWidth).MaxHeight(1).Render(line))
+	}
+
+	hint := "enter submit · tab next field · esc cancel"
+	if len(m.fields) > 0 && m.fields[m.focus].Hint != "" {
+		hint = m.fields[m.focus].Hint + "\n" + hint
+	}
+	rows = append(rows, formHintStyle.Render(hint))
+
+	return formStyle.
+		Width(max(0, m.width-formStyle.GetHorizontalBorderSize())).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
@@ -44,7 +44,16 @@ func NewIssuesListComponent(width int, height int) IssuesListModel {
 	}
 }
 
+// GetIssues returns the issues currently in the list.
+func (m IssuesListModel) GetIssues() []*github.Issue {
+	return m.issues
+}
+
+// GetSelectedIssue returns the issue under the cursor, nil if the list is empty.
 func (m IssuesListModel) GetSelectedIssue() *github.Issue {
+	if m.cursorIndex >= len(m.issues) {
+		return nil
+	}
 	return m.issues[m.cursorIndex]
 }
 
@@ -104,6 +113,8 @@ func (m IssuesListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case IssuesListUpdateIssuesMsg:
 		m.issues = msg.Issues
+		m.cursorIndex = max(0, min(m.cursorIndex, len(m.issues)-1))
+		m.viewportStartIndex = max(0, min(m.viewportStartIndex, len(m.issues)-m.height))
 		return m, nil
 	case IssuesListResetViewportMsg:
 		m.viewportStartIndex = 0
@@ -126,7 +137,12 @@ func (m IssuesListModel) View() string {
 		}
 		itemStyle = itemStyle.Width(m.width)
 
-		issueString := strconv.Itoa(*issue.Number) + " " + *issue.Title
+		// Issues being created have no number yet.
+		number := "…"
+		if issue.Number != nil {
+			number = strconv.Itoa(*issue.Number)
+		}
+		issueString := number + " " + issue.GetTitle()
 		if len(issueString) >= m.width {
 			issueString = issueString[:m.width-1]
 		}
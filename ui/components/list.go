@@ -0,0 +1,149 @@
+package components
+
+import (
+	"github.com/alex-laycalvert/ghtui/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+// ListModel is a scrollable list of items with a cursor, each rendered on one
+// line by the function given to `NewListComponent`.
+//
+// It moves like `IssuesListModel` and is used by pages listing anything else.
+type ListModel[T any] struct {
+	id                 string
+	width              int
+	height             int
+	items              []T
+	render             func(item T, width int) string
+	viewportStartIndex int
+	cursorIndex        int
+}
+
+type ListSetItemsMsg[T any] struct {
+	Items []T
+}
+
+type ListResetViewportMsg struct{}
+
+// ListSetCursorMsg moves the cursor to the item at Index, scrolling it into view.
+type ListSetCursorMsg struct {
+	Index int
+}
+
+// NewListComponent creates a list rendering each item with render, which must
+// return a single line at most width wide.
+func NewListComponent[T any](width int, height int, render func(item T, width int) string) ListModel[T] {
+	return ListModel[T]{
+		id:     "list_" + uuid.NewString(),
+		width:  width,
+		height: height,
+		render: render,
+	}
+}
+
+// GetItems returns the items currently in the list.
+func (m ListModel[T]) GetItems() []T {
+	return m.items
+}
+
+// GetSelectedItem returns the item under the cursor, false if the list is empty.
+func (m ListModel[T]) GetSelectedItem() (T, bool) {
+	if m.cursorIndex >= len(m.items) {
+		var zero T
+		return zero, false
+	}
+	return m.items[m.cursorIndex], true
+}
+
+func (m ListModel[T]) ID() string {
+	return m.id
+}
+
+func (m ListModel[T]) Init() tea.Cmd {
+	return nil
+}
+
+func (m ListModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch keypress := msg.String(); keypress {
+		case "j", "down":
+			m.cursorIndex = max(0, min(len(m.items)-1, m.cursorIndex+1))
+			if m.cursorIndex >= m.viewportStartIndex+m.height {
+				m.viewportStartIndex = max(0, min(m.viewportStartIndex+1, len(m.items)-m.height))
+			}
+			return m, nil
+		case "k", "up":
+			m.cursorIndex = max(0, m.cursorIndex-1)
+			if m.cursorIndex < m.viewportStartIndex {
+				m.viewportStartIndex = m.cursorIndex
+			}
+			return m, nil
+		case "H":
+			m.cursorIndex = m.viewportStartIndex
+			return m, nil
+		case "L":
+			m.cursorIndex = max(0, min(len(m.items)-1, m.viewportStartIndex+m.height-1))
+			return m, nil
+		case "g":
+			m.cursorIndex = 0
+			m.viewportStartIndex = 0
+			return m, nil
+		case "G":
+			m.cursorIndex = max(0, len(m.items)-1)
+			m.viewportStartIndex = max(0, len(m.items)-m.height)
+			return m, nil
+		}
+	case utils.UpdateSizeMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+
+		if msg.Width > 0 {
+			m.width = msg.Width
+		}
+		if msg.Height > 0 {
+			m.height = msg.Height
+		}
+		return m, nil
+	case ListSetItemsMsg[T]:
+		m.items = msg.Items
+		m.cursorIndex = max(0, min(m.cursorIndex, len(m.items)-1))
+		m.viewportStartIndex = max(0, min(m.viewportStartIndex, len(m.items)-m.height))
+		return m, nil
+	case ListResetViewportMsg:
+		m.viewportStartIndex = 0
+		m.cursorIndex = 0
+		return m, nil
+	case ListSetCursorMsg:
+		m.cursorIndex = max(0, min(msg.Index, len(m.items)-1))
+		if m.cursorIndex < m.viewportStartIndex {
+			m.viewportStartIndex = m.cursorIndex
+		}
+		if m.cursorIndex >= m.viewportStartIndex+m.height {
+			m.viewportStartIndex = max(0, m.cursorIndex-m.height+1)
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m ListModel[T]) View() string {
+	lines := make([]string, 0, m.height)
+	for i := m.viewportStartIndex; i < m.viewportStartIndex+m.height && i < len(m.items); i++ {
+		itemStyle := listItemStyle
+		if i == m.cursorIndex {
+			itemStyle = selectedListItemStyle
+		}
+		line := lipgloss.NewStyle().MaxWidth(m.width).Render(m.render(m.items[i], m.width))
+		lines = append(lines, itemStyle.Width(m.width).Render(line))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,400 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/google/uuid"
+
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+var (
+	logTimestampPattern = regexp.MustCompile(`^\x{FEFF}?(\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d(?:\.\d+)?Z) `)
+
+	logSectionStyle   = lipgloss.NewStyle().Bold(true)
+	logGroupStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	logErrorStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	logWarningStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	logCommandStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	logTimestampStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// A part of a log shown under its own collapsible title, e.g. a step of a job.
+type LogSection struct {
+	Title     string
+	Log       string
+	Collapsed bool
+}
+
+// LogViewerModel shows logs of GitHub Actions, keeping their ANSI colors.
+// Each `LogSection` and each `::group::` in them can be collapsed.
+//
+// Enter toggles the group under the cursor, Z collapses or expands all of
+// them, n and N move to the next and previous error and t toggles timestamps.
+type LogViewerModel struct {
+	id     string
+	width  int
+	height int
+
+	title      string
+	entries    []logEntry
+	groups     []logGroup
+	timestamps bool
+
+	// Entries shown, skipping those of collapsed groups, the first row shown
+	// and the row of the cursor.
+	visible []int
+	offset  int
+	cursor  int
+}
+
+type logEntryKind int
+
+const (
+	plainLogEntry logEntryKind = iota
+	errorLogEntry
+	warningLogEntry
+	commandLogEntry
+)
+
+type logEntry struct {
+	text      string
+	timestamp string
+	depth     int
+	kind      logEntryKind
+	// Index of the group this entry is the title of, -1 otherwise.
+	group int
+}
+
+type logGroup struct {
+	// Entry of the title and the entry after the group's last one.
+	start     int
+	end       int
+	collapsed bool
+}
+
+type LogViewerSetLogMsg struct {
+	Title    string
+	Sections []LogSection
+}
+
+func NewLogViewerComponent(width int, height int) LogViewerModel {
+	return LogViewerModel{
+		id:     "logViewer_" + uuid.NewString(),
+		width:  width,
+		height: height,
+	}
+}
+
+func (m LogViewerModel) ID() string {
+	return m.id
+}
+
+func (m LogViewerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m LogViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case utils.UpdateSizeMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+
+		if msg.Width > 0 {
+			m.width = msg.Width
+		}
+		if msg.Height > 0 {
+			m.height = msg.Height
+		}
+		m.moveCursor(0)
+		return m, nil
+	case LogViewerSetLogMsg:
+		m.title = msg.Title
+		m.entries, m.groups = parseLogSections(msg.Sections)
+		m.offset = 0
+		m.cursor = 0
+		m.updateVisible()
+		// Start on the first expanded section.
+		for row, entry := range m.visible {
+			if group := m.entries[entry].group; group >= 0 && !m.groups[group].collapsed {
+				m.cursor = row
+				m.offset = row
+				break
+			}
+		}
+		m.moveCursor(0)
+		return m, nil
+	case tea.KeyMsg:
+		half := max(1, m.logHeight()/2)
+		switch msg.String() {
+		case "j", "down":
+			m.moveCursor(1)
+		case "k", "up":
+			m.moveCursor(-1)
+		case "ctrl+d", "pgdown":
+			m.offset += half
+			m.moveCursor(half)
+		case "ctrl+u", "pgup":
+			m.offset = max(0, m.offset-half)
+			m.moveCursor(-half)
+		case "g":
+			m.moveCursor(-len(m.visible))
+		case "G":
+			m.moveCursor(len(m.visible))
+		case "enter", " ":
+			m.toggleGroup()
+		case "Z":
+			collapse := false
+			for _, group := range m.groups {
+				if !group.collapsed {
+					collapse = true
+				}
+			}
+			for i := range m.groups {
+				m.groups[i].collapsed = collapse
+			}
+			m.cursor = 0
+			m.offset = 0
+			m.updateVisible()
+		case "n":
+			m.jumpToError(1)
+		case "N":
+			m.jumpToError(-1)
+		case "t":
+			m.timestamps = !m.timestamps
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m LogViewerModel) View() string {
+	height := m.logHeight()
+	rows := make([]string, 0, height)
+	for row := m.offset; row < len(m.visible) && row < m.offset+height; row++ {
+		marker := " "
+		if row == m.cursor {
+			marker = diffCursorStyle.Render("▌")
+		}
+		rows = append(rows, marker+m.renderEntry(m.visible[row]))
+	}
+	if len(m.entries) == 0 {
+		rows = append(rows, diffNoticeStyle.Render(" The log is empty."))
+	}
+
+	title := lipgloss.NewStyle().Bold(true).MaxWidth(m.width).Render(m.title)
+	help := diffHelpStyle.
+		Width(m.width).
+		MaxHeight(1).
+		Render(fmt.Sprintf("%d lines · enter fold · Z fold all · n/N error · t timestamps", len(m.entries)))
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		lipgloss.NewStyle().Width(m.width).Height(height).Render(strings.Join(rows, "\n")),
+		help,
+	)
+}
+
+// logHeight is the height of the log, between the title and the help line.
+func (m LogViewerModel) logHeight() int {
+	return max(0, m.height-2)
+}
+
+func (m LogViewerModel) renderEntry(index int) string {
+	entry := m.entries[index]
+	width := max(0, m.width-1)
+
+	prefix := strings.Repeat("  ", entry.depth)
+	if entry.group >= 0 {
+		marker := "▾ "
+		if m.groups[entry.group].collapsed {
+			marker = "▸ "
+		}
+		prefix = strings.Repeat("  ", entry.depth) + marker
+	}
+	if m.timestamps && entry.timestamp != "" {
+		prefix = logTimestampStyle.Render(entry.timestamp+" ") + prefix
+	}
+
+	text := entry.text
+	switch {
+	case entry.group >= 0 && entry.depth == 0:
+		group := m.groups[entry.group]
+		text = logSectionStyle.Render(text) + diffHelpStyle.Render(fmt.Sprintf(" · %d lines", group.end-group.start-1))
+	case entry.group >= 0:
+		text = logGroupStyle.Render(text)
+	case entry.kind == errorLogEntry:
+		text = logErrorStyle.Render(text)
+	case entry.kind == warningLogEntry:
+		text = logWarningStyle.Render(text)
+	case entry.kind == commandLogEntry:
+		text = logCommandStyle.Render(text)
+	}
+	// Colors left on by the log do not leak into the next line.
+	return ansi.Truncate(prefix+text, width, "…") + "\x1b[0m"
+}
+
+// updateVisible lists the entries outside collapsed groups.
+func (m *LogViewerModel) updateVisible() {
+	m.visible = make([]int, 0, len(m.entries))
+	for i := 0; i < len(m.entries); i++ {
+		m.visible = append(m.visible, i)
+		if group := m.entries[i].group; group >= 0 && m.groups[group].collapsed {
+			i = m.groups[group].end - 1
+		}
+	}
+}
+
+func (m *LogViewerModel) moveCursor(rows int) {
+	m.cursor = max(0, min(m.cursor+rows, len(m.visible)-1))
+	height := m.logHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+	m.offset = max(0, min(m.offset, len(m.visible)-height))
+}
+
+// toggleGroup collapses or expands the group whose title is under the
+// cursor, otherwise collapses the innermost group around the cursor.
+func (m *LogViewerModel) toggleGroup() {
+	if m.cursor >= len(m.visible) {
+		return
+	}
+	entry := m.visible[m.cursor]
+	group := m.entries[entry].group
+	if group < 0 {
+		for i, candidate := range m.groups {
+			if candidate.start < entry && entry < candidate.end && (group < 0 || candidate.start > m.groups[group].start) {
+				group = i
+			}
+		}
+	}
+	if group < 0 {
+		return
+	}
+
+	m.groups[group].collapsed = !m.groups[group].collapsed
+	m.updateVisible()
+	for row, index := range m.visible {
+		if index == m.groups[group].start {
+			m.cursor = row
+		}
+	}
+	m.moveCursor(0)
+}
+
+// jumpToError moves the cursor to the next error in direction, expanding the
+// groups around it.
+func (m *LogViewerModel) jumpToError(direction int) {
+	if m.cursor >= len(m.visible) {
+		return
+	}
+	current := m.visible[m.cursor]
+	for i := current + direction; i >= 0 && i < len(m.entries); i += direction {
+		if m.entries[i].kind != errorLogEntry {
+			continue
+		}
+		for g := range m.groups {
+			if m.groups[g].start < i && i < m.groups[g].end {
+				m.groups[g].collapsed = false
+			}
+		}
+		m.updateVisible()
+		for row, index := range m.visible {
+			if index == i {
+				m.cursor = row
+			}
+		}
+		m.moveCursor(0)
+		return
+	}
+}
+
+// parseLogSections turns sections of a log into entries, each section and
+// `::group::` being a group.
+func parseLogSections(sections []LogSection) ([]logEntry, []logGroup) {
+	entries := make([]logEntry, 0)
+	groups := make([]logGroup, 0)
+	for _, section := range sections {
+		groups = append(groups, logGroup{start: len(entries), collapsed: section.Collapsed})
+		entries = append(entries, logEntry{text: section.Title, group: len(groups) - 1})
+		// Groups opened in the section and not closed yet, innermost last.
+		open := []int{len(groups) - 1}
+
+		lines := strings.Split(strings.TrimRight(section.Log, "\n"), "\n")
+		if section.Log == "" {
+			lines = nil
+		}
+		for _, line := range lines {
+			line = strings.TrimRight(line, "\r")
+			timestamp := ""
+			if match := logTimestampPattern.FindStringSubmatch(line); match != nil {
+				timestamp = match[1]
+				line = line[len(match[0]):]
+			}
+			command, text := logCommand(line)
+
+			switch command {
+			case "group":
+				groups = append(groups, logGroup{start: len(entries), collapsed: true})
+				entries = append(entries, logEntry{text: text, timestamp: timestamp, depth: len(open), group: len(groups) - 1})
+				open = append(open, len(groups)-1)
+				continue
+			case "endgroup":
+				if len(open) > 1 {
+					groups[open[len(open)-1]].end = len(entries)
+					open = open[:len(open)-1]
+				}
+				continue
+			}
+
+			entry := logEntry{text: text, timestamp: timestamp, depth: len(open), group: -1}
+			switch command {
+			case "error":
+				entry.kind = errorLogEntry
+				entry.text = "Error: " + text
+			case "warning":
+				entry.kind = warningLogEntry
+				entry.text = "Warning: " + text
+			case "command":
+				entry.kind = commandLogEntry
+			}
+			entries = append(entries, entry)
+		}
+
+		for _, group := range open {
+			groups[group].end = len(entries)
+		}
+	}
+	return entries, groups
+}
+
+// logCommand returns the workflow command a log line starts with, either as
+// `::name::` or as GitHub writes them back, `##[name]`, and the rest of the line.
+func logCommand(line string) (string, string) {
+	if rest, ok := strings.CutPrefix(line, "##["); ok {
+		if name, text, found := strings.Cut(rest, "]"); found {
+			return name, text
+		}
+	}
+	if rest, ok := strings.CutPrefix(line, "::"); ok {
+		if name, text, found := strings.Cut(rest, "::"); found {
+			// Commands like ::error file=x:: carry parameters after the name.
+			name, _, _ = strings.Cut(name, " ")
+			return name, text
+		}
+	}
+	if strings.HasPrefix(line, "[command]") {
+		return "command", strings.TrimPrefix(line, "[command]")
+	}
+	return "", line
+}
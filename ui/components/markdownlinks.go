@@ -0,0 +1,368 @@
+package components
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Put before the text of the selected link.
+const selectedLinkMarker = "▶ "
+
+// A link of the markdown shown.
+type markdownLink struct {
+	url string
+	// Byte offset of the link's text in the rewritten markdown.
+	offset int
+}
+
+// A run of markdown lines, either a fenced or indented code block or
+// everything else.
+type markdownSegment struct {
+	text string
+	code bool
+}
+
+var (
+	htmlAnchorPattern = regexp.MustCompile(`(?is)<a\s[^>]*?href\s*=\s*["']([^"']*)["'][^>]*>(.*?)</a>`)
+	htmlImagePattern  = regexp.MustCompile(`(?is)<img\s[^>]*>`)
+	// Tags GitHub lays READMEs out with, dropped along with everything they
+	// hold when glamour meets them.
+	htmlLayoutPattern   = regexp.MustCompile(`(?i)</?(?:p|div|center|picture|source|span|details|summary|h[1-6]|br|hr|sup|sub|b|strong|em|i)\b[^>]*>`)
+	altAttributePattern = regexp.MustCompile(`(?i)\balt\s*=\s*["']([^"']*)["']`)
+	srcAttributePattern = regexp.MustCompile(`(?i)\bsrc\s*=\s*["']([^"']*)["']`)
+
+	linkedImagePattern         = regexp.MustCompile(`\[\s*!\[([^\]]*)\]\([^)]*\)\s*\]\(`)
+	imagePattern               = regexp.MustCompile(`!\[([^\]]*)\]\(\s*<?([^)\s>]*)>?(?:\s+"[^"]*")?\s*\)`)
+	linkPattern                = regexp.MustCompile(`\[([^\]]*)\]\(\s*<?([^)\s>]*)>?(?:\s+"[^"]*")?\s*\)`)
+	autolinkPattern            = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	referenceDefinitionPattern = regexp.MustCompile(`(?m)^( {0,3}\[[^\]]+\]:\s*)(\S+)`)
+	// References to issues and pull requests: #123 or owner/name#123.
+	issueReferencePattern = regexp.MustCompile(`(^|[\s(])((?:([\w.-]+)/([\w.-]+))?#(\d+))\b`)
+
+	listItemPattern            = regexp.MustCompile(`^ {0,3}(?:[-*+]|\d{1,9}[.)])(?:\s|$)`)
+	codeSpanPlaceholderPattern = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// rewriteMarkdownLinks prepares markdown from GitHub for glamour and lists
+// its links.
+//
+// HTML links and images are turned into markdown, images into links to them
+// labelled with their alt text, or just the alt text inside links so badges
+// are a word. Relative URLs are resolved against base, the URL of the
+// markdown on GitHub, and links starting with / against its repository.
+// References to issues and pull requests become links to them. Code blocks and
+// spans are left as they are.
+func rewriteMarkdownLinks(content string, base *url.URL) (string, []markdownLink) {
+	var rewritten strings.Builder
+	links := make([]markdownLink, 0)
+	for _, segment := range splitCodeBlocks(content) {
+		if segment.code {
+			rewritten.WriteString(segment.text)
+			continue
+		}
+
+		text, spans := protectCodeSpans(segment.text)
+		restore := func(text string) string {
+			return codeSpanPlaceholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+				index, _ := strconv.Atoi(strings.Trim(placeholder, "\x00"))
+				return spans[index]
+			})
+		}
+		// writeText writes text between links, turning its references into links.
+		writeText := func(text string) {
+			last := 0
+			for _, match := range issueReferencePattern.FindAllStringSubmatchIndex(text, -1) {
+				var owner, name string
+				if match[6] >= 0 {
+					owner, name = text[match[6]:match[7]], text[match[8]:match[9]]
+				}
+				target := issueReferenceURL(base, owner, name, text[match[10]:match[11]])
+				if target == "" {
+					continue
+				}
+				rewritten.WriteString(restore(text[last:match[4]]))
+				rewritten.WriteString("[")
+				links = append(links, markdownLink{url: target, offset: rewritten.Len()})
+				rewritten.WriteString(text[match[4]:match[5]] + "](" + target + ")")
+				last = match[5]
+			}
+			rewritten.WriteString(restore(text[last:]))
+		}
+
+		text = rewriteHTML(text)
+		text = linkedImagePattern.ReplaceAllStringFunc(text, func(image string) string {
+			return "[" + altText(linkedImagePattern.FindStringSubmatch(image)[1]) + "]("
+		})
+		text = imagePattern.ReplaceAllStringFunc(text, func(image string) string {
+			match := imagePattern.FindStringSubmatch(image)
+			label := "image"
+			if alt := strings.TrimSpace(match[1]); alt != "" {
+				label += ": " + alt
+			}
+			return "[" + label + "](" + match[2] + ")"
+		})
+		text = autolinkPattern.ReplaceAllString(text, "[$1]($1)")
+		text = referenceDefinitionPattern.ReplaceAllStringFunc(text, func(definition string) string {
+			match := referenceDefinitionPattern.FindStringSubmatch(definition)
+			return match[1] + resolveLink(match[2], base)
+		})
+
+		last := 0
+		for _, match := range linkPattern.FindAllStringSubmatchIndex(text, -1) {
+			writeText(text[last:match[0]])
+			target := resolveLink(restore(text[match[4]:match[5]]), base)
+			rewritten.WriteString("[")
+			links = append(links, markdownLink{url: target, offset: rewritten.Len()})
+			rewritten.WriteString(restore(text[match[2]:match[3]]) + "](" + target + ")")
+			last = match[1]
+		}
+		writeText(text[last:])
+	}
+	return rewritten.String(), links
+}
+
+// issueReferenceURL returns the URL of issue number of owner/name, or of the
+// repository of base when they are empty. It is empty when base is not the
+// URL of a page of a repository.
+func issueReferenceURL(base *url.URL, owner string, name string, number string) string {
+	if base == nil || base.Host == "" {
+		return ""
+	}
+	if owner == "" {
+		segments := strings.Split(strings.TrimPrefix(base.Path, "/"), "/")
+		if len(segments) < 2 || segments[0] == "" || segments[1] == "" {
+			return ""
+		}
+		owner, name = segments[0], segments[1]
+	}
+	// GitHub redirects issue URLs of pull requests to them.
+	return fmt.Sprintf("%s://%s/%s/%s/issues/%s", base.Scheme, base.Host, owner, name, number)
+}
+
+// protectCodeSpans replaces the code spans of text with placeholders, so what
+// they hold is not rewritten, returning the spans by the index in their
+// placeholder.
+func protectCodeSpans(text string) (string, []string) {
+	var protected strings.Builder
+	spans := make([]string, 0)
+	for i := 0; i < len(text); {
+		if text[i] != '`' {
+			protected.WriteByte(text[i])
+			i++
+			continue
+		}
+		open := i
+		for i < len(text) && text[i] == '`' {
+			i++
+		}
+		end := closingBackticks(text, i, i-open)
+		if end < 0 {
+			protected.WriteString(text[open:i])
+			continue
+		}
+		spans = append(spans, text[open:end])
+		fmt.Fprintf(&protected, "\x00%d\x00", len(spans)-1)
+		i = end
+	}
+	return protected.String(), spans
+}
+
+// closingBackticks returns the end of the run of exactly length backticks
+// closing a code span opened before from, or -1 if the paragraph has none.
+func closingBackticks(text string, from int, length int) int {
+	for i := from; i < len(text); {
+		if strings.HasPrefix(text[i:], "\n\n") {
+			return -1
+		}
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(text) && text[i] == '`' {
+			i++
+		}
+		if i-start == length {
+			return i
+		}
+	}
+	return -1
+}
+
+// A list tracker follows the lists of markdown line by line.
+type listTracker struct {
+	inList     bool
+	afterBlank bool
+}
+
+// next reports whether line is part of a list.
+func (l *listTracker) next(line string) bool {
+	blank := strings.TrimSpace(line) == ""
+	switch {
+	case listItemPattern.MatchString(line):
+		l.inList = true
+	case l.inList && l.afterBlank && !blank && indentation(line) < 2:
+		// A paragraph after a blank line ends the list.
+		l.inList = false
+	}
+	l.afterBlank = blank
+	return l.inList
+}
+
+// indentation returns the width of the whitespace starting line, tabs being
+// four columns.
+func indentation(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// rewriteHTML turns the HTML links and images of text into markdown and
+// drops layout tags, which glamour would leave out along with what they hold.
+func rewriteHTML(text string) string {
+	lines := strings.Split(text, "\n")
+	lists := listTracker{}
+	for i, line := range lines {
+		// Indented HTML would be a code block once the layout tags around it
+		// are dropped, unless the indentation nests it in a list.
+		inList := lists.next(line)
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "<") && !inList {
+			lines[i] = trimmed
+		}
+	}
+	text = strings.Join(lines, "\n")
+
+	text = htmlAnchorPattern.ReplaceAllStringFunc(text, func(anchor string) string {
+		match := htmlAnchorPattern.FindStringSubmatch(anchor)
+		inner := htmlImagePattern.ReplaceAllStringFunc(match[2], func(image string) string {
+			return " " + altText(htmlAttribute(altAttributePattern, image)) + " "
+		})
+		inner = strings.Join(strings.Fields(htmlLayoutPattern.ReplaceAllString(inner, "")), " ")
+		if inner == "" {
+			inner = match[1]
+		}
+		return "[" + inner + "](" + match[1] + ")"
+	})
+	text = htmlImagePattern.ReplaceAllStringFunc(text, func(image string) string {
+		return "![" + htmlAttribute(altAttributePattern, image) + "](" + htmlAttribute(srcAttributePattern, image) + ")"
+	})
+	return htmlLayoutPattern.ReplaceAllString(text, "")
+}
+
+// splitCodeBlocks splits markdown into its fenced and indented code blocks,
+// whose links are left alone, and the text between them.
+func splitCodeBlocks(content string) []markdownSegment {
+	segments := make([]markdownSegment, 0)
+	var current strings.Builder
+	flush := func(code bool) {
+		if current.Len() > 0 {
+			segments = append(segments, markdownSegment{text: current.String(), code: code})
+			current.Reset()
+		}
+	}
+
+	fence := ""
+	// Whether an indented code block is being split, and whether the line
+	// before was blank, as one must be before an indented code block.
+	indented := false
+	afterBlank := true
+	lists := listTracker{}
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		isFence := len(line)-len(trimmed) <= 3 &&
+			(strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"))
+		blank := strings.TrimSpace(line) == ""
+		if fence == "" {
+			if indented && !blank && indentation(line) < 4 {
+				flush(true)
+				indented = false
+			}
+			inList := lists.next(line)
+			if !indented && afterBlank && !blank && !inList && indentation(line) >= 4 {
+				flush(false)
+				indented = true
+			}
+			afterBlank = blank
+			if indented {
+				current.WriteString(line)
+				continue
+			}
+		}
+		switch {
+		case fence == "" && isFence:
+			flush(false)
+			fence = trimmed[:3]
+			current.WriteString(line)
+		case fence != "" && isFence && strings.HasPrefix(trimmed, fence):
+			current.WriteString(line)
+			flush(true)
+			fence = ""
+		default:
+			current.WriteString(line)
+		}
+	}
+	flush(fence != "" || indented)
+	return segments
+}
+
+// resolveLink resolves a relative link against base. Anchors within the
+// markdown are left as they are.
+func resolveLink(link string, base *url.URL) string {
+	if base == nil || link == "" || strings.HasPrefix(link, "#") {
+		return link
+	}
+	target, err := url.Parse(link)
+	if err != nil || target.IsAbs() || target.Host != "" {
+		return link
+	}
+	if strings.HasPrefix(target.Path, "/") {
+		// The repository's root is the start of base's path: /owner/name/blob/ref.
+		segments := strings.SplitN(strings.TrimPrefix(base.Path, "/"), "/", 5)
+		if len(segments) >= 4 {
+			target.Path = "/" + strings.Join(segments[:4], "/") + target.Path
+		}
+	}
+	return base.ResolveReference(target).String()
+}
+
+func htmlAttribute(pattern *regexp.Regexp, tag string) string {
+	if match := pattern.FindStringSubmatch(tag); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+func altText(alt string) string {
+	if alt = strings.TrimSpace(alt); alt != "" {
+		return alt
+	}
+	return "image"
+}
+
+// headingSlug returns the anchor GitHub gives a heading: lowercased, spaces
+// as dashes and other punctuation dropped.
+func headingSlug(heading string) string {
+	var slug strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case r == ' ':
+			slug.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			slug.WriteRune(r)
+		}
+	}
+	return slug.String()
+}
@@ -0,0 +1,91 @@
+package components
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestRewriteMarkdownLinks(t *testing.T) {
+	base, _ := url.Parse("https://github.com/owner/repo/blob/main/README.md")
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		links   []string
+	}{
+		{
+			name:    "relative link",
+			content: "See [the docs](docs/index.md).",
+			want:    "See [the docs](https://github.com/owner/repo/blob/main/docs/index.md).",
+			links:   []string{"https://github.com/owner/repo/blob/main/docs/index.md"},
+		},
+		{
+			name:    "code span",
+			content: "Write `<a href=\"x\">[y](z)</a> #1` to link.",
+			want:    "Write `<a href=\"x\">[y](z)</a> #1` to link.",
+		},
+		{
+			name:    "code span in a link",
+			content: "Run [`make`](Makefile).",
+			want:    "Run [`make`](https://github.com/owner/repo/blob/main/Makefile).",
+			links:   []string{"https://github.com/owner/repo/blob/main/Makefile"},
+		},
+		{
+			name:    "indented code block",
+			content: "Example:\n\n    <img src=\"a.png\" alt=\"a\">\n    [b](c)\n\nDone.",
+			want:    "Example:\n\n    <img src=\"a.png\" alt=\"a\">\n    [b](c)\n\nDone.",
+		},
+		{
+			name:    "fenced code block",
+			content: "```html\n<a href=\"x\">y</a>\n```\n",
+			want:    "```html\n<a href=\"x\">y</a>\n```\n",
+		},
+		{
+			name:    "centered badges",
+			content: "<p align=\"center\">\n    <a href=\"https://ci.example\"><img src=\"badge.svg\" alt=\"build\"></a>\n</p>",
+			want:    "\n[build](https://ci.example)\n",
+			links:   []string{"https://ci.example"},
+		},
+		{
+			name:    "HTML nested in a list",
+			content: "- Item\n\n    <a href=\"https://example.com\">link</a>",
+			want:    "- Item\n\n    [link](https://example.com)",
+			links:   []string{"https://example.com"},
+		},
+		{
+			name:    "issue references",
+			content: "Fixes #12 and other/project#3, not a#4.",
+			want:    "Fixes [#12](https://github.com/owner/repo/issues/12) and [other/project#3](https://github.com/other/project/issues/3), not a#4.",
+			links:   []string{"https://github.com/owner/repo/issues/12", "https://github.com/other/project/issues/3"},
+		},
+		{
+			name:    "issue reference in a link",
+			content: "[see #12](https://example.com)",
+			want:    "[see #12](https://example.com)",
+			links:   []string{"https://example.com"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, links := rewriteMarkdownLinks(test.content, base)
+			if got != test.want {
+				t.Errorf("rewriteMarkdownLinks() = %q, want %q", got, test.want)
+			}
+			urls := make([]string, len(links))
+			for i, link := range links {
+				urls[i] = link.url
+			}
+			if !slices.Equal(urls, test.links) && (len(urls) != 0 || len(test.links) != 0) {
+				t.Errorf("links %q, want %q", urls, test.links)
+			}
+		})
+	}
+}
+
+func TestRewriteMarkdownLinksWithoutBase(t *testing.T) {
+	got, links := rewriteMarkdownLinks("Fixes #12.", nil)
+	if got != "Fixes #12." || len(links) != 0 {
+		t.Errorf("rewriteMarkdownLinks() = %q with links %v, want references left alone", got, links)
+	}
+}
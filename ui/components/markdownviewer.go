@@ -1,14 +1,24 @@
 package components
 
 import (
+	"net/url"
+	"strings"
+
+	"github.com/alex-laycalvert/ghtui/ui/theme"
 	"github.com/alex-laycalvert/ghtui/utils"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/google/uuid"
 )
 
+// markdownViewerModel shows rendered markdown.
+//
+// Markdown given with a base URL has its links rewritten against it, and they
+// can be selected with ] and [, then tab and shift+tab, and followed with
+// enter. esc clears the selection.
 type markdownViewerModel struct {
 	id     string
 	width  int
@@ -19,11 +29,27 @@ type markdownViewerModel struct {
 	viewport viewport.Model
 	renderer *glamour.TermRenderer
 
+	// The content as it is rendered, and its links when they can be selected.
+	markdown string
+	links    []markdownLink
+	rendered string
+	// The link selected, -1 for none.
+	selected int
+
 	updateTimes int
 }
 
 type MarkdownViewerSetContentMsg struct {
 	Content string
+	// URL of the markdown on GitHub. When set, relative links and images are
+	// resolved against it and links can be followed.
+	BaseURL string
+}
+
+// Sent by the viewer when a link other than an anchor in the markdown is followed.
+type MarkdownViewerFollowLinkMsg struct {
+	ID  string
+	URL string
 }
 
 type markdownViewerUpdateMarkdownMsg struct {
@@ -35,7 +61,7 @@ func NewMarkdownViewerComponent(width int, height int, style lipgloss.Style) mar
 	viewport := viewport.New(width, height)
 	viewport.Style = style
 	renderer, _ := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
+		theme.MarkdownStyleOption(),
 	)
 	m := markdownViewerModel{
 		id:       "markdownViewer_" + uuid.NewString(),
@@ -43,6 +69,7 @@ func NewMarkdownViewerComponent(width int, height int, style lipgloss.Style) mar
 		height:   height,
 		viewport: viewport,
 		renderer: renderer,
+		selected: -1,
 	}
 	return m
 }
@@ -51,6 +78,11 @@ func (m markdownViewerModel) ID() string {
 	return m.id
 }
 
+// CapturesInput reports whether a link is selected, so tab moves between links.
+func (m markdownViewerModel) CapturesInput() bool {
+	return m.selected >= 0
+}
+
 func (m markdownViewerModel) Init() tea.Cmd {
 	return nil
 }
@@ -78,12 +110,41 @@ func (m markdownViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.height = msg.Height
 		}
 
-		m.viewport.Width = msg.Width
-		m.viewport.Height = msg.Height
+		m.viewport.Width = m.width
+		m.viewport.Height = m.height
 
 		return m, nil
 	case tea.KeyMsg:
 		switch keypress := msg.String(); keypress {
+		case "]", "tab":
+			if keypress == "]" || m.selected >= 0 {
+				m.selectLink(1)
+			}
+			return m, nil
+		case "[", "shift+tab":
+			if keypress == "[" || m.selected >= 0 {
+				m.selectLink(-1)
+			}
+			return m, nil
+		case "enter":
+			if m.selected < 0 {
+				return m, nil
+			}
+			link := m.links[m.selected].url
+			if strings.HasPrefix(link, "#") {
+				m.scrollToHeading(strings.TrimPrefix(link, "#"))
+				return m, nil
+			}
+			id := m.id
+			return m, func() tea.Msg {
+				return MarkdownViewerFollowLinkMsg{ID: id, URL: link}
+			}
+		case "esc":
+			if m.selected >= 0 {
+				m.selected = -1
+				m.render()
+			}
+			return m, nil
 		case "g":
 			m.viewport.GotoTop()
 			return m, nil
@@ -95,8 +156,12 @@ func (m markdownViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 	case MarkdownViewerSetContentMsg:
-		str, _ := m.renderer.Render(msg.Content)
-		m.viewport.SetContent(str)
+		m.markdown, m.links = msg.Content, nil
+		if base, err := url.Parse(msg.BaseURL); msg.BaseURL != "" && err == nil {
+			m.markdown, m.links = rewriteMarkdownLinks(msg.Content, base)
+		}
+		m.selected = -1
+		m.render()
 		return m, nil
 	}
 
@@ -106,3 +171,58 @@ func (m markdownViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m markdownViewerModel) View() string {
 	return m.viewport.View()
 }
+
+// render renders the markdown, marking the selected link and scrolling to it.
+func (m *markdownViewerModel) render() {
+	markdown := m.markdown
+	if m.selected >= 0 {
+		offset := m.links[m.selected].offset
+		markdown = markdown[:offset] + selectedLinkMarker + markdown[offset:]
+	}
+	m.rendered, _ = m.renderer.Render(markdown)
+	m.viewport.SetContent(m.rendered)
+	if m.selected < 0 {
+		return
+	}
+
+	// The marker is found by how many came before it, in case the markdown has some.
+	before := strings.Count(m.markdown[:m.links[m.selected].offset], strings.TrimSpace(selectedLinkMarker))
+	seen := 0
+	for i, line := range strings.Split(ansi.Strip(m.rendered), "\n") {
+		seen += strings.Count(line, strings.TrimSpace(selectedLinkMarker))
+		if seen > before {
+			if i < m.viewport.YOffset || i >= m.viewport.YOffset+m.viewport.Height {
+				m.viewport.SetYOffset(max(0, i-m.viewport.Height/2))
+			}
+			return
+		}
+	}
+}
+
+// selectLink selects the next link, or previous one with a negative step,
+// wrapping around.
+func (m *markdownViewerModel) selectLink(step int) {
+	if len(m.links) == 0 {
+		return
+	}
+	switch {
+	case m.selected < 0 && step < 0:
+		m.selected = len(m.links) - 1
+	case m.selected < 0:
+		m.selected = 0
+	default:
+		m.selected = (m.selected + step + len(m.links)) % len(m.links)
+	}
+	m.render()
+}
+
+// scrollToHeading scrolls to the heading whose anchor is anchor.
+func (m *markdownViewerModel) scrollToHeading(anchor string) {
+	anchor = strings.ToLower(anchor)
+	for i, line := range strings.Split(ansi.Strip(m.rendered), "\n") {
+		if headingSlug(strings.TrimLeft(strings.TrimSpace(line), "#")) == anchor {
+			m.viewport.SetYOffset(i)
+			return
+		}
+	}
+}
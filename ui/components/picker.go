@@ -0,0 +1,250 @@
+package components
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/alex-laycalvert/ghtui/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+var (
+	pickerQueryStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("205"))
+	pickerSelectedStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("230")).
+				Background(lipgloss.Color("62"))
+	pickerDetailStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("241"))
+)
+
+// An item of a `PickerModel`.
+type PickerItem struct {
+	Value string
+	// Shown after the value, and matched as well.
+	Detail string
+}
+
+// PickerModel picks one of a list of items by typing part of it. Items are
+// matched fuzzily with `utils.FuzzyMatch`, the best matches first, and in
+// the order given without a query.
+//
+// The arrow keys, ctrl+n and ctrl+p move between matches, enter picks one
+// with `PickerSelectMsg` and esc cancels with `PickerCancelMsg`. Enter picks
+// the query itself when nothing matches it.
+type PickerModel struct {
+	id     string
+	width  int
+	height int
+
+	title              string
+	query              string
+	items              []PickerItem
+	matches            []PickerItem
+	cursorIndex        int
+	viewportStartIndex int
+}
+
+// PickerResetMsg shows new items and clears the query.
+type PickerResetMsg struct {
+	Title string
+	Items []PickerItem
+}
+
+// PickerSetItemsMsg replaces the items, keeping the query.
+type PickerSetItemsMsg struct {
+	Items []PickerItem
+}
+
+type PickerSelectMsg struct {
+	ID    string
+	Value string
+}
+
+type PickerCancelMsg struct {
+	ID string
+}
+
+func NewPickerComponent(width int, height int) PickerModel {
+	return PickerModel{
+		id:     "picker_" + uuid.NewString(),
+		width:  width,
+		height: height,
+	}
+}
+
+func (m PickerModel) ID() string {
+	return m.id
+}
+
+func (m PickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m PickerModel) CapturesInput() bool {
+	return true
+}
+
+func (m PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case PickerResetMsg:
+		m.title = msg.Title
+		m.query = ""
+		m.items = msg.Items
+		m.match()
+		return m, nil
+	case PickerSetItemsMsg:
+		m.items = msg.Items
+		m.match()
+		return m, nil
+	case utils.UpdateSizeMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+
+		if msg.Width > 0 {
+			m.width = msg.Width
+		}
+		if msg.Height > 0 {
+			m.height = msg.Height
+		}
+		return m, nil
+	case tea.KeyMsg:
+		switch keypress := msg.String(); keypress {
+		case "enter":
+			value := strings.TrimSpace(m.query)
+			if m.cursorIndex < len(m.matches) {
+				value = m.matches[m.cursorIndex].Value
+			}
+			if value == "" {
+				return m, nil
+			}
+			id := m.id
+			return m, func() tea.Msg {
+				return PickerSelectMsg{ID: id, Value: value}
+			}
+		case "esc":
+			id := m.id
+			return m, func() tea.Msg {
+				return PickerCancelMsg{ID: id}
+			}
+		case "down", "ctrl+n":
+			m.moveCursor(1)
+			return m, nil
+		case "up", "ctrl+p":
+			m.moveCursor(-1)
+			return m, nil
+		case "backspace":
+			runes := []rune(m.query)
+			if len(runes) > 0 {
+				m.query = string(runes[:len(runes)-1])
+				m.match()
+			}
+			return m, nil
+		case "ctrl+u":
+			m.query = ""
+			m.match()
+			return m, nil
+		case "ctrl+w":
+			trimmed := strings.TrimRight(m.query, " ")
+			m.query = trimmed[:strings.LastIndex(trimmed, " ")+1]
+			m.match()
+			return m, nil
+		default:
+			if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+				m.query += string(msg.Runes)
+				m.match()
+			}
+			return m, nil
+		}
+	}
+
+	return m, nil
+}
+
+// match finds the items matching the query and moves the cursor to the best.
+func (m *PickerModel) match() {
+	m.cursorIndex = 0
+	m.viewportStartIndex = 0
+	if strings.TrimSpace(m.query) == "" {
+		m.matches = m.items
+		return
+	}
+
+	type scoredItem struct {
+		item  PickerItem
+		score int
+	}
+	scored := make([]scoredItem, 0, len(m.items))
+	for _, item := range m.items {
+		// The value matters more than the detail.
+		score, ok := utils.FuzzyMatch(m.query, item.Value)
+		if !ok {
+			score, ok = utils.FuzzyMatch(m.query, item.Value+" "+item.Detail)
+			score /= 2
+		}
+		if ok {
+			scored = append(scored, scoredItem{item: item, score: score})
+		}
+	}
+	slices.SortStableFunc(scored, func(a, b scoredItem) int {
+		return cmp.Compare(b.score, a.score)
+	})
+	m.matches = make([]PickerItem, len(scored))
+	for i, s := range scored {
+		m.matches[i] = s.item
+	}
+}
+
+func (m *PickerModel) moveCursor(delta int) {
+	m.cursorIndex = max(0, min(len(m.matches)-1, m.cursorIndex+delta))
+	listHeight := m.listHeight()
+	if m.cursorIndex < m.viewportStartIndex {
+		m.viewportStartIndex = m.cursorIndex
+	}
+	if m.cursorIndex >= m.viewportStartIndex+listHeight {
+		m.viewportStartIndex = m.cursorIndex - listHeight + 1
+	}
+}
+
+// listHeight is the number of matches shown under the title, query and hint.
+func (m PickerModel) listHeight() int {
+	return max(1, m.height-3)
+}
+
+func (m PickerModel) View() string {
+	line := lipgloss.NewStyle().MaxWidth(m.width).MaxHeight(1)
+	rows := []string{
+		line.Bold(true).Render(m.title),
+		line.Render(pickerQueryStyle.Render("> ") + m.query + "█"),
+	}
+
+	end := min(len(m.matches), m.viewportStartIndex+m.listHeight())
+	for i := m.viewportStartIndex; i < end; i++ {
+		item := m.matches[i]
+		text := item.Value
+		if i == m.cursorIndex {
+			text = pickerSelectedStyle.Render(text)
+		}
+		if item.Detail != "" {
+			text += pickerDetailStyle.Render("  " + item.Detail)
+		}
+		rows = append(rows, line.Render(text))
+	}
+	if len(m.matches) == 0 {
+		hint := "No matches"
+		if strings.TrimSpace(m.query) != "" {
+			hint += ", enter picks " + strings.TrimSpace(m.query)
+		}
+		rows = append(rows, pickerDetailStyle.Render(hint))
+	}
+	rows = append(rows, pickerDetailStyle.Render("enter pick · ↑/↓ move · esc cancel"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		MaxHeight(m.height).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
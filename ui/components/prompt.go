@@ -0,0 +1,125 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/alex-laycalvert/ghtui/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+var (
+	promptStyle = lipgloss.NewStyle().
+			Bold(true)
+	promptKeyStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("205"))
+	promptLabelStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("241"))
+)
+
+// One of the answers to a `PromptModel`, chosen by pressing `Key`.
+type PromptOption struct {
+	Key   string
+	Label string
+	Value string
+}
+
+// PromptModel asks a single line question answered with one key press, e.g. to
+// confirm an action or pick between a few choices.
+//
+// Choosing an option sends `PromptAnswerMsg` with its value, esc sends it with
+// an empty value. A prompt is reused by sending it `PromptResetMsg`.
+type PromptModel struct {
+	id    string
+	width int
+
+	question string
+	options  []PromptOption
+}
+
+type PromptResetMsg struct {
+	Question string
+	Options  []PromptOption
+}
+
+type PromptAnswerMsg struct {
+	ID string
+	// Value of the chosen option, empty if the prompt was cancelled.
+	Value string
+}
+
+// ConfirmOptions are the options of a yes/no prompt, answering "yes" or "no".
+var ConfirmOptions = []PromptOption{
+	{Key: "y", Label: "yes", Value: "yes"},
+	{Key: "n", Label: "no", Value: "no"},
+}
+
+func NewPromptComponent(width int) PromptModel {
+	return PromptModel{
+		id:    "prompt_" + uuid.NewString(),
+		width: width,
+	}
+}
+
+func (m PromptModel) ID() string {
+	return m.id
+}
+
+func (m PromptModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m PromptModel) CapturesInput() bool {
+	return true
+}
+
+func (m PromptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case PromptResetMsg:
+		m.question = msg.Question
+		m.options = msg.Options
+		return m, nil
+	case utils.UpdateSizeMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+
+		if msg.Width > 0 {
+			m.width = msg.Width
+		}
+		return m, nil
+	case tea.KeyMsg:
+		keypress := msg.String()
+		if keypress == "esc" {
+			return m, m.answer("")
+		}
+		for _, option := range m.options {
+			if option.Key == keypress {
+				return m, m.answer(option.Value)
+			}
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m PromptModel) answer(value string) tea.Cmd {
+	id := m.id
+	return func() tea.Msg {
+		return PromptAnswerMsg{ID: id, Value: value}
+	}
+}
+
+func (m PromptModel) View() string {
+	options := make([]string, 0, len(m.options)+1)
+	for _, option := range m.options {
+		options = append(options, promptKeyStyle.Render(option.Key)+" "+promptLabelStyle.Render(option.Label))
+	}
+	options = append(options, promptKeyStyle.Render("esc")+" "+promptLabelStyle.Render("cancel"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Render(promptStyle.Render(m.question) + "  " + strings.Join(options, promptLabelStyle.Render(" · ")))
+}
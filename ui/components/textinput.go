@@ -42,6 +42,10 @@ func (m TextInputComponent) ID() string {
 	return m.id
 }
 
+func (m TextInputComponent) CapturesInput() bool {
+	return true
+}
+
 func (m TextInputComponent) Init() tea.Cmd {
 	return nil
 }
@@ -0,0 +1,62 @@
+// Package highlight colors source code with chroma, using the style of the
+// current theme.
+package highlight
+
+import (
+	"path/filepath"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alex-laycalvert/ghtui/ui/theme"
+)
+
+// Lexer returns the lexer for the file at path, falling back to plain text.
+func Lexer(path string) chroma.Lexer {
+	lexer := lexers.Match(filepath.Base(path))
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	return chroma.Coalesce(lexer)
+}
+
+// Style returns the chroma style of the current theme.
+func Style() *chroma.Style {
+	name := theme.Current.SyntaxStyle
+	if name == "auto" {
+		name = "github"
+		if lipgloss.HasDarkBackground() {
+			name = "github-dark"
+		}
+	}
+	return styles.Get(name)
+}
+
+// Colors returns the foreground color of each rune of text as a hex string,
+// empty for runes in the terminal's default color.
+//
+// text is tokenised on its own, so constructs spanning several lines, such as
+// block comments, are only colored on the lines their delimiters are on.
+func Colors(lexer chroma.Lexer, style *chroma.Style, text string) []string {
+	colors := make([]string, 0, len(text))
+	iterator, err := lexer.Tokenise(nil, text)
+	if err != nil {
+		for range []rune(text) {
+			colors = append(colors, "")
+		}
+		return colors
+	}
+
+	for _, token := range iterator.Tokens() {
+		color := ""
+		if entry := style.Get(token.Type); entry.Colour.IsSet() {
+			color = entry.Colour.String()
+		}
+		for range []rune(token.Value) {
+			colors = append(colors, color)
+		}
+	}
+	return colors
+}
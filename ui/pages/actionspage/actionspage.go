@@ -0,0 +1,536 @@
+package actionspage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+// How often runs and jobs that did not finish are checked again.
+const pollInterval = 5 * time.Second
+
+const runsPerPage = 50
+
+var (
+	chipStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("230")).
+			Background(lipgloss.Color("62")).
+			Padding(0, 1).
+			MarginRight(1)
+	titleStyle       = lipgloss.NewStyle().Bold(true)
+	successStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	failureStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	pendingStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	mutedStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	noticeStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	noticeErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+type view int
+
+const (
+	runsView view = iota
+	workflowsView
+	jobsView
+	logView
+)
+
+type ActionsPageModel struct {
+	id     string
+	width  int
+	height int
+
+	repo    gh.Repo
+	actions gh.ActionsService
+	state   utils.ComponentState
+	view    view
+	filters runFilters
+	// Page of runs shown, from 1.
+	page      int
+	total     int
+	workflows []*github.Workflow
+	// Run whose jobs are shown.
+	run  *github.WorkflowRun
+	jobs []*github.WorkflowJob
+	// Job whose log is shown, and its step, nil for the whole job.
+	logJob  *github.WorkflowJob
+	logStep *github.TaskStep
+	// Whether the log was loaded, is loading, or failed to load.
+	logLoaded  bool
+	logLoading bool
+	logError   string
+	// Increased whenever a check is scheduled, so only the latest one runs.
+	poll int
+	// The re-run, cancellation or dispatch being prepared.
+	task          *actionTask
+	notice        string
+	noticeIsError bool
+
+	componentGroup         utils.ComponentGroup
+	spinnerComponent       string
+	errorPanelComponent    string
+	runsListComponent      string
+	workflowsListComponent string
+	jobsListComponent      string
+	logViewerComponent     string
+	filterFormComponent    string
+	dispatchFormComponent  string
+	promptComponent        string
+}
+
+type runsLoadingMsg struct{}
+
+type runsReadyMsg struct {
+	page gh.RunPage
+}
+
+type workflowsReadyMsg struct {
+	workflows []*github.Workflow
+}
+
+type jobsReadyMsg struct {
+	runID int64
+	jobs  []*github.WorkflowJob
+}
+
+type logReadyMsg struct {
+	jobID int64
+	log   string
+	err   error
+}
+
+// Sent after `pollInterval` to check the runs or jobs shown again.
+type pollMsg struct {
+	poll int
+}
+
+func NewActionsPage(id string, actions gh.ActionsService, repo gh.Repo, width int, height int) ActionsPageModel {
+	spinner := components.NewSpinnerComponent()
+	errorPanel := components.NewErrorPanelComponent(width)
+	runsList := components.NewListComponent(width, height-1, renderRun)
+	workflowsList := components.NewListComponent(width, height-1, renderWorkflow)
+	jobsList := components.NewListComponent(width, height-1, renderJobRow)
+	logViewer := components.NewLogViewerComponent(width, height)
+	filterForm := components.NewFormComponent(width)
+	dispatchForm := components.NewFormComponent(width)
+	prompt := components.NewPromptComponent(width)
+
+	return ActionsPageModel{
+		id:      id,
+		width:   width,
+		height:  height,
+		repo:    repo,
+		actions: actions,
+		state:   utils.LoadingState,
+		page:    1,
+		componentGroup: utils.NewComponentGroup(
+			spinner,
+			errorPanel,
+			runsList,
+			workflowsList,
+			jobsList,
+			logViewer,
+			filterForm,
+			dispatchForm,
+			prompt,
+		),
+		spinnerComponent:       spinner.ID(),
+		errorPanelComponent:    errorPanel.ID(),
+		runsListComponent:      runsList.ID(),
+		workflowsListComponent: workflowsList.ID(),
+		jobsListComponent:      jobsList.ID(),
+		logViewerComponent:     logViewer.ID(),
+		filterFormComponent:    filterForm.ID(),
+		dispatchFormComponent:  dispatchForm.ID(),
+		promptComponent:        prompt.ID(),
+	}
+}
+
+func (m ActionsPageModel) ID() string {
+	return m.id
+}
+
+// CapturesInput reports whether a form or the prompt is taking input.
+func (m ActionsPageModel) CapturesInput() bool {
+	return m.componentGroup.CapturesInput()
+}
+
+func (m ActionsPageModel) Init() tea.Cmd {
+	return tea.Sequence(
+		m.fetchRuns(),
+		m.fetchWorkflows(),
+		m.componentGroup.Init(),
+	)
+}
+
+func (m ActionsPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case utils.PageMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+		return m.Update(msg.Msg)
+	case utils.FocusMsg:
+		if m.id != msg.ID {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+		return m, m.refresh()
+	case utils.BlurMsg:
+		if m.id != msg.ID {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+		// Stop checking runs while the page is hidden.
+		m.poll++
+		return m, nil
+	case utils.SuspendMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+		m.poll++
+		return m, nil
+	case utils.UpdateSizeMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+
+		if msg.Width == 0 && msg.Height == 0 {
+			return m, nil
+		}
+
+		if msg.Width > 0 {
+			m.width = msg.Width
+		}
+		if msg.Height > 0 {
+			m.height = msg.Height
+		}
+
+		cmds := make([]tea.Cmd, 0)
+		for _, list := range []string{m.runsListComponent, m.workflowsListComponent, m.jobsListComponent} {
+			cmds = append(cmds, m.componentGroup.Update(list, utils.UpdateSizeMsg{
+				ID:     list,
+				Width:  m.width,
+				Height: m.height - 1,
+			}))
+		}
+		for _, component := range []string{m.errorPanelComponent, m.filterFormComponent, m.dispatchFormComponent, m.promptComponent} {
+			cmds = append(cmds, m.componentGroup.Update(component, utils.UpdateSizeMsg{
+				ID:    component,
+				Width: m.width,
+			}))
+		}
+		cmds = append(cmds, m.componentGroup.Update(m.logViewerComponent, utils.UpdateSizeMsg{
+			ID:     m.logViewerComponent,
+			Width:  m.width,
+			Height: m.height,
+		}))
+		return m, tea.Batch(cmds...)
+	case tea.KeyMsg:
+		m.notice = ""
+		if m.componentGroup.CapturesInput() {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+		if m.state == utils.ErrorState {
+			if msg.String() == "r" {
+				return m, m.fetchRuns()
+			}
+			return m, nil
+		}
+		if m.state != utils.ReadyState {
+			return m, nil
+		}
+
+		switch m.view {
+		case runsView:
+			return m, m.handleRunsKey(msg)
+		case workflowsView:
+			return m, m.handleWorkflowsKey(msg)
+		case jobsView:
+			return m, m.handleJobsKey(msg)
+		default:
+			return m, m.handleLogKey(msg)
+		}
+	case runsLoadingMsg:
+		m.state = utils.LoadingState
+		return m, m.componentGroup.FocusOn(m.spinnerComponent)
+	case runsReadyMsg:
+		m.total = msg.page.Total
+		cmds := []tea.Cmd{
+			m.componentGroup.Update(m.runsListComponent, components.ListSetItemsMsg[*github.WorkflowRun]{
+				Items: msg.page.Runs,
+			}),
+		}
+		if m.state != utils.ReadyState {
+			m.state = utils.ReadyState
+			cmds = append(cmds, m.componentGroup.FocusOn(m.runsListComponent))
+		}
+		for _, run := range msg.page.Runs {
+			if run.GetStatus() != "completed" && m.view == runsView {
+				cmds = append(cmds, m.schedulePoll())
+				break
+			}
+		}
+		return m, tea.Batch(cmds...)
+	case workflowsReadyMsg:
+		m.workflows = msg.workflows
+		return m, nil
+	case jobsReadyMsg:
+		if m.run == nil || m.run.GetID() != msg.runID {
+			return m, nil
+		}
+		return m, m.handleJobsReady(msg)
+	case logReadyMsg:
+		if m.logJob == nil || m.logJob.GetID() != msg.jobID {
+			return m, nil
+		}
+		m.logLoading = false
+		if msg.err != nil {
+			m.logError = msg.err.Error()
+			return m, nil
+		}
+		m.logLoaded = true
+		m.logError = ""
+		return m, m.componentGroup.Update(m.logViewerComponent, components.LogViewerSetLogMsg{
+			Title:    m.logTitle(),
+			Sections: splitJobLog(msg.log, m.logJob, m.logStep),
+		})
+	case pollMsg:
+		if m.poll != msg.poll || m.state != utils.ReadyState {
+			return m, nil
+		}
+		if m.view == runsView {
+			return m, m.fetchRuns()
+		}
+		if m.run != nil {
+			return m, m.fetchJobs(m.run.GetID())
+		}
+		return m, nil
+	case components.FormSubmitMsg:
+		switch msg.ID {
+		case m.filterFormComponent:
+			return m, m.handleFilterForm(msg.Values)
+		case m.dispatchFormComponent:
+			return m, m.handleDispatchForm(msg.Values)
+		}
+		return m, nil
+	case components.FormCancelMsg:
+		if msg.ID == m.dispatchFormComponent {
+			m.task = nil
+		}
+		return m, m.componentGroup.FocusOn(m.runsListComponent)
+	case components.PromptAnswerMsg:
+		if m.promptComponent != msg.ID || m.task == nil {
+			return m, nil
+		}
+		return m, m.handlePromptAnswer(msg.Value)
+	case dispatchReadyMsg:
+		return m, m.handleDispatchReady(msg)
+	case actionDoneMsg:
+		m.setNotice(msg.notice, false)
+		return m, m.schedulePoll()
+	case actionFailedMsg:
+		m.setNotice(fmt.Sprintf("Failed to %s: %s", msg.action, msg.err), true)
+		return m, nil
+	case utils.ErrorMsg:
+		if m.id != msg.Source {
+			return m, m.componentGroup.UpdateAll(msg)
+		}
+
+		m.state = utils.ErrorState
+		m.view = runsView
+		m.poll++
+		return m, tea.Batch(
+			m.componentGroup.Update(m.errorPanelComponent, components.ErrorPanelSetErrorMsg{Err: msg}),
+			m.componentGroup.FocusOn(m.errorPanelComponent),
+		)
+	default:
+		return m, m.componentGroup.UpdateAll(msg)
+	}
+}
+
+func (m ActionsPageModel) View() string {
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Render(m.body())
+}
+
+func (m ActionsPageModel) body() string {
+	switch m.state {
+	case utils.LoadingState:
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(fmt.Sprintf(
+				"%s Loading workflow runs of %s",
+				m.componentGroup.GetComponent(m.spinnerComponent).View(),
+				m.repo,
+			))
+	case utils.ErrorState:
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(m.componentGroup.GetComponent(m.errorPanelComponent).View())
+	}
+
+	var body string
+	switch {
+	case m.componentGroup.IsFocused(m.filterFormComponent):
+		body = m.componentGroup.GetComponent(m.filterFormComponent).View()
+	case m.componentGroup.IsFocused(m.dispatchFormComponent):
+		body = m.componentGroup.GetComponent(m.dispatchFormComponent).View()
+	case m.view == workflowsView:
+		body = lipgloss.JoinVertical(
+			lipgloss.Left,
+			titleStyle.Render("Show the runs of")+mutedStyle.Render(" · enter select · esc back"),
+			m.componentGroup.GetComponent(m.workflowsListComponent).View(),
+		)
+	case m.view == jobsView:
+		body = lipgloss.JoinVertical(
+			lipgloss.Left,
+			lipgloss.NewStyle().MaxWidth(m.width).MaxHeight(1).Render(m.runHeader()),
+			m.componentGroup.GetComponent(m.jobsListComponent).View(),
+		)
+	case m.view == logView:
+		body = m.logBody()
+	default:
+		body = lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.chips(),
+			m.componentGroup.GetComponent(m.runsListComponent).View(),
+		)
+	}
+
+	var footer string
+	if m.componentGroup.IsFocused(m.promptComponent) {
+		footer = m.componentGroup.GetComponent(m.promptComponent).View()
+	} else if m.notice != "" {
+		style := noticeStyle
+		if m.noticeIsError {
+			style = noticeErrorStyle
+		}
+		footer = style.Width(m.width).MaxHeight(1).Render(m.notice)
+	}
+	if footer == "" {
+		return body
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lipgloss.NewStyle().MaxHeight(max(0, m.height-1)).Render(body),
+		footer,
+	)
+}
+
+// logBody shows the log, or why it is not shown yet.
+func (m ActionsPageModel) logBody() string {
+	spinner := m.componentGroup.GetComponent(m.spinnerComponent).View()
+	switch {
+	case m.logLoaded:
+		return m.componentGroup.GetComponent(m.logViewerComponent).View()
+	case m.logError != "":
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			titleStyle.Render(m.logTitle()),
+			failureStyle.Render("Failed to load the log: "+m.logError),
+			mutedStyle.Render("r retry · esc back"),
+		)
+	case m.logJob.GetStatus() != "completed":
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			titleStyle.Render(m.logTitle()),
+			fmt.Sprintf("%s The job is %s, its log is shown once it finishes.", spinner, describeStatus(m.logJob.GetStatus(), "")),
+			mutedStyle.Render("esc back"),
+		)
+	default:
+		return fmt.Sprintf("%s Loading the log of %s", spinner, m.logJob.GetName())
+	}
+}
+
+// refresh lists the runs or jobs shown again.
+func (m *ActionsPageModel) refresh() tea.Cmd {
+	if m.state == utils.ReadyState && m.view != runsView && m.run != nil {
+		return m.fetchJobs(m.run.GetID())
+	}
+	return m.fetchRuns()
+}
+
+// schedulePoll checks the runs or jobs shown again after `pollInterval`,
+// replacing any check already scheduled.
+func (m *ActionsPageModel) schedulePoll() tea.Cmd {
+	m.poll++
+	poll := m.poll
+	return utils.PageCmd(m.id, tea.Tick(pollInterval, func(time.Time) tea.Msg {
+		return pollMsg{poll: poll}
+	}))
+}
+
+// fetchRuns lists the current page of runs, showing the loading spinner
+// unless runs are already shown.
+func (m *ActionsPageModel) fetchRuns() tea.Cmd {
+	search := gh.RunSearch{
+		WorkflowID: m.filters.WorkflowID,
+		Branch:     m.filters.Branch,
+		Event:      m.filters.Event,
+		Status:     m.filters.Status,
+		Page:       m.page,
+		PerPage:    runsPerPage,
+	}
+
+	cmds := make([]tea.Cmd, 0, 2)
+	if m.state != utils.ReadyState {
+		cmds = append(cmds, utils.PageCmd(m.id, func() tea.Msg { return runsLoadingMsg{} }))
+	}
+	cmds = append(cmds, utils.PageCmd(m.id, func() tea.Msg {
+		page, err := m.actions.ListRuns(context.Background(), m.repo, search)
+		if err != nil {
+			return utils.NewErrorMsg(m.id, err)
+		}
+		return runsReadyMsg{page: page}
+	}))
+	return tea.Sequence(cmds...)
+}
+
+func (m *ActionsPageModel) fetchWorkflows() tea.Cmd {
+	return utils.PageCmd(m.id, func() tea.Msg {
+		workflows, err := m.actions.ListWorkflows(context.Background(), m.repo)
+		if err != nil {
+			return utils.NewErrorMsg(m.id, err)
+		}
+		return workflowsReadyMsg{workflows: workflows}
+	})
+}
+
+func (m *ActionsPageModel) fetchJobs(runID int64) tea.Cmd {
+	return utils.PageCmd(m.id, func() tea.Msg {
+		jobs, err := m.actions.ListJobs(context.Background(), m.repo, runID)
+		if err != nil {
+			return utils.NewErrorMsg(m.id, err)
+		}
+		return jobsReadyMsg{runID: runID, jobs: jobs}
+	})
+}
+
+func (m *ActionsPageModel) fetchLog(jobID int64) tea.Cmd {
+	m.logLoading = true
+	m.logError = ""
+	return utils.PageCmd(m.id, func() tea.Msg {
+		log, err := m.actions.GetJobLog(context.Background(), m.repo, jobID)
+		return logReadyMsg{jobID: jobID, log: log, err: err}
+	})
+}
+
+func (m *ActionsPageModel) setNotice(notice string, isError bool) {
+	m.notice = notice
+	m.noticeIsError = isError
+}
@@ -0,0 +1,292 @@
+package actionspage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+type actionTaskKind int
+
+const (
+	rerunTask actionTaskKind = iota
+	cancelTask
+	dispatchTask
+)
+
+// actionTask is the re-run, cancellation or dispatch being prepared through
+// the prompt or the dispatch form.
+type actionTask struct {
+	kind actionTaskKind
+	run  *github.WorkflowRun
+	// Workflow dispatched and how.
+	workflow *github.Workflow
+	dispatch gh.WorkflowDispatch
+	// Component to focus again once the task is done.
+	returnFocus string
+}
+
+type dispatchReadyMsg struct {
+	workflow *github.Workflow
+	dispatch gh.WorkflowDispatch
+	// Values submitted for another ref, nil when the form is first shown.
+	values []string
+	err    error
+}
+
+// Sent once a re-run, cancellation or dispatch was accepted.
+type actionDoneMsg struct {
+	notice string
+}
+
+type actionFailedMsg struct {
+	action string
+	err    error
+}
+
+// startRerun asks whether to re-run all the jobs of run or only the failed ones.
+func (m *ActionsPageModel) startRerun(run *github.WorkflowRun) tea.Cmd {
+	if run.GetStatus() != "completed" {
+		m.setNotice(fmt.Sprintf("#%d is still %s", run.GetRunNumber(), describeStatus(run.GetStatus(), "")), true)
+		return nil
+	}
+	options := []components.PromptOption{{Key: "a", Label: "all jobs", Value: "all"}}
+	if run.GetConclusion() == "failure" || run.GetConclusion() == "cancelled" || run.GetConclusion() == "timed_out" {
+		options = append(options, components.PromptOption{Key: "f", Label: "failed jobs", Value: "failed"})
+	}
+	m.task = &actionTask{kind: rerunTask, run: run, returnFocus: m.componentGroup.GetFocusedComponentName()}
+	return m.showPrompt(fmt.Sprintf("Re-run %s #%d:", run.GetName(), run.GetRunNumber()), options)
+}
+
+// startCancel asks to confirm cancelling run.
+func (m *ActionsPageModel) startCancel(run *github.WorkflowRun) tea.Cmd {
+	if run.GetStatus() == "completed" {
+		m.setNotice(fmt.Sprintf("#%d already finished", run.GetRunNumber()), true)
+		return nil
+	}
+	m.task = &actionTask{kind: cancelTask, run: run, returnFocus: m.componentGroup.GetFocusedComponentName()}
+	return m.showPrompt(fmt.Sprintf("Cancel %s #%d?", run.GetName(), run.GetRunNumber()), components.ConfirmOptions)
+}
+
+// startDispatch reads the inputs of the workflow the runs are filtered by,
+// to ask for them in the dispatch form.
+func (m *ActionsPageModel) startDispatch() tea.Cmd {
+	var workflow *github.Workflow
+	for _, candidate := range m.workflows {
+		if candidate.GetID() == m.filters.WorkflowID {
+			workflow = candidate
+		}
+	}
+	if workflow == nil {
+		m.setNotice("Pick the workflow to run with w first", true)
+		return nil
+	}
+
+	m.setNotice(fmt.Sprintf("Reading the inputs of %s…", workflow.GetName()), false)
+	return m.readDispatch(workflow, m.filters.Branch, nil)
+}
+
+// readDispatch reads the inputs of workflow on ref, the default branch if
+// empty, keeping the values submitted for another ref.
+func (m *ActionsPageModel) readDispatch(workflow *github.Workflow, ref string, values []string) tea.Cmd {
+	repo := m.repo
+	return utils.PageCmd(m.id, func() tea.Msg {
+		dispatch, err := m.actions.GetDispatch(context.Background(), repo, workflow, ref)
+		return dispatchReadyMsg{workflow: workflow, dispatch: dispatch, values: values, err: err}
+	})
+}
+
+// handleDispatchReady shows the dispatch form with a field for the ref and
+// each input. Once read again for the ref submitted, the workflow runs if its
+// inputs are the same there, and the form is shown again otherwise.
+func (m *ActionsPageModel) handleDispatchReady(msg dispatchReadyMsg) tea.Cmd {
+	resubmitted := msg.values != nil
+	if resubmitted && (m.task == nil || m.task.kind != dispatchTask) {
+		// The form was cancelled meanwhile.
+		return nil
+	}
+	m.notice = ""
+	switch {
+	case msg.err != nil:
+		m.setNotice("Failed to read the workflow: "+msg.err.Error(), true)
+		return nil
+	case !msg.dispatch.Dispatchable:
+		m.setNotice(fmt.Sprintf("%s cannot be run manually, it has no workflow_dispatch trigger on %s", msg.workflow.GetName(), msg.dispatch.Ref), true)
+		return nil
+	}
+
+	// Values typed for the inputs, by name.
+	typed := map[string]string{}
+	if resubmitted {
+		if slices.EqualFunc(m.task.dispatch.Inputs, msg.dispatch.Inputs, sameInput) {
+			m.task.dispatch = msg.dispatch
+			return m.handleDispatchForm(msg.values)
+		}
+		for i, input := range m.task.dispatch.Inputs {
+			typed[input.Name] = msg.values[i+1]
+		}
+		m.setNotice(fmt.Sprintf("The inputs of %s differ on %s, check them and submit again", msg.workflow.GetName(), msg.dispatch.Ref), true)
+	}
+
+	fields := []components.FormField{{
+		Label: "Ref",
+		Value: msg.dispatch.Ref,
+		Hint:  fmt.Sprintf("Branch or tag to run on. Inputs were read from the workflow on %s.", msg.dispatch.Ref),
+	}}
+	for _, input := range msg.dispatch.Inputs {
+		hint := []string{}
+		if input.Description != "" {
+			hint = append(hint, strings.ReplaceAll(input.Description, "\n", " "))
+		}
+		kind := input.Type
+		if input.Required {
+			kind += ", required"
+		}
+		hint = append(hint, kind)
+		if len(input.Options) > 0 {
+			hint = append(hint, "one of "+strings.Join(input.Options, ", "))
+		}
+		value, ok := typed[input.Name]
+		if !ok {
+			value = input.Default
+		}
+		fields = append(fields, components.FormField{
+			Label: input.Name,
+			Value: value,
+			Hint:  strings.Join(hint, " · "),
+		})
+	}
+
+	m.task = &actionTask{
+		kind:        dispatchTask,
+		workflow:    msg.workflow,
+		dispatch:    msg.dispatch,
+		returnFocus: m.runsListComponent,
+	}
+	return tea.Sequence(
+		m.componentGroup.Update(m.dispatchFormComponent, components.FormResetMsg{
+			Title:  "Run " + msg.workflow.GetName(),
+			Fields: fields,
+		}),
+		m.componentGroup.FocusOn(m.dispatchFormComponent),
+	)
+}
+
+// handleDispatchForm checks the inputs against their types and runs the workflow.
+func (m *ActionsPageModel) handleDispatchForm(values []string) tea.Cmd {
+	task := m.task
+	if task == nil || task.kind != dispatchTask {
+		return nil
+	}
+	ref := values[0]
+	if ref == "" {
+		m.setNotice("A ref is required", true)
+		return nil
+	}
+	if ref != task.dispatch.Ref {
+		// The workflow, and so its inputs, may differ on another ref.
+		m.setNotice(fmt.Sprintf("Reading the inputs of %s on %s…", task.workflow.GetName(), ref), false)
+		return m.readDispatch(task.workflow, ref, values)
+	}
+
+	inputs := map[string]any{}
+	for i, input := range task.dispatch.Inputs {
+		value := values[i+1]
+		if value == "" {
+			if input.Required {
+				m.setNotice(fmt.Sprintf("%s is required", input.Name), true)
+				return nil
+			}
+			continue
+		}
+		switch input.Type {
+		case "boolean":
+			if value != "true" && value != "false" {
+				m.setNotice(fmt.Sprintf("%s must be true or false", input.Name), true)
+				return nil
+			}
+		case "number":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				m.setNotice(fmt.Sprintf("%s must be a number", input.Name), true)
+				return nil
+			}
+		case "choice":
+			if !slices.Contains(input.Options, value) {
+				m.setNotice(fmt.Sprintf("%s must be one of %s", input.Name, strings.Join(input.Options, ", ")), true)
+				return nil
+			}
+		}
+		inputs[input.Name] = value
+	}
+
+	m.task = nil
+	repo := m.repo
+	workflow := task.workflow
+	m.setNotice(fmt.Sprintf("Running %s on %s…", workflow.GetName(), ref), false)
+	return tea.Batch(
+		m.componentGroup.FocusOn(task.returnFocus),
+		utils.PageCmd(m.id, func() tea.Msg {
+			if err := m.actions.DispatchWorkflow(context.Background(), repo, workflow.GetID(), ref, inputs); err != nil {
+				return actionFailedMsg{action: "run " + workflow.GetName(), err: err}
+			}
+			return actionDoneMsg{notice: fmt.Sprintf("%s started on %s, its run shows up shortly", workflow.GetName(), ref)}
+		}),
+	)
+}
+
+// sameInput reports whether two inputs are asked for and checked the same way.
+func sameInput(a gh.WorkflowInput, b gh.WorkflowInput) bool {
+	return a.Name == b.Name && a.Type == b.Type && a.Required == b.Required && slices.Equal(a.Options, b.Options)
+}
+
+// handlePromptAnswer re-runs or cancels the run once confirmed.
+func (m *ActionsPageModel) handlePromptAnswer(value string) tea.Cmd {
+	task := m.task
+	m.task = nil
+	focus := m.componentGroup.FocusOn(task.returnFocus)
+	if value == "" || value == "no" {
+		return focus
+	}
+
+	repo := m.repo
+	run := task.run
+	return tea.Batch(focus, utils.PageCmd(m.id, func() tea.Msg {
+		ctx := context.Background()
+		switch {
+		case task.kind == cancelTask:
+			if err := m.actions.CancelRun(ctx, repo, run.GetID()); err != nil {
+				return actionFailedMsg{action: fmt.Sprintf("cancel #%d", run.GetRunNumber()), err: err}
+			}
+			return actionDoneMsg{notice: fmt.Sprintf("Cancelling #%d", run.GetRunNumber())}
+		case value == "failed":
+			if err := m.actions.RerunFailedJobs(ctx, repo, run.GetID()); err != nil {
+				return actionFailedMsg{action: fmt.Sprintf("re-run #%d", run.GetRunNumber()), err: err}
+			}
+			return actionDoneMsg{notice: fmt.Sprintf("Re-running the failed jobs of #%d", run.GetRunNumber())}
+		default:
+			if err := m.actions.RerunRun(ctx, repo, run.GetID()); err != nil {
+				return actionFailedMsg{action: fmt.Sprintf("re-run #%d", run.GetRunNumber()), err: err}
+			}
+			return actionDoneMsg{notice: fmt.Sprintf("Re-running #%d", run.GetRunNumber())}
+		}
+	}))
+}
+
+func (m *ActionsPageModel) showPrompt(question string, options []components.PromptOption) tea.Cmd {
+	return tea.Sequence(
+		m.componentGroup.Update(m.promptComponent, components.PromptResetMsg{
+			Question: question,
+			Options:  options,
+		}),
+		m.componentGroup.FocusOn(m.promptComponent),
+	)
+}
@@ -0,0 +1,228 @@
+package actionspage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/ui/components"
+)
+
+// A line of the jobs list: a job, or one of its steps.
+type jobRow struct {
+	job *github.WorkflowJob
+	// nil for the job itself.
+	step *github.TaskStep
+}
+
+// openRun shows the jobs of run.
+func (m *ActionsPageModel) openRun(run *github.WorkflowRun) tea.Cmd {
+	m.run = run
+	m.jobs = nil
+	m.view = jobsView
+	return tea.Sequence(
+		m.componentGroup.Update(m.jobsListComponent, components.ListSetItemsMsg[jobRow]{}),
+		m.componentGroup.Update(m.jobsListComponent, components.ListResetViewportMsg{}),
+		m.componentGroup.FocusOn(m.jobsListComponent),
+		m.fetchJobs(run.GetID()),
+	)
+}
+
+func (m *ActionsPageModel) handleJobsReady(msg jobsReadyMsg) tea.Cmd {
+	m.jobs = msg.jobs
+	rows := make([]jobRow, 0)
+	running := false
+	for _, job := range msg.jobs {
+		rows = append(rows, jobRow{job: job})
+		for _, step := range job.Steps {
+			rows = append(rows, jobRow{job: job, step: step})
+		}
+		running = running || job.GetStatus() != "completed"
+
+		if m.logJob != nil && m.logJob.GetID() == job.GetID() {
+			m.logJob = job
+			for _, step := range job.Steps {
+				if m.logStep != nil && m.logStep.GetNumber() == step.GetNumber() {
+					m.logStep = step
+				}
+			}
+		}
+	}
+
+	cmds := []tea.Cmd{
+		m.componentGroup.Update(m.jobsListComponent, components.ListSetItemsMsg[jobRow]{Items: rows}),
+	}
+	if running && m.view != runsView {
+		cmds = append(cmds, m.schedulePoll())
+	}
+	if m.view == logView && m.logJob.GetStatus() == "completed" && !m.logLoaded && !m.logLoading && m.logError == "" {
+		cmds = append(cmds, m.fetchLog(m.logJob.GetID()))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *ActionsPageModel) handleJobsKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		row, ok := m.componentGroup.
+			GetComponent(m.jobsListComponent).(components.ListModel[jobRow]).
+			GetSelectedItem()
+		if !ok {
+			return nil
+		}
+		return m.openLog(row.job, row.step)
+	case "esc":
+		m.view = runsView
+		m.run = nil
+		return tea.Batch(
+			m.componentGroup.FocusOn(m.runsListComponent),
+			m.fetchRuns(),
+		)
+	case "R":
+		return m.startRerun(m.run)
+	case "x":
+		return m.startCancel(m.run)
+	default:
+		return m.componentGroup.UpdateFocused(msg)
+	}
+}
+
+// openLog shows the log of job, only expanding step if there is one. The
+// log is loaded once the job finished.
+func (m *ActionsPageModel) openLog(job *github.WorkflowJob, step *github.TaskStep) tea.Cmd {
+	m.logJob = job
+	m.logStep = step
+	m.logLoaded = false
+	m.logLoading = false
+	m.logError = ""
+	m.view = logView
+
+	cmds := []tea.Cmd{m.componentGroup.FocusOn(m.logViewerComponent)}
+	if job.GetStatus() == "completed" {
+		cmds = append(cmds, m.fetchLog(job.GetID()))
+	}
+	return tea.Sequence(cmds...)
+}
+
+func (m *ActionsPageModel) handleLogKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.view = jobsView
+		m.logJob = nil
+		m.logStep = nil
+		return m.componentGroup.FocusOn(m.jobsListComponent)
+	case "r":
+		if m.logError == "" {
+			return nil
+		}
+		return m.fetchLog(m.logJob.GetID())
+	default:
+		return m.componentGroup.UpdateFocused(msg)
+	}
+}
+
+func (m ActionsPageModel) logTitle() string {
+	title := fmt.Sprintf("%s #%d · %s", m.run.GetName(), m.run.GetRunNumber(), m.logJob.GetName())
+	if m.logStep != nil {
+		title += fmt.Sprintf(" · %d. %s", m.logStep.GetNumber(), m.logStep.GetName())
+	}
+	return title
+}
+
+// runHeader describes the run whose jobs are shown.
+func (m ActionsPageModel) runHeader() string {
+	status, conclusion := m.run.GetStatus(), m.run.GetConclusion()
+	if len(m.jobs) > 0 {
+		// Jobs are checked again while the run goes on, the run is not.
+		status = "completed"
+		for _, job := range m.jobs {
+			if job.GetStatus() != "completed" {
+				status = "in_progress"
+			}
+		}
+		if status == "completed" && m.run.GetStatus() != "completed" {
+			conclusion = ""
+		}
+	}
+	return fmt.Sprintf(
+		"%s %s #%d %s",
+		statusIcon(status, conclusion),
+		m.run.GetName(),
+		m.run.GetRunNumber(),
+		m.run.GetDisplayTitle(),
+	) + mutedStyle.Render(fmt.Sprintf(
+		" · %s · %s · enter log · R re-run · x cancel · esc back",
+		m.run.GetHeadBranch(),
+		describeStatus(status, conclusion),
+	))
+}
+
+// renderJobRow renders a job, or one of its steps indented under it.
+func renderJobRow(row jobRow, width int) string {
+	if row.step == nil {
+		job := row.job
+		line := fmt.Sprintf("%s %s", statusIcon(job.GetStatus(), job.GetConclusion()), job.GetName())
+		if job.GetStatus() == "completed" {
+			line += mutedStyle.Render(" · " + formatDuration(job.GetCompletedAt().Sub(job.GetStartedAt().Time)))
+		}
+		return titleStyle.Render(line)
+	}
+
+	step := row.step
+	line := fmt.Sprintf("    %s %d. %s", statusIcon(step.GetStatus(), step.GetConclusion()), step.GetNumber(), step.GetName())
+	if step.GetStatus() == "completed" && step.StartedAt != nil {
+		line += mutedStyle.Render(" · " + formatDuration(step.GetCompletedAt().Sub(step.GetStartedAt().Time)))
+	}
+	return line
+}
+
+// splitJobLog splits the log of a job into a section per step, by when each
+// line was written. Only step, or the failed steps for the whole job, are
+// expanded.
+func splitJobLog(log string, job *github.WorkflowJob, step *github.TaskStep) []components.LogSection {
+	if len(job.Steps) == 0 {
+		return []components.LogSection{{Title: job.GetName(), Log: log}}
+	}
+
+	sections := make([]components.LogSection, len(job.Steps))
+	lines := make([][]string, len(job.Steps))
+	for i, jobStep := range job.Steps {
+		title := fmt.Sprintf("%d. %s", jobStep.GetNumber(), jobStep.GetName())
+		if jobStep.GetStatus() == "completed" && jobStep.GetConclusion() != "success" {
+			title += " · " + describeStatus(jobStep.GetStatus(), jobStep.GetConclusion())
+		}
+		collapsed := jobStep.GetConclusion() != "failure"
+		if step != nil {
+			collapsed = jobStep.GetNumber() != step.GetNumber()
+		}
+		sections[i] = components.LogSection{Title: title, Collapsed: collapsed}
+	}
+
+	current := 0
+	for _, line := range strings.Split(log, "\n") {
+		stamp, _, _ := strings.Cut(strings.TrimPrefix(line, "\ufeff"), " ")
+		if written, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+			// Steps report their times to the second.
+			written = written.Truncate(time.Second)
+			for i := current + 1; i < len(job.Steps); i++ {
+				started := job.Steps[i].StartedAt
+				if started == nil {
+					// Skipped steps have no log.
+					continue
+				}
+				if started.After(written) {
+					break
+				}
+				current = i
+			}
+		}
+		lines[current] = append(lines[current], line)
+	}
+	for i := range sections {
+		sections[i].Log = strings.Join(lines[i], "\n")
+	}
+	return sections
+}
@@ -0,0 +1,253 @@
+package actionspage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+// Statuses and conclusions GitHub filters runs by.
+var runStatuses = []string{
+	"queued", "in_progress", "completed", "waiting", "requested", "pending", "action_required",
+	"success", "failure", "cancelled", "skipped", "neutral", "stale", "timed_out",
+}
+
+// runFilters narrow down the runs listed.
+type runFilters struct {
+	// Workflow whose runs are listed, 0 for all of them, and its name.
+	WorkflowID int64
+	Workflow   string
+	Branch     string
+	Event      string
+	Status     string
+}
+
+func (m *ActionsPageModel) handleRunsKey(msg tea.KeyMsg) tea.Cmd {
+	run, selected := m.getSelectedRun()
+	switch msg.String() {
+	case "enter":
+		if !selected {
+			return nil
+		}
+		return m.openRun(run)
+	case "w":
+		m.view = workflowsView
+		choices := append([]*github.Workflow{nil}, m.workflows...)
+		return tea.Sequence(
+			m.componentGroup.Update(m.workflowsListComponent, components.ListSetItemsMsg[*github.Workflow]{
+				Items: choices,
+			}),
+			m.componentGroup.FocusOn(m.workflowsListComponent),
+		)
+	case "f":
+		return tea.Sequence(
+			m.componentGroup.Update(m.filterFormComponent, components.FormResetMsg{
+				Title: "Filter workflow runs",
+				Fields: []components.FormField{
+					{Label: "Branch", Value: m.filters.Branch},
+					{Label: "Event", Value: m.filters.Event, Hint: "e.g. push, pull_request, schedule, workflow_dispatch"},
+					{Label: "Status", Value: m.filters.Status, Hint: "One of " + strings.Join(runStatuses, ", ")},
+				},
+			}),
+			m.componentGroup.FocusOn(m.filterFormComponent),
+		)
+	case "F":
+		m.filters = runFilters{}
+		return m.refetchFromStart()
+	case "]":
+		if m.page*runsPerPage >= m.total {
+			return nil
+		}
+		m.page++
+		return tea.Batch(
+			m.componentGroup.Update(m.runsListComponent, components.ListResetViewportMsg{}),
+			m.fetchRuns(),
+		)
+	case "[":
+		if m.page == 1 {
+			return nil
+		}
+		m.page--
+		return tea.Batch(
+			m.componentGroup.Update(m.runsListComponent, components.ListResetViewportMsg{}),
+			m.fetchRuns(),
+		)
+	case "R":
+		if !selected {
+			return nil
+		}
+		return m.startRerun(run)
+	case "x":
+		if !selected {
+			return nil
+		}
+		return m.startCancel(run)
+	case "D":
+		return m.startDispatch()
+	default:
+		return m.componentGroup.UpdateFocused(msg)
+	}
+}
+
+func (m *ActionsPageModel) handleWorkflowsKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		workflow, ok := m.componentGroup.
+			GetComponent(m.workflowsListComponent).(components.ListModel[*github.Workflow]).
+			GetSelectedItem()
+		if !ok {
+			return nil
+		}
+		m.filters.WorkflowID = workflow.GetID()
+		m.filters.Workflow = workflow.GetName()
+		m.view = runsView
+		return tea.Batch(
+			m.componentGroup.FocusOn(m.runsListComponent),
+			m.refetchFromStart(),
+		)
+	case "esc":
+		m.view = runsView
+		return m.componentGroup.FocusOn(m.runsListComponent)
+	default:
+		return m.componentGroup.UpdateFocused(msg)
+	}
+}
+
+// handleFilterForm lists the runs matching the filters submitted.
+func (m *ActionsPageModel) handleFilterForm(values []string) tea.Cmd {
+	status := strings.ToLower(values[2])
+	if status != "" && !slices.Contains(runStatuses, status) {
+		m.setNotice(fmt.Sprintf("Unknown status %q", values[2]), true)
+		return nil
+	}
+	m.filters.Branch = values[0]
+	m.filters.Event = values[1]
+	m.filters.Status = status
+	return tea.Batch(
+		m.componentGroup.FocusOn(m.runsListComponent),
+		m.refetchFromStart(),
+	)
+}
+
+// refetchFromStart lists the first page after the filters changed.
+func (m *ActionsPageModel) refetchFromStart() tea.Cmd {
+	m.page = 1
+	return tea.Batch(
+		m.componentGroup.Update(m.runsListComponent, components.ListResetViewportMsg{}),
+		m.fetchRuns(),
+	)
+}
+
+func (m ActionsPageModel) getSelectedRun() (*github.WorkflowRun, bool) {
+	return m.componentGroup.
+		GetComponent(m.runsListComponent).(components.ListModel[*github.WorkflowRun]).
+		GetSelectedItem()
+}
+
+// chips renders the active filters and the page shown on a single line.
+func (m ActionsPageModel) chips() string {
+	chips := make([]string, 0)
+	if m.filters.Workflow != "" {
+		chips = append(chips, chipStyle.Render(m.filters.Workflow))
+	}
+	if m.filters.Branch != "" {
+		chips = append(chips, chipStyle.Render("branch:"+m.filters.Branch))
+	}
+	if m.filters.Event != "" {
+		chips = append(chips, chipStyle.Render("event:"+m.filters.Event))
+	}
+	if m.filters.Status != "" {
+		chips = append(chips, chipStyle.Render("status:"+m.filters.Status))
+	}
+	pages := max(1, (m.total+runsPerPage-1)/runsPerPage)
+	chips = append(chips, mutedStyle.Render(fmt.Sprintf(
+		"page %d/%d · %d runs · w workflow · f filter · R re-run · x cancel · D dispatch · [ ] pages",
+		m.page,
+		pages,
+		m.total,
+	)))
+	return lipgloss.NewStyle().
+		MaxWidth(m.width).
+		MaxHeight(1).
+		Render(lipgloss.JoinHorizontal(lipgloss.Top, chips...))
+}
+
+// renderRun renders a run as a line of the list: its state, number, title,
+// workflow, branch, event, actor and when it ran.
+func renderRun(run *github.WorkflowRun, width int) string {
+	parts := []string{
+		fmt.Sprintf("%s #%d %s", statusIcon(run.GetStatus(), run.GetConclusion()), run.GetRunNumber(), run.GetDisplayTitle()),
+		run.GetName(),
+		run.GetHeadBranch(),
+		run.GetEvent(),
+		"@" + run.GetActor().GetLogin(),
+	}
+	if run.GetStatus() == "completed" {
+		parts = append(parts, utils.Since(run.GetUpdatedAt().Time)+" · "+formatDuration(run.GetUpdatedAt().Sub(run.GetRunStartedAt().Time)))
+	} else {
+		parts = append(parts, describeStatus(run.GetStatus(), ""))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// renderWorkflow renders a workflow to pick, nil for all of them.
+func renderWorkflow(workflow *github.Workflow, width int) string {
+	if workflow == nil {
+		return "All workflows"
+	}
+	line := workflow.GetName() + mutedStyle.Render(" · "+workflow.GetPath())
+	if workflow.GetState() != "active" {
+		line += mutedStyle.Render(" · " + strings.ReplaceAll(workflow.GetState(), "_", " "))
+	}
+	return line
+}
+
+// statusIcon returns an icon for the status and conclusion of a run, job or step.
+func statusIcon(status string, conclusion string) string {
+	if status != "completed" {
+		if status == "in_progress" {
+			return pendingStyle.Render("●")
+		}
+		return pendingStyle.Render("○")
+	}
+	switch conclusion {
+	case "success":
+		return successStyle.Render("✓")
+	case "failure", "timed_out", "startup_failure", "action_required":
+		return failureStyle.Render("✗")
+	case "cancelled":
+		return mutedStyle.Render("⊘")
+	default:
+		return mutedStyle.Render("–")
+	}
+}
+
+// describeStatus returns the conclusion of what completed, its status otherwise.
+func describeStatus(status string, conclusion string) string {
+	if status == "completed" && conclusion != "" {
+		return strings.ReplaceAll(conclusion, "_", " ")
+	}
+	return strings.ReplaceAll(status, "_", " ")
+}
+
+func formatDuration(duration time.Duration) string {
+	if duration < 0 {
+		return ""
+	}
+	duration = duration.Round(time.Second)
+	if duration < time.Minute {
+		return fmt.Sprintf("%ds", int(duration.Seconds()))
+	}
+	if duration < time.Hour {
+		return fmt.Sprintf("%dm %ds", int(duration.Minutes()), int(duration.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh %dm", int(duration.Hours()), int(duration.Minutes())%60)
+}
@@ -0,0 +1,289 @@
+package issuespage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+const detailTimeFormat = "Jan 2, 2006 15:04"
+
+// issueDetail is an issue together with the part of its timeline loaded so far.
+type issueDetail struct {
+	repo   gh.Repo
+	issue  *github.Issue
+	events []*github.Timeline
+	// Next timeline page to load, 0 once everything is loaded.
+	nextPage int
+	// Whether the issue and the first page of its timeline were loaded, rather
+	// than only what the list or the page opening it knew.
+	loaded bool
+	// Whether a request for the detail is in flight, and the number of the
+	// latest, whose response is the only one shown.
+	loading bool
+	request int
+	// Why the latest request failed, shown in the detail pane until retried.
+	err error
+}
+
+type issueDetailReadyMsg struct {
+	repo     gh.Repo
+	number   int
+	request  int
+	issue    *github.Issue
+	events   []*github.Timeline
+	nextPage int
+	// Whether events continue the timeline already shown rather than replace it.
+	appended bool
+}
+
+type issueDetailFailedMsg struct {
+	repo    gh.Repo
+	number  int
+	request int
+	err     error
+}
+
+// fetchIssueDetail loads the issue's metadata and the first page of its timeline.
+func (m *IssuesPageModel) fetchIssueDetail(repo gh.Repo, number int) tea.Cmd {
+	request := m.startDetailRequest()
+	return utils.PageCmd(m.id, func() tea.Msg {
+		ctx := context.Background()
+		issue, err := m.issues.GetIssue(ctx, repo, number)
+		if err != nil {
+			return issueDetailFailedMsg{repo: repo, number: number, request: request, err: err}
+		}
+		timeline, err := m.issues.ListTimeline(ctx, repo, number, 1)
+		if err != nil {
+			return issueDetailFailedMsg{repo: repo, number: number, request: request, err: err}
+		}
+
+		return issueDetailReadyMsg{
+			repo:     repo,
+			number:   number,
+			request:  request,
+			issue:    issue,
+			events:   timeline.Events,
+			nextPage: timeline.NextPage,
+		}
+	})
+}
+
+// fetchMoreTimeline loads the next page of the timeline of the issue shown,
+// unless the detail is still loading.
+func (m *IssuesPageModel) fetchMoreTimeline() tea.Cmd {
+	detail := m.detail
+	if detail == nil || detail.loading || detail.nextPage == 0 {
+		return nil
+	}
+	request := m.startDetailRequest()
+	repo, number, issue, page := detail.repo, detail.issue.GetNumber(), detail.issue, detail.nextPage
+	return utils.PageCmd(m.id, func() tea.Msg {
+		timeline, err := m.issues.ListTimeline(context.Background(), repo, number, page)
+		if err != nil {
+			return issueDetailFailedMsg{repo: repo, number: number, request: request, err: err}
+		}
+
+		return issueDetailReadyMsg{
+			repo:     repo,
+			number:   number,
+			request:  request,
+			issue:    issue,
+			events:   timeline.Events,
+			nextPage: timeline.NextPage,
+			appended: true,
+		}
+	})
+}
+
+// retryDetail requests again what failed to load of the issue shown.
+func (m *IssuesPageModel) retryDetail() tea.Cmd {
+	if m.detail.loaded {
+		return m.fetchMoreTimeline()
+	}
+	return m.fetchIssueDetail(m.detail.repo, m.detail.issue.GetNumber())
+}
+
+// startDetailRequest marks the detail shown as loading, returning the number of
+// the request.
+func (m *IssuesPageModel) startDetailRequest() int {
+	if m.detail == nil {
+		return 0
+	}
+	m.detail.loading = true
+	m.detail.request++
+	return m.detail.request
+}
+
+// isDetailResponse reports whether a response for the detail of number in repo
+// answers the latest request for the detail shown.
+func (m IssuesPageModel) isDetailResponse(repo gh.Repo, number int, request int) bool {
+	return m.detail != nil &&
+		m.detail.repo == repo &&
+		m.detail.issue.GetNumber() == number &&
+		m.detail.request == request
+}
+
+// renderIssueDetail renders the issue's metadata, body and timeline as markdown.
+func renderIssueDetail(detail issueDetail) string {
+	issue := detail.issue
+	doc := strings.Builder{}
+
+	fmt.Fprintf(&doc, "# %s #%d\n\n", issue.GetTitle(), issue.GetNumber())
+	if !detail.loaded && detail.err != nil {
+		fmt.Fprintf(&doc, "**Failed to load the issue:** %s\n\n*Press r to retry.*\n", detail.err)
+		return doc.String()
+	}
+
+	state := "Open"
+	if issue.GetState() == "closed" {
+		state = "Closed"
+	}
+	if reason := issue.GetStateReason(); issue.GetState() == "closed" && reason != "" {
+		state += " as " + strings.ReplaceAll(reason, "_", " ")
+	}
+	if issue.GetLocked() {
+		state += " · locked"
+	}
+	fmt.Fprintf(
+		&doc,
+		"**%s** · opened by @%s on %s · %d comments\n\n",
+		state,
+		issue.GetUser().GetLogin(),
+		issue.GetCreatedAt().Format(detailTimeFormat),
+		issue.GetComments(),
+	)
+
+	if len(issue.Labels) > 0 {
+		labels := make([]string, len(issue.Labels))
+		for i, label := range issue.Labels {
+			labels[i] = "`" + label.GetName() + "`"
+		}
+		fmt.Fprintf(&doc, "**Labels:** %s\n\n", strings.Join(labels, " "))
+	}
+	if len(issue.Assignees) > 0 {
+		assignees := make([]string, len(issue.Assignees))
+		for i, assignee := range issue.Assignees {
+			assignees[i] = "@" + assignee.GetLogin()
+		}
+		fmt.Fprintf(&doc, "**Assignees:** %s\n\n", strings.Join(assignees, ", "))
+	}
+	if issue.Milestone != nil {
+		fmt.Fprintf(&doc, "**Milestone:** %s\n\n", issue.Milestone.GetTitle())
+	}
+
+	doc.WriteString("---\n\n")
+	if body := issue.GetBody(); body != "" {
+		doc.WriteString(body + "\n\n")
+	} else {
+		doc.WriteString("*No description provided.*\n\n")
+	}
+
+	inList := false
+	for _, event := range detail.events {
+		if event.GetEvent() == "commented" {
+			inList = false
+			fmt.Fprintf(
+				&doc,
+				"---\n\n### @%s commented · %s\n\n%s\n\n",
+				eventAuthor(event),
+				event.GetCreatedAt().Format(detailTimeFormat),
+				event.GetBody(),
+			)
+			continue
+		}
+
+		description := describeEvent(event)
+		if description == "" {
+			continue
+		}
+		if !inList {
+			doc.WriteString("---\n\n")
+			inList = true
+		}
+		fmt.Fprintf(
+			&doc,
+			"- @%s %s · %s\n",
+			eventAuthor(event),
+			description,
+			event.GetCreatedAt().Format(detailTimeFormat),
+		)
+	}
+	if inList {
+		doc.WriteString("\n")
+	}
+
+	if detail.err != nil {
+		fmt.Fprintf(&doc, "---\n\n**Failed to load more of the timeline:** %s\n\n*Press r to retry.*\n", detail.err)
+	} else if detail.nextPage != 0 {
+		doc.WriteString("---\n\n*Press m to load more of the timeline.*\n")
+	}
+	return doc.String()
+}
+
+func eventAuthor(event *github.Timeline) string {
+	if event.Actor != nil {
+		return event.Actor.GetLogin()
+	}
+	return event.GetUser().GetLogin()
+}
+
+// describeEvent returns a short description of a timeline event, or an empty
+// string for events that are not shown.
+func describeEvent(event *github.Timeline) string {
+	switch event.GetEvent() {
+	case "labeled":
+		return fmt.Sprintf("added label `%s`", event.GetLabel().GetName())
+	case "unlabeled":
+		return fmt.Sprintf("removed label `%s`", event.GetLabel().GetName())
+	case "assigned":
+		return "assigned @" + event.GetAssignee().GetLogin()
+	case "unassigned":
+		return "unassigned @" + event.GetAssignee().GetLogin()
+	case "milestoned":
+		return fmt.Sprintf("added this to the %s milestone", event.GetMilestone().GetTitle())
+	case "demilestoned":
+		return fmt.Sprintf("removed this from the %s milestone", event.GetMilestone().GetTitle())
+	case "renamed":
+		return fmt.Sprintf("changed the title from *%s* to *%s*", event.GetRename().GetFrom(), event.GetRename().GetTo())
+	case "referenced":
+		return fmt.Sprintf("referenced this in commit `%.7s`", event.GetCommitID())
+	case "cross-referenced":
+		source := event.GetSource().GetIssue()
+		return fmt.Sprintf(
+			"mentioned this in %s#%d *%s*",
+			source.GetRepository().GetFullName(),
+			source.GetNumber(),
+			source.GetTitle(),
+		)
+	case "closed":
+		if commit := event.GetCommitID(); commit != "" {
+			return fmt.Sprintf("closed this in commit `%.7s`", commit)
+		}
+		return "closed this"
+	case "reopened":
+		return "reopened this"
+	case "locked":
+		return "locked this conversation"
+	case "unlocked":
+		return "unlocked this conversation"
+	case "pinned":
+		return "pinned this issue"
+	case "unpinned":
+		return "unpinned this issue"
+	case "transferred":
+		return "transferred this issue"
+	case "marked_as_duplicate":
+		return "marked this as a duplicate"
+	case "connected":
+		return "linked a pull request"
+	default:
+		return ""
+	}
+}
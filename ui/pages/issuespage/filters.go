@@ -0,0 +1,239 @@
+package issuespage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alex-laycalvert/ghtui/config"
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+// Name of the state file holding the filters of every repository.
+const filtersStateName = "issue-filters"
+
+const filterDateFormat = "2006-01-02"
+
+var (
+	filterStates = []string{"open", "closed", "all"}
+	filterSorts  = []string{"created", "updated", "comments", "reactions"}
+)
+
+var filterChipStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("230")).
+	Background(lipgloss.Color("62")).
+	Padding(0, 1).
+	MarginRight(1)
+
+// issueFilters narrow down and order the issues listed, turned into search
+// qualifiers by `query`.
+type issueFilters struct {
+	// One of `filterStates`.
+	State     string   `json:"state"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+	Author    string   `json:"author,omitempty"`
+	Milestone string   `json:"milestone,omitempty"`
+	Mentions  string   `json:"mentions,omitempty"`
+	// Bounds of the last update, as `filterDateFormat` dates, either may be empty.
+	UpdatedSince string `json:"updated_since,omitempty"`
+	UpdatedUntil string `json:"updated_until,omitempty"`
+	// One of `filterSorts`.
+	Sort  string `json:"sort"`
+	Order string `json:"order"`
+}
+
+type filtersSavedMsg struct {
+	err error
+}
+
+func defaultFilters() issueFilters {
+	return issueFilters{State: "open", Sort: "created", Order: "desc"}
+}
+
+// loadFilters returns the filters last used on repo, or the defaults.
+func loadFilters(repo gh.Repo) issueFilters {
+	saved := map[string]issueFilters{}
+	if err := config.LoadState(filtersStateName, &saved); err != nil {
+		return defaultFilters()
+	}
+	filters, ok := saved[repo.String()]
+	if !ok {
+		return defaultFilters()
+	}
+	return filters
+}
+
+// saveFilters remembers filters as the ones used on repo, for the page with id.
+func saveFilters(id string, repo gh.Repo, filters issueFilters) tea.Cmd {
+	return utils.PageCmd(id, func() tea.Msg {
+		saved := map[string]issueFilters{}
+		// A corrupt state file is replaced rather than blocking the save.
+		_ = config.LoadState(filtersStateName, &saved)
+		saved[repo.String()] = filters
+		return filtersSavedMsg{err: config.SaveState(filtersStateName, saved)}
+	})
+}
+
+// query returns the search query listing the issues of repo matching the
+// filters and searchTerm.
+func (f issueFilters) query(repo gh.Repo, searchTerm string) string {
+	qualifiers := []string{"repo:" + repo.String(), "is:issue"}
+	if f.State != "all" {
+		qualifiers = append(qualifiers, "is:"+f.State)
+	}
+	for _, label := range f.Labels {
+		qualifiers = append(qualifiers, "label:"+quoteQualifier(label))
+	}
+	for _, assignee := range f.Assignees {
+		qualifiers = append(qualifiers, "assignee:"+assignee)
+	}
+	if f.Author != "" {
+		qualifiers = append(qualifiers, "author:"+f.Author)
+	}
+	if f.Milestone != "" {
+		qualifiers = append(qualifiers, "milestone:"+quoteQualifier(f.Milestone))
+	}
+	if f.Mentions != "" {
+		qualifiers = append(qualifiers, "mentions:"+f.Mentions)
+	}
+	if updated := f.updatedRange(); updated != "" {
+		qualifiers = append(qualifiers, "updated:"+updated)
+	}
+	if searchTerm != "" {
+		qualifiers = append(qualifiers, searchTerm)
+	}
+	return strings.Join(qualifiers, " ")
+}
+
+// updatedRange returns the updated range in the search syntax, empty without bounds.
+func (f issueFilters) updatedRange() string {
+	switch {
+	case f.UpdatedSince != "" && f.UpdatedUntil != "":
+		return f.UpdatedSince + ".." + f.UpdatedUntil
+	case f.UpdatedSince != "":
+		return ">=" + f.UpdatedSince
+	case f.UpdatedUntil != "":
+		return "<=" + f.UpdatedUntil
+	default:
+		return ""
+	}
+}
+
+// matchesState reports whether an issue in state belongs in a list filtered by f.
+func (f issueFilters) matchesState(state string) bool {
+	return f.State == "all" || f.State == state
+}
+
+// chips renders the active filters as a single line.
+func (f issueFilters) chips(width int) string {
+	chips := []string{"is:" + f.State}
+	for _, label := range f.Labels {
+		chips = append(chips, "label:"+label)
+	}
+	for _, assignee := range f.Assignees {
+		chips = append(chips, "assignee:"+assignee)
+	}
+	if f.Author != "" {
+		chips = append(chips, "author:"+f.Author)
+	}
+	if f.Milestone != "" {
+		chips = append(chips, "milestone:"+f.Milestone)
+	}
+	if f.Mentions != "" {
+		chips = append(chips, "mentions:"+f.Mentions)
+	}
+	if updated := f.updatedRange(); updated != "" {
+		chips = append(chips, "updated:"+updated)
+	}
+	chips = append(chips, fmt.Sprintf("sort:%s-%s", f.Sort, f.Order))
+
+	rendered := make([]string, len(chips))
+	for i, chip := range chips {
+		rendered[i] = filterChipStyle.Render(chip)
+	}
+	return lipgloss.NewStyle().
+		Width(width).
+		MaxWidth(width).
+		MaxHeight(1).
+		Render(lipgloss.JoinHorizontal(lipgloss.Top, rendered...))
+}
+
+// formFields returns the filter panel's fields, filled in with f.
+func (f issueFilters) formFields() []components.FormField {
+	updated := ""
+	if f.UpdatedSince != "" || f.UpdatedUntil != "" {
+		updated = f.UpdatedSince + ".." + f.UpdatedUntil
+	}
+	return []components.FormField{
+		{Label: "State", Value: f.State, Hint: "open, closed or all"},
+		{Label: "Labels", Value: strings.Join(f.Labels, ", "), Hint: "Comma separated label names, all must match"},
+		{Label: "Assignees", Value: strings.Join(f.Assignees, ", "), Hint: "Comma separated logins, @me for yourself"},
+		{Label: "Author", Value: f.Author, Hint: "Login, @me for yourself"},
+		{Label: "Milestone", Value: f.Milestone, Hint: "Milestone title"},
+		{Label: "Mentions", Value: f.Mentions, Hint: "Login, @me for yourself"},
+		{Label: "Updated", Value: updated, Hint: "YYYY-MM-DD..YYYY-MM-DD, either side may be left out"},
+		{Label: "Sort", Value: f.Sort + " " + f.Order, Hint: "created, updated, comments or reactions, then asc or desc"},
+	}
+}
+
+// parseFilterForm reads filters from the values of the filter panel's fields.
+func parseFilterForm(values []string) (issueFilters, error) {
+	f := issueFilters{
+		State:     strings.ToLower(values[0]),
+		Labels:    splitList(values[1]),
+		Assignees: splitList(values[2]),
+		Author:    values[3],
+		Milestone: values[4],
+		Mentions:  values[5],
+	}
+	if f.State == "" {
+		f.State = "open"
+	}
+	if !slices.Contains(filterStates, f.State) {
+		return f, fmt.Errorf("unknown state %q, expected one of %s", f.State, strings.Join(filterStates, ", "))
+	}
+
+	if updated := values[6]; updated != "" {
+		since, until, isRange := strings.Cut(updated, "..")
+		if !isRange {
+			until = ""
+		}
+		for _, date := range []string{since, until} {
+			if _, err := time.Parse(filterDateFormat, date); date != "" && err != nil {
+				return f, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+			}
+		}
+		f.UpdatedSince = since
+		f.UpdatedUntil = until
+	}
+
+	sort := strings.Fields(strings.ToLower(values[7]))
+	f.Sort, f.Order = "created", "desc"
+	if len(sort) > 0 {
+		f.Sort = sort[0]
+	}
+	if len(sort) > 1 {
+		f.Order = sort[1]
+	}
+	if !slices.Contains(filterSorts, f.Sort) {
+		return f, fmt.Errorf("unknown sort %q, expected one of %s", f.Sort, strings.Join(filterSorts, ", "))
+	}
+	if f.Order != "asc" && f.Order != "desc" {
+		return f, fmt.Errorf("unknown sort direction %q, expected asc or desc", f.Order)
+	}
+	return f, nil
+}
+
+func quoteQualifier(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + value + `"`
+	}
+	return value
+}
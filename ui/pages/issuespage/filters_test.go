@@ -0,0 +1,159 @@
+package issuespage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+)
+
+func TestIssueFiltersQuery(t *testing.T) {
+	repo := gh.Repo{Owner: "owner", Name: "repo"}
+	tests := []struct {
+		name       string
+		filters    issueFilters
+		searchTerm string
+		want       string
+	}{
+		{
+			name:    "defaults",
+			filters: defaultFilters(),
+			want:    "repo:owner/repo is:issue is:open",
+		},
+		{
+			name:    "any state",
+			filters: issueFilters{State: "all", Sort: "created", Order: "desc"},
+			want:    "repo:owner/repo is:issue",
+		},
+		{
+			name: "every qualifier",
+			filters: issueFilters{
+				State:     "closed",
+				Labels:    []string{"bug", "good first issue"},
+				Assignees: []string{"@me", "octocat"},
+				Author:    "hubot",
+				Milestone: "v1.0 launch",
+				Mentions:  "monalisa",
+			},
+			want: `repo:owner/repo is:issue is:closed label:bug label:"good first issue" assignee:@me assignee:octocat author:hubot milestone:"v1.0 launch" mentions:monalisa`,
+		},
+		{
+			name:    "updated range",
+			filters: issueFilters{State: "open", UpdatedSince: "2024-01-01", UpdatedUntil: "2024-02-01"},
+			want:    "repo:owner/repo is:issue is:open updated:2024-01-01..2024-02-01",
+		},
+		{
+			name:    "updated since",
+			filters: issueFilters{State: "open", UpdatedSince: "2024-01-01"},
+			want:    "repo:owner/repo is:issue is:open updated:>=2024-01-01",
+		},
+		{
+			name:    "updated until",
+			filters: issueFilters{State: "open", UpdatedUntil: "2024-02-01"},
+			want:    "repo:owner/repo is:issue is:open updated:<=2024-02-01",
+		},
+		{
+			name:       "search term last",
+			filters:    issueFilters{State: "open", Labels: []string{"bug"}},
+			searchTerm: "crash in:title",
+			want:       "repo:owner/repo is:issue is:open label:bug crash in:title",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.filters.query(repo, test.searchTerm); got != test.want {
+				t.Errorf("query\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterForm(t *testing.T) {
+	tests := []struct {
+		name string
+		// State, labels, assignees, author, milestone, mentions, updated and sort.
+		values  []string
+		want    issueFilters
+		wantErr string
+	}{
+		{
+			name:   "empty form",
+			values: []string{"", "", "", "", "", "", "", ""},
+			want:   issueFilters{State: "open", Labels: []string{}, Assignees: []string{}, Sort: "created", Order: "desc"},
+		},
+		{
+			name:   "every field",
+			values: []string{"Closed", "bug, good first issue,", " @me ,octocat", "hubot", "v1.0", "monalisa", "2024-01-01..2024-02-01", "Updated ASC"},
+			want: issueFilters{
+				State:        "closed",
+				Labels:       []string{"bug", "good first issue"},
+				Assignees:    []string{"@me", "octocat"},
+				Author:       "hubot",
+				Milestone:    "v1.0",
+				Mentions:     "monalisa",
+				UpdatedSince: "2024-01-01",
+				UpdatedUntil: "2024-02-01",
+				Sort:         "updated",
+				Order:        "asc",
+			},
+		},
+		{
+			name:   "single date is the lower bound",
+			values: []string{"all", "", "", "", "", "", "2024-01-01", "comments"},
+			want:   issueFilters{State: "all", Labels: []string{}, Assignees: []string{}, UpdatedSince: "2024-01-01", Sort: "comments", Order: "desc"},
+		},
+		{
+			name:   "open ended range",
+			values: []string{"open", "", "", "", "", "", "..2024-02-01", ""},
+			want:   issueFilters{State: "open", Labels: []string{}, Assignees: []string{}, UpdatedUntil: "2024-02-01", Sort: "created", Order: "desc"},
+		},
+		{
+			name:    "unknown state",
+			values:  []string{"merged", "", "", "", "", "", "", ""},
+			wantErr: `unknown state "merged"`,
+		},
+		{
+			name:    "invalid date",
+			values:  []string{"open", "", "", "", "", "", "2024-13-01..", ""},
+			wantErr: `invalid date "2024-13-01"`,
+		},
+		{
+			name:    "unknown sort",
+			values:  []string{"open", "", "", "", "", "", "", "stars"},
+			wantErr: `unknown sort "stars"`,
+		},
+		{
+			name:    "unknown direction",
+			values:  []string{"open", "", "", "", "", "", "", "created up"},
+			wantErr: `unknown sort direction "up"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseFilterForm(test.values)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("error %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("filters\n%+v\nwant\n%+v", got, test.want)
+			}
+
+			// The form shows the filters parsed as they were written.
+			fields := got.formFields()
+			values := make([]string, len(fields))
+			for i, field := range fields {
+				values[i] = field.Value
+			}
+			if again, err := parseFilterForm(values); err != nil || !reflect.DeepEqual(again, got) {
+				t.Errorf("filters read back from their form\n%+v, %v\nwant\n%+v", again, err, got)
+			}
+		})
+	}
+}
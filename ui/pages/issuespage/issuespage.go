@@ -3,34 +3,58 @@ package issuespage
 import (
 	"context"
 	"fmt"
-	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/google/go-github/v69/github"
 
+	"github.com/alex-laycalvert/ghtui/gh"
 	"github.com/alex-laycalvert/ghtui/ui/components"
 	"github.com/alex-laycalvert/ghtui/utils"
 )
 
+var (
+	noticeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("42"))
+	noticeErrorStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("196"))
+)
+
 type IssuesPageModel struct {
 	id     string
 	width  int
 	height int
 
-	repo              string
-	client            *github.Client
+	repo              gh.Repo
+	issues            gh.IssueService
 	state             utils.ComponentState
 	currentIssuesPage int
 	lastIssuesPage    int
 	selectedIssue     *github.Issue
-	search            string
+	detail            *issueDetail
+	// Placeholders of the issues being created, which cannot be opened or acted
+	// on until GitHub's versions replace them.
+	creating map[*github.Issue]bool
+	search   string
+	filters  issueFilters
+	// The mutation being prepared through the form and prompt.
+	action     *issueAction
+	promptStep promptStep
+	// Component to focus again once the form or prompt is done.
+	returnFocus   string
+	notice        string
+	noticeIsError bool
 
 	componentGroup          utils.ComponentGroup
 	spinnerComponent        string
 	issuesListComponent     string
 	markdownViewerComponent string
 	textInputComponent      string
+	errorPanelComponent     string
+	formComponent           string
+	promptComponent         string
+	previewComponent        string
+	filterFormComponent     string
 }
 
 type issuesLoadingMsg struct{}
@@ -38,9 +62,11 @@ type issuesLoadingMsg struct{}
 type issuesReadyMsg struct {
 	issues         []*github.Issue
 	lastIssuesPage int
+	// Whether the issues come from the disk cache and are still being revalidated.
+	cached bool
 }
 
-func NewIssuesPage(id string, client *github.Client, repo string, width int, height int) IssuesPageModel {
+func NewIssuesPage(id string, issues gh.IssueService, repo gh.Repo, width int, height int) IssuesPageModel {
 	spinner := components.NewSpinnerComponent()
 	issuesList := components.NewIssuesListComponent(width, height)
 	markdownViewer := components.NewMarkdownViewerComponent(
@@ -55,25 +81,42 @@ func NewIssuesPage(id string, client *github.Client, repo string, width int, hei
 			PaddingRight(2),
 	)
 	textInput := components.NewTextInputComponent("Search", width)
+	errorPanel := components.NewErrorPanelComponent(width)
+	form := components.NewFormComponent(width)
+	prompt := components.NewPromptComponent(width)
+	preview := components.NewMarkdownViewerComponent(width, height-1, lipgloss.NewStyle())
+	filterForm := components.NewFormComponent(width)
 
 	m := IssuesPageModel{
 		id:                id,
 		state:             utils.LoadingState,
-		client:            client,
+		issues:            issues,
 		repo:              repo,
 		width:             width,
 		height:            height,
 		currentIssuesPage: 1,
+		creating:          map[*github.Issue]bool{},
+		filters:           loadFilters(repo),
 		componentGroup: utils.NewComponentGroup(
 			spinner,
 			issuesList,
 			markdownViewer,
 			textInput,
+			errorPanel,
+			form,
+			prompt,
+			preview,
+			filterForm,
 		),
 		spinnerComponent:        spinner.ID(),
 		issuesListComponent:     issuesList.ID(),
 		markdownViewerComponent: markdownViewer.ID(),
 		textInputComponent:      textInput.ID(),
+		errorPanelComponent:     errorPanel.ID(),
+		formComponent:           form.ID(),
+		promptComponent:         prompt.ID(),
+		previewComponent:        preview.ID(),
+		filterFormComponent:     filterForm.ID(),
 	}
 
 	return m
@@ -83,6 +126,11 @@ func (m IssuesPageModel) ID() string {
 	return m.id
 }
 
+// CapturesInput reports whether the search, form or prompt is taking input.
+func (m IssuesPageModel) CapturesInput() bool {
+	return m.componentGroup.CapturesInput()
+}
+
 func (m IssuesPageModel) Init() tea.Cmd {
 	return tea.Sequence(
 		m.fetchIssues("", 0),
@@ -92,6 +140,11 @@ func (m IssuesPageModel) Init() tea.Cmd {
 
 func (m IssuesPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case utils.PageMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+		return m.Update(msg.Msg)
 	case utils.FocusMsg:
 		if m.id != msg.ID {
 			return m, m.componentGroup.UpdateFocused(msg)
@@ -103,6 +156,7 @@ func (m IssuesPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		m.selectedIssue = nil
+		m.detail = nil
 		return m, tea.Batch(
 			m.componentGroup.FocusOn(m.issuesListComponent),
 			m.componentGroup.Update(m.issuesListComponent, utils.UpdateSizeMsg{
@@ -143,12 +197,28 @@ func (m IssuesPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.componentGroup.Update(m.issuesListComponent, utils.UpdateSizeMsg{
 				ID:     m.issuesListComponent,
 				Width:  width,
-				Height: m.height,
+				Height: m.height - 1,
 			}),
 			m.componentGroup.Update(m.textInputComponent, utils.UpdateSizeMsg{
 				ID:    m.textInputComponent,
 				Width: width,
 			}),
+			m.componentGroup.Update(m.errorPanelComponent, utils.UpdateSizeMsg{
+				ID:    m.errorPanelComponent,
+				Width: m.width,
+			}),
+			m.componentGroup.Update(m.formComponent, utils.UpdateSizeMsg{
+				ID:    m.formComponent,
+				Width: m.width,
+			}),
+			m.componentGroup.Update(m.promptComponent, utils.UpdateSizeMsg{
+				ID:    m.promptComponent,
+				Width: m.width,
+			}),
+			m.componentGroup.Update(m.filterFormComponent, utils.UpdateSizeMsg{
+				ID:    m.filterFormComponent,
+				Width: m.width,
+			}),
 		)
 
 		m.componentGroup.Update(m.markdownViewerComponent, utils.UpdateSizeMsg{
@@ -159,7 +229,7 @@ func (m IssuesPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.componentGroup.Update(m.issuesListComponent, utils.UpdateSizeMsg{
 			ID:     m.issuesListComponent,
 			Width:  width,
-			Height: m.height,
+			Height: m.height - 1,
 		})
 		m.componentGroup.Update(m.textInputComponent, utils.UpdateSizeMsg{
 			ID:    m.textInputComponent,
@@ -167,35 +237,77 @@ func (m IssuesPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 		return m, tea.Batch(cmds...)
 	case tea.KeyMsg:
+		m.notice = ""
+		if m.previewing() {
+			// Keys not answering the prompt scroll the preview.
+			return m, tea.Batch(
+				m.componentGroup.UpdateFocused(msg),
+				m.componentGroup.Update(m.previewComponent, msg),
+			)
+		}
+		if m.componentGroup.IsFocused(m.formComponent) ||
+			m.componentGroup.IsFocused(m.promptComponent) ||
+			m.componentGroup.IsFocused(m.filterFormComponent) {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+
+		browsing := m.state == utils.ReadyState &&
+			(m.componentGroup.IsFocused(m.issuesListComponent) || m.componentGroup.IsFocused(m.markdownViewerComponent))
 		switch k := msg.String(); {
+		case k == "r" && m.detail != nil && m.detail.err != nil && m.componentGroup.IsFocused(m.markdownViewerComponent):
+			return m, m.retryDetail()
+		case k == "r" && m.state == utils.ErrorState:
+			return m, m.fetchIssues(m.search, m.currentIssuesPage)
 		case k == "enter" && m.state == utils.ReadyState && m.componentGroup.IsFocused(m.issuesListComponent):
 			issue := m.getSelectedIssue()
-			m.selectedIssue = issue
+			if issue == nil || m.isBeingCreated(issue) {
+				return m, nil
+			}
+			return m, m.openDetail(m.repo, issue, renderIssueDetail(issueDetail{repo: m.repo, issue: issue}))
+		case k == "m" && m.detail != nil && m.componentGroup.IsFocused(m.markdownViewerComponent):
+			return m, m.fetchMoreTimeline()
+		case k == "f" && browsing:
 			return m, tea.Sequence(
-				m.componentGroup.Update(m.issuesListComponent, utils.UpdateSizeMsg{
-					ID:    m.issuesListComponent,
-					Width: m.width / 2,
-				}),
-				m.componentGroup.Update(m.textInputComponent, utils.UpdateSizeMsg{
-					ID:    m.textInputComponent,
-					Width: m.width / 2,
+				m.componentGroup.Update(m.filterFormComponent, components.FormResetMsg{
+					Title:  fmt.Sprintf("Filter issues in %s", m.repo),
+					Fields: m.filters.formFields(),
 				}),
-				m.componentGroup.Update(m.markdownViewerComponent, components.MarkdownViewerSetContentMsg{
-					Content: issue.GetBody(),
-				}),
-				m.componentGroup.FocusOn(m.markdownViewerComponent),
+				m.componentGroup.FocusOn(m.filterFormComponent),
 			)
+		case k == "F" && browsing:
+			return m, m.applyFilters(defaultFilters())
+		case k == "n" && browsing:
+			return m, m.startAction(createIssueAction, nil)
+		case (k == "e" || k == "c" || k == "x" || k == "a") && browsing:
+			issue := m.actionTarget()
+			if issue == nil || m.isBeingCreated(issue) {
+				return m, nil
+			}
+			switch k {
+			case "e":
+				return m, m.startAction(editIssueAction, issue)
+			case "c":
+				return m, m.startAction(commentAction, issue)
+			case "x":
+				if issue.GetState() == "closed" {
+					return m, m.startAction(reopenAction, issue)
+				}
+				return m, m.startAction(closeAction, issue)
+			default:
+				return m, m.showActionMenu(issue)
+			}
 		case k == "esc":
 			if m.componentGroup.IsFocused(m.textInputComponent) {
 				return m, tea.Sequence(
 					m.componentGroup.Update(m.issuesListComponent, utils.UpdateSizeMsg{
 						ID:     m.issuesListComponent,
-						Height: m.height,
+						Height: m.height - 1,
 					}),
 					m.componentGroup.FocusOn(m.issuesListComponent),
 				)
 			} else if m.componentGroup.IsFocused(m.markdownViewerComponent) {
 				m.selectedIssue = nil
+				m.detail = nil
 				return m, tea.Sequence(
 					m.componentGroup.Update(m.issuesListComponent, utils.UpdateSizeMsg{
 						ID:    m.issuesListComponent,
@@ -233,7 +345,7 @@ func (m IssuesPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Sequence(
 				m.componentGroup.Update(m.issuesListComponent, utils.UpdateSizeMsg{
 					ID:     m.issuesListComponent,
-					Height: m.height - 1,
+					Height: m.height - 2,
 				}),
 				m.componentGroup.FocusOn(m.textInputComponent),
 			)
@@ -243,6 +355,55 @@ func (m IssuesPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, m.componentGroup.UpdateFocused(msg)
 		}
+	case utils.OpenItemMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+		issue := &github.Issue{Number: github.Ptr(msg.Number), Title: github.Ptr(msg.Title)}
+		return m, m.openDetail(msg.Repo, issue, fmt.Sprintf("# %s #%d\n\n*Loading…*", msg.Title, msg.Number))
+	case components.FormSubmitMsg:
+		if m.filterFormComponent == msg.ID {
+			filters, err := parseFilterForm(msg.Values)
+			if err != nil {
+				m.setNotice(err.Error(), true)
+				return m, nil
+			}
+			return m, tea.Sequence(
+				m.componentGroup.FocusOn(m.issuesListComponent),
+				m.applyFilters(filters),
+			)
+		}
+		if m.formComponent != msg.ID || m.action == nil {
+			return m, nil
+		}
+		return m, m.handleFormSubmit(msg.Values)
+	case components.FormCancelMsg:
+		if m.filterFormComponent == msg.ID {
+			return m, m.componentGroup.FocusOn(m.issuesListComponent)
+		}
+		if m.formComponent != msg.ID {
+			return m, nil
+		}
+		return m, m.cancelAction()
+	case components.PromptAnswerMsg:
+		if m.promptComponent != msg.ID || m.action == nil {
+			return m, nil
+		}
+		return m, m.handlePromptAnswer(msg.Value)
+	case filtersSavedMsg:
+		if msg.err != nil {
+			m.setNotice("Failed to remember filters: "+msg.err.Error(), true)
+		}
+		return m, nil
+	case components.ComposerDoneMsg:
+		if m.id != msg.ID || m.action == nil {
+			return m, nil
+		}
+		return m, m.handleComposed(msg)
+	case issueMutationDoneMsg:
+		return m, m.handleMutationDone(msg)
+	case issueMutationFailedMsg:
+		return m, m.handleMutationFailed(msg)
 	case components.TextInputSubmitMsg:
 		cmds := []tea.Cmd{m.componentGroup.FocusOn(m.issuesListComponent)}
 		if m.search != msg.Value {
@@ -252,16 +413,67 @@ func (m IssuesPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Sequence(cmds...)
 	case issuesReadyMsg:
 		m.lastIssuesPage = msg.lastIssuesPage
+		if !msg.cached && m.state == utils.ReadyState {
+			// Revalidated results replacing cached ones, the user may already be
+			// browsing them so focus stays where it is.
+			return m, m.componentGroup.Update(m.issuesListComponent, components.IssuesListUpdateIssuesMsg{
+				Issues: msg.issues,
+			})
+		}
 		m.state = utils.ReadyState
+		focus := m.issuesListComponent
+		if m.detail != nil {
+			// An issue was opened from another page while the list loaded.
+			focus = m.markdownViewerComponent
+		}
 		return m, tea.Batch(
-			m.componentGroup.FocusOn(m.issuesListComponent),
+			m.componentGroup.FocusOn(focus),
 			m.componentGroup.Update(m.issuesListComponent, components.IssuesListUpdateIssuesMsg{
 				Issues: msg.issues,
 			}),
 		)
+	case issueDetailReadyMsg:
+		if !m.isDetailResponse(msg.repo, msg.number, msg.request) {
+			// The user moved on, or asked again, before the detail arrived.
+			return m, nil
+		}
+
+		m.detail.issue = msg.issue
+		if msg.appended {
+			m.detail.events = append(m.detail.events, msg.events...)
+		} else {
+			m.detail.events = msg.events
+		}
+		m.detail.nextPage = msg.nextPage
+		m.detail.loaded = true
+		m.detail.loading = false
+		m.detail.err = nil
+		return m, m.componentGroup.Update(m.markdownViewerComponent, components.MarkdownViewerSetContentMsg{
+			Content: renderIssueDetail(*m.detail),
+		})
+	case issueDetailFailedMsg:
+		if !m.isDetailResponse(msg.repo, msg.number, msg.request) {
+			return m, nil
+		}
+
+		m.detail.loading = false
+		m.detail.err = msg.err
+		return m, m.componentGroup.Update(m.markdownViewerComponent, components.MarkdownViewerSetContentMsg{
+			Content: renderIssueDetail(*m.detail),
+		})
 	case issuesLoadingMsg:
 		m.state = utils.LoadingState
 		return m, m.componentGroup.FocusOn(m.spinnerComponent)
+	case utils.ErrorMsg:
+		if m.id != msg.Source {
+			return m, m.componentGroup.UpdateAll(msg)
+		}
+
+		m.state = utils.ErrorState
+		return m, tea.Batch(
+			m.componentGroup.Update(m.errorPanelComponent, components.ErrorPanelSetErrorMsg{Err: msg}),
+			m.componentGroup.FocusOn(m.errorPanelComponent),
+		)
 	default:
 		return m, m.componentGroup.UpdateAll(msg)
 	}
@@ -288,55 +500,152 @@ func (m IssuesPageModel) body() string {
 				m.repo,
 			))
 	case utils.ReadyState:
-		issuesList := m.componentGroup.GetComponent(m.issuesListComponent).View()
-		if m.componentGroup.IsFocused(m.textInputComponent) || m.search != "" {
-			issuesList = lipgloss.JoinVertical(
-				lipgloss.Left,
-				issuesList,
-				m.componentGroup.GetComponent(m.textInputComponent).View(),
-			)
+		body := m.issuesBody()
+		if m.componentGroup.IsFocused(m.formComponent) {
+			body = m.componentGroup.GetComponent(m.formComponent).View()
+		} else if m.componentGroup.IsFocused(m.filterFormComponent) {
+			body = m.componentGroup.GetComponent(m.filterFormComponent).View()
+		} else if m.previewing() {
+			body = m.componentGroup.GetComponent(m.previewComponent).View()
 		}
 
-		if m.selectedIssue == nil {
-			return issuesList
+		var footer string
+		if m.componentGroup.IsFocused(m.promptComponent) {
+			footer = m.componentGroup.GetComponent(m.promptComponent).View()
+		} else if m.notice != "" {
+			style := noticeStyle
+			if m.noticeIsError {
+				style = noticeErrorStyle
+			}
+			footer = style.Width(m.width).MaxHeight(1).Render(m.notice)
 		}
 
-		return lipgloss.JoinHorizontal(
-			lipgloss.Top,
-			issuesList,
-			m.componentGroup.GetComponent(m.markdownViewerComponent).View(),
+		if footer == "" {
+			return body
+		}
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			lipgloss.NewStyle().MaxHeight(max(0, m.height-1)).Render(body),
+			footer,
 		)
+	case utils.ErrorState:
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(m.componentGroup.GetComponent(m.errorPanelComponent).View())
 	default:
 		return ""
 	}
 }
 
+// issuesBody renders the issues list, the search input and the selected issue's detail.
+func (m IssuesPageModel) issuesBody() string {
+	width := m.width
+	if m.selectedIssue != nil {
+		width = m.width / 2
+	}
+	issuesList := lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.filters.chips(width),
+		m.componentGroup.GetComponent(m.issuesListComponent).View(),
+	)
+	if m.componentGroup.IsFocused(m.textInputComponent) || m.search != "" {
+		issuesList = lipgloss.JoinVertical(
+			lipgloss.Left,
+			issuesList,
+			m.componentGroup.GetComponent(m.textInputComponent).View(),
+		)
+	}
+
+	if m.selectedIssue == nil {
+		return issuesList
+	}
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		issuesList,
+		m.componentGroup.GetComponent(m.markdownViewerComponent).View(),
+	)
+}
+
+// openDetail shows issue of repo in the detail pane with content until its
+// detail is loaded.
+func (m *IssuesPageModel) openDetail(repo gh.Repo, issue *github.Issue, content string) tea.Cmd {
+	m.selectedIssue = issue
+	m.detail = &issueDetail{repo: repo, issue: issue}
+	cmds := []tea.Cmd{
+		m.componentGroup.Update(m.issuesListComponent, utils.UpdateSizeMsg{
+			ID:    m.issuesListComponent,
+			Width: m.width / 2,
+		}),
+		m.componentGroup.Update(m.textInputComponent, utils.UpdateSizeMsg{
+			ID:    m.textInputComponent,
+			Width: m.width / 2,
+		}),
+		m.componentGroup.Update(m.markdownViewerComponent, components.MarkdownViewerSetContentMsg{
+			Content: content,
+		}),
+	}
+	if m.state == utils.ReadyState {
+		cmds = append(cmds, m.componentGroup.FocusOn(m.markdownViewerComponent))
+	}
+	cmds = append(cmds, m.fetchIssueDetail(repo, issue.GetNumber()))
+	return tea.Sequence(cmds...)
+}
+
+// applyFilters lists the issues matching filters from the first page and
+// remembers them for the repository.
+func (m *IssuesPageModel) applyFilters(filters issueFilters) tea.Cmd {
+	m.filters = filters
+	m.currentIssuesPage = 1
+	return tea.Batch(
+		m.componentGroup.Update(m.issuesListComponent, components.IssuesListResetViewportMsg{}),
+		m.fetchIssues(m.search, m.currentIssuesPage),
+		saveFilters(m.id, m.repo, filters),
+	)
+}
+
+// fetchIssues shows the cached search results right away, if there are any, and
+// replaces them once revalidated with GitHub. Without cached results the
+// loading spinner is shown instead.
 func (m *IssuesPageModel) fetchIssues(searchTerm string, page int) tea.Cmd {
+	search := gh.IssueSearch{
+		Query:   m.filters.query(m.repo, searchTerm),
+		Sort:    m.filters.Sort,
+		Order:   m.filters.Order,
+		Page:    page,
+		PerPage: 50,
+	}
+
 	return tea.Sequence(
-		issuesLoadingCmd,
-		func() tea.Msg {
-			searchString := fmt.Sprintf("repo:%s is:open is:issue %s", m.repo, searchTerm)
-			result, response, err := m.client.Search.Issues(context.Background(), searchString, &github.SearchOptions{
-				Sort:        "created",
-				Order:       "desc",
-				ListOptions: github.ListOptions{Page: page, PerPage: 50},
-			})
+		utils.PageCmd(m.id, func() tea.Msg {
+			result, err := m.issues.SearchIssues(gh.WithCacheOnly(context.Background()), search)
+			if err != nil {
+				return issuesLoadingMsg{}
+			}
 
-			// TODO: send err as message
-			checkErr(err)
+			return issuesReadyMsg{
+				issues:         result.Issues,
+				lastIssuesPage: result.LastPage,
+				cached:         true,
+			}
+		}),
+		utils.PageCmd(m.id, func() tea.Msg {
+			result, err := m.issues.SearchIssues(context.Background(), search)
+			if err != nil {
+				return utils.NewErrorMsg(m.id, err)
+			}
 
 			return issuesReadyMsg{
 				issues:         result.Issues,
-				lastIssuesPage: response.LastPage,
+				lastIssuesPage: result.LastPage,
 			}
-		},
+		}),
 	)
 }
 
-func issuesLoadingCmd() tea.Msg {
-	return issuesLoadingMsg{}
-}
-
 // TODO: maybe make this a msg that is sent to this component?
 func (m IssuesPageModel) getSelectedIssue() *github.Issue {
 	return m.componentGroup.
@@ -344,9 +653,32 @@ func (m IssuesPageModel) getSelectedIssue() *github.Issue {
 		GetSelectedIssue()
 }
 
-func checkErr(err error) {
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
+// previewing reports whether the body of an action is being previewed before it is sent.
+func (m IssuesPageModel) previewing() bool {
+	return m.componentGroup.IsFocused(m.promptComponent) && m.promptStep == previewStep
+}
+
+func (m IssuesPageModel) getIssues() []*github.Issue {
+	return m.componentGroup.
+		GetComponent(m.issuesListComponent).(components.IssuesListModel).
+		GetIssues()
+}
+
+// isBeingCreated reports whether issue is the placeholder of an issue being
+// created, noting that it cannot be used yet if so.
+func (m *IssuesPageModel) isBeingCreated(issue *github.Issue) bool {
+	if !m.creating[issue] {
+		return false
+	}
+	m.setNotice(issue.GetTitle()+" is still being created", true)
+	return true
+}
+
+// actionTarget returns the issue shown in the detail pane, or the selected one
+// in the list when no issue is open.
+func (m IssuesPageModel) actionTarget() *github.Issue {
+	if m.detail != nil && m.componentGroup.IsFocused(m.markdownViewerComponent) {
+		return m.detail.issue
 	}
+	return m.getSelectedIssue()
 }
@@ -0,0 +1,348 @@
+package issuespage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/gh/ghtest"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/ui/uitest"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+var testRepo = gh.Repo{Owner: "owner", Name: "repo"}
+
+func newTestPage(t *testing.T, issues *ghtest.IssueService) *uitest.Program {
+	t.Helper()
+	// Filters are remembered in the state directory.
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	return uitest.Start(t, NewIssuesPage("issues", issues, testRepo, 120, 40))
+}
+
+func addIssues(issues *ghtest.IssueService, titles ...string) {
+	for _, title := range titles {
+		issues.AddIssue(testRepo, &github.Issue{Title: github.Ptr(title)})
+	}
+}
+
+func page(model tea.Model) IssuesPageModel {
+	return model.(IssuesPageModel)
+}
+
+// listed returns the titles of the issues listed.
+func listed(p *uitest.Program) []string {
+	var titles []string
+	p.Inspect(func(model tea.Model) {
+		for _, issue := range page(model).getIssues() {
+			titles = append(titles, issue.GetTitle())
+		}
+	})
+	return titles
+}
+
+func TestIssuesPageListsOpenIssues(t *testing.T) {
+	issues := &ghtest.IssueService{}
+	addIssues(issues, "first", "second", "closed")
+	issues.Issues[ghtest.Item{Repo: testRepo, Number: 3}].State = github.Ptr("closed")
+	issues.AddIssue(gh.Repo{Owner: "owner", Name: "other"}, &github.Issue{Title: github.Ptr("elsewhere")})
+
+	p := newTestPage(t, issues)
+	p.WaitFor("the issues", func(model tea.Model) bool {
+		return page(model).state == utils.ReadyState && len(page(model).getIssues()) > 0
+	})
+
+	if got := listed(p); len(got) != 2 || got[0] != "second" || got[1] != "first" {
+		t.Errorf("listed %q, want [second first]", got)
+	}
+	search := issues.Calls("SearchIssues")[0].Args[0].(gh.IssueSearch)
+	if want := "repo:owner/repo is:issue is:open"; search.Query != want {
+		t.Errorf("searched %q, want %q", search.Query, want)
+	}
+}
+
+func TestIssuesPageRetriesAfterError(t *testing.T) {
+	issues := &ghtest.IssueService{}
+	addIssues(issues, "first")
+	issues.FailWith("SearchIssues", errors.New("502 Bad Gateway"))
+
+	p := newTestPage(t, issues)
+	p.WaitFor("the error", func(model tea.Model) bool {
+		return page(model).state == utils.ErrorState
+	})
+
+	issues.FailWith("SearchIssues", nil)
+	p.Type("r")
+	p.WaitFor("the issues", func(model tea.Model) bool {
+		return page(model).state == utils.ReadyState && len(page(model).getIssues()) > 0
+	})
+	if got := listed(p); len(got) != 1 || got[0] != "first" {
+		t.Errorf("listed %q, want [first]", got)
+	}
+}
+
+func TestIssuesPageOpensIssueWithTimeline(t *testing.T) {
+	issues := &ghtest.IssueService{TimelinePageSize: 1}
+	addIssues(issues, "first")
+	issues.Timelines = map[ghtest.Item][]*github.Timeline{
+		{Repo: testRepo, Number: 1}: {
+			{Event: github.Ptr("commented"), Body: github.Ptr("one")},
+			{Event: github.Ptr("commented"), Body: github.Ptr("two")},
+		},
+	}
+
+	p := newTestPage(t, issues)
+	p.WaitFor("the issues", func(model tea.Model) bool {
+		return page(model).state == utils.ReadyState && len(page(model).getIssues()) > 0
+	})
+
+	p.Type("enter")
+	p.WaitFor("the first timeline page", func(model tea.Model) bool {
+		detail := page(model).detail
+		return detail != nil && len(detail.events) == 1 && detail.nextPage == 2
+	})
+
+	p.Type("m")
+	p.WaitFor("the whole timeline", func(model tea.Model) bool {
+		detail := page(model).detail
+		return detail != nil && len(detail.events) == 2 && detail.nextPage == 0
+	})
+}
+
+func TestIssuesPageSearchesTitles(t *testing.T) {
+	issues := &ghtest.IssueService{}
+	addIssues(issues, "crash on start", "typo in docs")
+
+	p := newTestPage(t, issues)
+	p.WaitFor("the issues", func(model tea.Model) bool {
+		return len(page(model).getIssues()) == 2
+	})
+
+	p.Type("/", "c", "r", "a", "s", "h", "enter")
+	p.WaitFor("the search results", func(model tea.Model) bool {
+		return len(page(model).getIssues()) == 1
+	})
+	if got := listed(p); got[0] != "crash on start" {
+		t.Errorf("listed %q, want [crash on start]", got)
+	}
+}
+
+func TestIssuesPageShowsDetailErrorsInDetailPane(t *testing.T) {
+	issues := &ghtest.IssueService{}
+	addIssues(issues, "first")
+	issues.FailWith("GetIssue", errors.New("502 Bad Gateway"))
+
+	p := newTestPage(t, issues)
+	p.WaitFor("the issues", func(model tea.Model) bool {
+		return page(model).state == utils.ReadyState && len(page(model).getIssues()) > 0
+	})
+
+	p.Type("enter")
+	p.WaitFor("the detail error", func(model tea.Model) bool {
+		detail := page(model).detail
+		return detail != nil && detail.err != nil
+	})
+	p.Inspect(func(model tea.Model) {
+		if state := page(model).state; state != utils.ReadyState {
+			t.Errorf("page state %v, want the issues list to stay shown", state)
+		}
+	})
+
+	issues.FailWith("GetIssue", nil)
+	searches := len(issues.Calls("SearchIssues"))
+	p.Type("r")
+	p.WaitFor("the detail", func(model tea.Model) bool {
+		detail := page(model).detail
+		return detail != nil && detail.loaded && detail.err == nil
+	})
+	if got := len(issues.Calls("SearchIssues")); got != searches {
+		t.Errorf("retrying the detail searched the issues %d more times", got-searches)
+	}
+}
+
+func TestIssuesPageLoadsEachTimelinePageOnce(t *testing.T) {
+	issues := &ghtest.IssueService{TimelinePageSize: 1}
+	addIssues(issues, "first")
+	issues.Timelines = map[ghtest.Item][]*github.Timeline{
+		{Repo: testRepo, Number: 1}: {
+			{Event: github.Ptr("commented"), Body: github.Ptr("one")},
+			{Event: github.Ptr("commented"), Body: github.Ptr("two")},
+			{Event: github.Ptr("commented"), Body: github.Ptr("three")},
+		},
+	}
+
+	p := newTestPage(t, issues)
+	p.WaitFor("the issues", func(model tea.Model) bool {
+		return page(model).state == utils.ReadyState && len(page(model).getIssues()) > 0
+	})
+	p.Type("enter")
+	p.WaitFor("the first timeline page", func(model tea.Model) bool {
+		detail := page(model).detail
+		return detail != nil && detail.loaded
+	})
+
+	p.Type("m", "m")
+	p.WaitFor("the next timeline page", func(model tea.Model) bool {
+		detail := page(model).detail
+		return detail != nil && !detail.loading && len(detail.events) > 1
+	})
+	p.Inspect(func(model tea.Model) {
+		seen := make(map[string]bool)
+		for _, event := range page(model).detail.events {
+			if seen[event.GetBody()] {
+				t.Errorf("timeline event %q shown twice", event.GetBody())
+			}
+			seen[event.GetBody()] = true
+		}
+	})
+}
+
+// useEditor makes the composer's editor replace the file with text.
+func useEditor(t *testing.T, text string) {
+	t.Helper()
+	editor := filepath.Join(t.TempDir(), "editor")
+	script := "#!/bin/sh\nprintf '%s\\n' '" + text + "' > \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", editor)
+}
+
+func TestIssuesPageRefusesIssueBeingCreated(t *testing.T) {
+	issues := &ghtest.IssueService{}
+	addIssues(issues, "first")
+	useEditor(t, "It crashes.")
+	release := issues.Hold("CreateIssue")
+	defer release()
+
+	p := newTestPage(t, issues)
+	p.WaitFor("the issues", func(model tea.Model) bool {
+		return page(model).state == utils.ReadyState && len(page(model).getIssues()) > 0
+	})
+
+	p.Type("n")
+	var form string
+	p.Inspect(func(model tea.Model) { form = page(model).formComponent })
+	p.Send(components.FormSubmitMsg{ID: form, Values: []string{"Crash", "", "", ""}})
+	p.WaitFor("the preview", func(model tea.Model) bool {
+		return page(model).previewing()
+	})
+	p.Type("y")
+	p.WaitFor("the placeholder", func(model tea.Model) bool {
+		return len(page(model).creating) == 1 && page(model).getSelectedIssue().Number == nil
+	})
+
+	p.Type("enter", "e", "x")
+	p.Inspect(func(model tea.Model) {
+		m := page(model)
+		if m.detail != nil || m.action != nil {
+			t.Errorf("the placeholder was opened or acted on: detail %v, action %v", m.detail, m.action)
+		}
+	})
+	if calls := issues.Calls("GetIssue"); len(calls) != 0 {
+		t.Errorf("fetched %v for the placeholder", calls)
+	}
+
+	release()
+	p.WaitFor("the created issue", func(model tea.Model) bool {
+		return len(page(model).creating) == 0 && page(model).getSelectedIssue().GetNumber() == 2
+	})
+	p.Type("enter")
+	p.WaitFor("the detail of the created issue", func(model tea.Model) bool {
+		detail := page(model).detail
+		return detail != nil && detail.loaded && detail.issue.GetNumber() == 2
+	})
+}
+
+// editIssue edits the body of the selected issue in the editor, up to the
+// preview of the change.
+func editIssue(t *testing.T, p *uitest.Program) {
+	t.Helper()
+	p.Type("e")
+	var form string
+	p.Inspect(func(model tea.Model) { form = page(model).formComponent })
+	p.Send(components.FormSubmitMsg{ID: form, Values: []string{"first"}})
+}
+
+func TestIssuesPageEditClearsBody(t *testing.T) {
+	issues := &ghtest.IssueService{}
+	issues.AddIssue(testRepo, &github.Issue{Title: github.Ptr("first"), Body: github.Ptr("Old body")})
+	useEditor(t, "")
+
+	p := newTestPage(t, issues)
+	p.WaitFor("the issues", func(model tea.Model) bool {
+		return page(model).state == utils.ReadyState && len(page(model).getIssues()) > 0
+	})
+	editIssue(t, p)
+	p.WaitFor("the preview", func(model tea.Model) bool {
+		return page(model).previewing()
+	})
+	p.Type("y")
+	p.WaitFor("the edit", func(model tea.Model) bool {
+		return page(model).notice == "Saved #1"
+	})
+	if body := issues.Issues[ghtest.Item{Repo: testRepo, Number: 1}].GetBody(); body != "" {
+		t.Errorf("body %q, want it cleared", body)
+	}
+}
+
+func TestIssuesPageCancelsUnsavedEdit(t *testing.T) {
+	issues := &ghtest.IssueService{}
+	addIssues(issues, "first")
+	t.Setenv("VISUAL", "true")
+
+	p := newTestPage(t, issues)
+	p.WaitFor("the issues", func(model tea.Model) bool {
+		return page(model).state == utils.ReadyState && len(page(model).getIssues()) > 0
+	})
+	editIssue(t, p)
+	p.WaitFor("the cancellation", func(model tea.Model) bool {
+		return page(model).notice == "Cancelled" && page(model).action == nil
+	})
+	if calls := issues.Calls("EditIssue"); len(calls) != 0 {
+		t.Errorf("edited the issue with %v", calls)
+	}
+}
+
+// prompting reports whether the page's prompt asks the question of step.
+func prompting(model tea.Model, step promptStep) bool {
+	m := page(model)
+	return m.componentGroup.IsFocused(m.promptComponent) && m.promptStep == step
+}
+
+func TestIssuesPageRestoresIssueWhenCloseFails(t *testing.T) {
+	issues := &ghtest.IssueService{}
+	addIssues(issues, "first", "second", "third")
+	issues.FailWith("EditIssue", errors.New("502 Bad Gateway"))
+
+	p := newTestPage(t, issues)
+	p.WaitFor("the issues", func(model tea.Model) bool {
+		return page(model).state == utils.ReadyState && len(page(model).getIssues()) > 0
+	})
+	p.Type("x")
+	p.WaitFor("the close reasons", func(model tea.Model) bool {
+		return prompting(model, closeReasonStep)
+	})
+	p.Type("c")
+	p.WaitFor("the confirmation", func(model tea.Model) bool {
+		return prompting(model, confirmStep)
+	})
+	p.Type("y")
+	p.WaitFor("the failure", func(model tea.Model) bool {
+		return page(model).noticeIsError
+	})
+
+	if got := listed(p); len(got) != 3 || got[0] != "third" || got[1] != "second" || got[2] != "first" {
+		t.Errorf("listed %v, want [third second first]", got)
+	}
+	p.Inspect(func(model tea.Model) {
+		if state := page(model).getIssues()[0].GetState(); state == "closed" {
+			t.Errorf("the issue still shows as %s", state)
+		}
+	})
+}
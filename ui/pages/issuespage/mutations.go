@@ -0,0 +1,593 @@
+package issuespage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+type issueActionKind int
+
+const (
+	createIssueAction issueActionKind = iota
+	editIssueAction
+	commentAction
+	closeAction
+	reopenAction
+	lockAction
+	unlockAction
+)
+
+// The question the page's prompt is currently asking.
+type promptStep int
+
+const (
+	actionMenuStep promptStep = iota
+	closeReasonStep
+	lockReasonStep
+	confirmStep
+	previewStep
+)
+
+// issueAction is a mutation being prepared through the form and prompt, sent
+// once the user confirms it.
+type issueAction struct {
+	kind issueActionKind
+	// The issue acted on, nil when creating one, and its repository.
+	issue *github.Issue
+	repo  gh.Repo
+
+	title     string
+	body      string
+	labels    []string
+	assignees []string
+	// Milestone number or title, resolved when the action is sent.
+	milestone string
+
+	stateReason string
+	lockReason  string
+}
+
+type issueMutationDoneMsg struct {
+	action issueAction
+	// The issue as returned by GitHub, or the optimistic copy for actions that
+	// do not return one.
+	issue *github.Issue
+	// The optimistic stand-in the returned issue replaces.
+	placeholder *github.Issue
+}
+
+type issueMutationFailedMsg struct {
+	action issueAction
+	err    utils.ErrorMsg
+	// The optimistic stand-in to roll back.
+	placeholder *github.Issue
+	// Where the issue acted on was in the list, -1 if it was not, so it can be
+	// put back if its optimistic version was filtered out.
+	index int
+}
+
+// startAction begins preparing an action of kind on issue, showing the form or
+// prompt it needs first.
+func (m *IssuesPageModel) startAction(kind issueActionKind, issue *github.Issue) tea.Cmd {
+	if !m.componentGroup.IsFocused(m.promptComponent) {
+		m.returnFocus = m.componentGroup.GetFocusedComponentName()
+	}
+	m.action = &issueAction{kind: kind, issue: issue, repo: m.repoOf(issue)}
+
+	switch kind {
+	case createIssueAction:
+		return m.showForm(fmt.Sprintf("New issue in %s", m.repo), []components.FormField{
+			{Label: "Title", Hint: "The body is written in your editor next"},
+			{Label: "Labels", Hint: "Comma separated label names"},
+			{Label: "Assignees", Hint: "Comma separated logins"},
+			{Label: "Milestone", Hint: "Milestone number or title"},
+		})
+	case editIssueAction:
+		return m.showForm(fmt.Sprintf("Edit #%d", issue.GetNumber()), []components.FormField{
+			{Label: "Title", Value: issue.GetTitle(), Hint: "The body is written in your editor next"},
+		})
+	case commentAction:
+		return m.compose()
+	case closeAction:
+		return m.showPrompt(closeReasonStep, fmt.Sprintf("Close #%d as", issue.GetNumber()), []components.PromptOption{
+			{Key: "c", Label: "completed", Value: "completed"},
+			{Key: "n", Label: "not planned", Value: "not_planned"},
+		})
+	case lockAction:
+		return m.showPrompt(lockReasonStep, fmt.Sprintf("Lock #%d because", issue.GetNumber()), []components.PromptOption{
+			{Key: "o", Label: "off-topic", Value: "off-topic"},
+			{Key: "h", Label: "too heated", Value: "too heated"},
+			{Key: "r", Label: "resolved", Value: "resolved"},
+			{Key: "s", Label: "spam", Value: "spam"},
+			{Key: "l", Label: "no reason", Value: "none"},
+		})
+	default:
+		return m.confirmAction()
+	}
+}
+
+// showActionMenu asks which action to take on issue.
+func (m *IssuesPageModel) showActionMenu(issue *github.Issue) tea.Cmd {
+	m.action = &issueAction{issue: issue, repo: m.repoOf(issue)}
+	m.returnFocus = m.componentGroup.GetFocusedComponentName()
+
+	state := components.PromptOption{Key: "x", Label: "close", Value: "close"}
+	if issue.GetState() == "closed" {
+		state = components.PromptOption{Key: "x", Label: "reopen", Value: "reopen"}
+	}
+	lock := components.PromptOption{Key: "l", Label: "lock", Value: "lock"}
+	if issue.GetLocked() {
+		lock = components.PromptOption{Key: "l", Label: "unlock", Value: "unlock"}
+	}
+	return m.showPrompt(actionMenuStep, fmt.Sprintf("#%d", issue.GetNumber()), []components.PromptOption{
+		{Key: "e", Label: "edit", Value: "edit"},
+		{Key: "c", Label: "comment", Value: "comment"},
+		state,
+		lock,
+	})
+}
+
+func (m *IssuesPageModel) showForm(title string, fields []components.FormField) tea.Cmd {
+	return tea.Sequence(
+		m.componentGroup.Update(m.formComponent, components.FormResetMsg{Title: title, Fields: fields}),
+		m.componentGroup.FocusOn(m.formComponent),
+	)
+}
+
+func (m *IssuesPageModel) showPrompt(step promptStep, question string, options []components.PromptOption) tea.Cmd {
+	m.promptStep = step
+	return tea.Sequence(
+		m.componentGroup.Update(m.promptComponent, components.PromptResetMsg{Question: question, Options: options}),
+		m.componentGroup.FocusOn(m.promptComponent),
+	)
+}
+
+// confirmAction asks for confirmation of the prepared action before sending it.
+// Actions with a body are confirmed from their preview instead.
+func (m *IssuesPageModel) confirmAction() tea.Cmd {
+	action := m.action
+	number := action.issue.GetNumber()
+
+	var question string
+	switch action.kind {
+	case closeAction:
+		question = fmt.Sprintf("Close #%d as %s?", number, strings.ReplaceAll(action.stateReason, "_", " "))
+	case reopenAction:
+		question = fmt.Sprintf("Reopen #%d?", number)
+	case lockAction:
+		question = fmt.Sprintf("Lock #%d?", number)
+	case unlockAction:
+		question = fmt.Sprintf("Unlock #%d?", number)
+	}
+	return m.showPrompt(confirmStep, question, components.ConfirmOptions)
+}
+
+// cancelAction drops the prepared action and returns focus to where it was.
+func (m *IssuesPageModel) cancelAction() tea.Cmd {
+	m.action = nil
+	focus := m.returnFocus
+	if focus == "" {
+		focus = m.issuesListComponent
+	}
+	return m.componentGroup.FocusOn(focus)
+}
+
+// handleFormSubmit fills the prepared action in from the form's values.
+func (m *IssuesPageModel) handleFormSubmit(values []string) tea.Cmd {
+	action := m.action
+	switch action.kind {
+	case createIssueAction:
+		action.title = values[0]
+		action.labels = splitList(values[1])
+		action.assignees = splitList(values[2])
+		action.milestone = values[3]
+	case editIssueAction:
+		action.title = values[0]
+		action.body = action.issue.GetBody()
+	}
+	if action.title == "" {
+		m.setNotice("An issue needs a title", true)
+		return nil
+	}
+	return m.compose()
+}
+
+// compose opens the editor on the body of the prepared action, with the issue
+// it is about as context.
+func (m *IssuesPageModel) compose() tea.Cmd {
+	action := m.action
+	var instructions, context string
+	switch action.kind {
+	case createIssueAction:
+		instructions = fmt.Sprintf("Write the body of %q above, in markdown.", action.title)
+	case editIssueAction:
+		instructions = fmt.Sprintf("Edit the body of #%d above, in markdown.", action.issue.GetNumber())
+	case commentAction:
+		instructions = fmt.Sprintf("Write your comment on #%d above, in markdown.", action.issue.GetNumber())
+		context = fmt.Sprintf("# %s #%d\n\n%s", action.issue.GetTitle(), action.issue.GetNumber(), action.issue.GetBody())
+	}
+	return components.Compose(m.id, components.ComposerTemplate(action.body, instructions, context))
+}
+
+// handleComposed previews the text written in the editor before the action is sent.
+func (m *IssuesPageModel) handleComposed(msg components.ComposerDoneMsg) tea.Cmd {
+	if msg.Err != nil {
+		m.setNotice("Editor failed: "+msg.Err.Error(), true)
+		return m.cancelAction()
+	}
+	if msg.Cancelled {
+		m.setNotice("Cancelled", false)
+		return m.cancelAction()
+	}
+	if msg.Text == "" && m.action.kind == commentAction {
+		m.setNotice("A comment cannot be empty, cancelled", true)
+		return m.cancelAction()
+	}
+
+	action := m.action
+	action.body = msg.Text
+
+	preview := strings.Builder{}
+	var question string
+	switch action.kind {
+	case createIssueAction:
+		fmt.Fprintf(&preview, "# %s\n\n", action.title)
+		question = "Create this issue?"
+	case editIssueAction:
+		fmt.Fprintf(&preview, "# %s #%d\n\n", action.title, action.issue.GetNumber())
+		question = fmt.Sprintf("Save changes to #%d?", action.issue.GetNumber())
+	case commentAction:
+		question = fmt.Sprintf("Post this comment on #%d?", action.issue.GetNumber())
+	}
+	if action.body != "" {
+		preview.WriteString(action.body)
+	} else {
+		preview.WriteString("*No description provided.*")
+	}
+
+	return tea.Sequence(
+		m.componentGroup.Update(m.previewComponent, utils.UpdateSizeMsg{
+			ID:     m.previewComponent,
+			Width:  m.width,
+			Height: m.height - 1,
+		}),
+		m.componentGroup.Update(m.previewComponent, components.MarkdownViewerSetContentMsg{
+			Content: preview.String(),
+		}),
+		m.showPrompt(previewStep, question, []components.PromptOption{
+			{Key: "y", Label: "submit", Value: "yes"},
+			{Key: "e", Label: "edit again", Value: "edit"},
+			{Key: "n", Label: "discard", Value: "no"},
+		}),
+	)
+}
+
+// handlePromptAnswer moves the prepared action on according to the prompt's answer.
+func (m *IssuesPageModel) handlePromptAnswer(value string) tea.Cmd {
+	if value == "" || value == "no" {
+		return m.cancelAction()
+	}
+
+	action := m.action
+	switch m.promptStep {
+	case actionMenuStep:
+		issue := action.issue
+		switch value {
+		case "edit":
+			return m.startAction(editIssueAction, issue)
+		case "comment":
+			return m.startAction(commentAction, issue)
+		case "close":
+			return m.startAction(closeAction, issue)
+		case "reopen":
+			return m.startAction(reopenAction, issue)
+		case "lock":
+			return m.startAction(lockAction, issue)
+		case "unlock":
+			return m.startAction(unlockAction, issue)
+		}
+		return m.cancelAction()
+	case closeReasonStep:
+		action.stateReason = value
+		return m.confirmAction()
+	case lockReasonStep:
+		if value != "none" {
+			action.lockReason = value
+		}
+		return m.confirmAction()
+	case previewStep:
+		if value == "edit" {
+			return m.compose()
+		}
+		fallthrough
+	default:
+		m.action = nil
+		return tea.Batch(
+			m.cancelAction(),
+			m.sendAction(*action),
+		)
+	}
+}
+
+// sendAction applies action to the issues list and detail optimistically, then
+// sends it to GitHub. A failure rolls the issue acted on back.
+func (m *IssuesPageModel) sendAction(action issueAction) tea.Cmd {
+	current := m.getIssues()
+	optimistic := optimisticIssue(action)
+	issues := make([]*github.Issue, 0, len(current)+1)
+	if action.kind == createIssueAction {
+		m.creating[optimistic] = true
+		if m.listShows(optimistic) {
+			issues = append(issues, optimistic)
+		}
+	}
+	index := -1
+	for i, issue := range current {
+		if action.issue != nil && action.repo == m.repo && issue.GetNumber() == action.issue.GetNumber() {
+			index = i
+			if !m.listShows(optimistic) {
+				continue
+			}
+			issue = optimistic
+		}
+		issues = append(issues, issue)
+	}
+
+	cmds := []tea.Cmd{
+		m.componentGroup.Update(m.issuesListComponent, components.IssuesListUpdateIssuesMsg{Issues: issues}),
+	}
+	if m.detail != nil && action.issue != nil && m.detail.repo == action.repo && m.detail.issue.GetNumber() == action.issue.GetNumber() {
+		m.detail.issue = optimistic
+		cmds = append(cmds, m.componentGroup.Update(m.markdownViewerComponent, components.MarkdownViewerSetContentMsg{
+			Content: renderIssueDetail(*m.detail),
+		}))
+	}
+
+	id := m.id
+	repo := action.repo
+	service := m.issues
+	cmds = append(cmds, utils.PageCmd(m.id, func() tea.Msg {
+		issue, err := performAction(context.Background(), service, repo, action, optimistic)
+		if err != nil {
+			return issueMutationFailedMsg{
+				action:      action,
+				err:         utils.NewErrorMsg(id, err),
+				placeholder: optimistic,
+				index:       index,
+			}
+		}
+		return issueMutationDoneMsg{action: action, issue: issue, placeholder: optimistic}
+	}))
+	return tea.Batch(cmds...)
+}
+
+// handleMutationDone swaps the optimistic issue for GitHub's version.
+func (m *IssuesPageModel) handleMutationDone(msg issueMutationDoneMsg) tea.Cmd {
+	delete(m.creating, msg.placeholder)
+	issues := make([]*github.Issue, 0)
+	for _, issue := range m.getIssues() {
+		if issue == msg.placeholder {
+			if !m.listShows(msg.issue) {
+				continue
+			}
+			issue = msg.issue
+		}
+		issues = append(issues, issue)
+	}
+
+	number := msg.issue.GetNumber()
+	switch msg.action.kind {
+	case createIssueAction:
+		m.setNotice(fmt.Sprintf("Created #%d", number), false)
+	case editIssueAction:
+		m.setNotice(fmt.Sprintf("Saved #%d", number), false)
+	case commentAction:
+		m.setNotice(fmt.Sprintf("Commented on #%d", number), false)
+	case closeAction:
+		m.setNotice(fmt.Sprintf("Closed #%d", number), false)
+	case reopenAction:
+		m.setNotice(fmt.Sprintf("Reopened #%d", number), false)
+	case lockAction:
+		m.setNotice(fmt.Sprintf("Locked #%d", number), false)
+	case unlockAction:
+		m.setNotice(fmt.Sprintf("Unlocked #%d", number), false)
+	}
+
+	cmds := []tea.Cmd{
+		m.componentGroup.Update(m.issuesListComponent, components.IssuesListUpdateIssuesMsg{Issues: issues}),
+	}
+	if m.detail != nil && m.detail.repo == msg.action.repo && m.detail.issue.GetNumber() == number {
+		cmds = append(cmds, m.fetchIssueDetail(m.detail.repo, number))
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleMutationFailed puts the issue acted on back in place of its optimistic
+// version, or drops the one that was being created, and reports the error.
+// The rest of the list is left as it is now.
+func (m *IssuesPageModel) handleMutationFailed(msg issueMutationFailedMsg) tea.Cmd {
+	delete(m.creating, msg.placeholder)
+	m.setNotice("Failed: "+msg.err.Error(), true)
+
+	original := msg.action.issue
+	restored := original == nil
+	issues := make([]*github.Issue, 0)
+	for _, issue := range m.getIssues() {
+		if original != nil && msg.action.repo == m.repo && issue.GetNumber() == original.GetNumber() {
+			restored = true
+		}
+		if issue == msg.placeholder {
+			if original == nil {
+				continue
+			}
+			issue = original
+		}
+		issues = append(issues, issue)
+	}
+	if !restored && msg.index >= 0 && m.listShows(original) {
+		index := min(msg.index, len(issues))
+		issues = append(issues[:index], append([]*github.Issue{original}, issues[index:]...)...)
+	}
+
+	cmds := []tea.Cmd{
+		m.componentGroup.Update(m.issuesListComponent, components.IssuesListUpdateIssuesMsg{Issues: issues}),
+	}
+	if m.detail != nil && msg.action.issue != nil && m.detail.repo == msg.action.repo && m.detail.issue.GetNumber() == msg.action.issue.GetNumber() {
+		m.detail.issue = msg.action.issue
+		cmds = append(cmds,
+			m.componentGroup.Update(m.markdownViewerComponent, components.MarkdownViewerSetContentMsg{
+				Content: renderIssueDetail(*m.detail),
+			}),
+		)
+	}
+	return tea.Batch(cmds...)
+}
+
+// listShows reports whether issue belongs in the list given its filters.
+func (m *IssuesPageModel) listShows(issue *github.Issue) bool {
+	return m.filters.matchesState(issue.GetState())
+}
+
+// repoOf returns the repository of issue: the page's, unless issue is the one
+// opened in the detail pane from another repository.
+func (m *IssuesPageModel) repoOf(issue *github.Issue) gh.Repo {
+	if m.detail != nil && issue != nil && issue == m.detail.issue {
+		return m.detail.repo
+	}
+	return m.repo
+}
+
+func (m *IssuesPageModel) setNotice(notice string, isError bool) {
+	m.notice = notice
+	m.noticeIsError = isError
+}
+
+// optimisticIssue returns a copy of the action's issue as it will look once the
+// action succeeds. The issue created by `createIssueAction` has no number until
+// GitHub's version replaces it.
+func optimisticIssue(action issueAction) *github.Issue {
+	issue := &github.Issue{}
+	if action.issue != nil {
+		copied := *action.issue
+		issue = &copied
+	}
+
+	switch action.kind {
+	case createIssueAction:
+		issue.Title = &action.title
+		issue.Body = &action.body
+		issue.State = github.Ptr("open")
+	case editIssueAction:
+		issue.Title = &action.title
+		issue.Body = &action.body
+	case commentAction:
+		issue.Comments = github.Ptr(issue.GetComments() + 1)
+	case closeAction:
+		issue.State = github.Ptr("closed")
+		issue.StateReason = &action.stateReason
+	case reopenAction:
+		issue.State = github.Ptr("open")
+		issue.StateReason = github.Ptr("reopened")
+	case lockAction:
+		issue.Locked = github.Ptr(true)
+	case unlockAction:
+		issue.Locked = github.Ptr(false)
+	}
+	return issue
+}
+
+// performAction sends action to GitHub and returns the resulting issue. Actions
+// that do not return the issue yield the optimistic one.
+func performAction(
+	ctx context.Context,
+	service gh.IssueService,
+	repo gh.Repo,
+	action issueAction,
+	optimistic *github.Issue,
+) (*github.Issue, error) {
+	number := action.issue.GetNumber()
+	switch action.kind {
+	case createIssueAction:
+		request := &github.IssueRequest{Title: &action.title}
+		if action.body != "" {
+			request.Body = &action.body
+		}
+		if len(action.labels) > 0 {
+			request.Labels = &action.labels
+		}
+		if len(action.assignees) > 0 {
+			request.Assignees = &action.assignees
+		}
+		if action.milestone != "" {
+			milestone, err := resolveMilestone(ctx, service, repo, action.milestone)
+			if err != nil {
+				return nil, err
+			}
+			request.Milestone = &milestone
+		}
+		return service.CreateIssue(ctx, repo, request)
+	case editIssueAction:
+		return service.EditIssue(ctx, repo, number, &github.IssueRequest{
+			Title: &action.title,
+			Body:  &action.body,
+		})
+	case commentAction:
+		if _, err := service.CreateComment(ctx, repo, number, action.body); err != nil {
+			return nil, err
+		}
+		return optimistic, nil
+	case closeAction:
+		return service.EditIssue(ctx, repo, number, &github.IssueRequest{
+			State:       github.Ptr("closed"),
+			StateReason: &action.stateReason,
+		})
+	case reopenAction:
+		return service.EditIssue(ctx, repo, number, &github.IssueRequest{
+			State: github.Ptr("open"),
+		})
+	case lockAction:
+		return optimistic, service.Lock(ctx, repo, number, action.lockReason)
+	case unlockAction:
+		return optimistic, service.Unlock(ctx, repo, number)
+	default:
+		return nil, fmt.Errorf("unknown issue action %d", action.kind)
+	}
+}
+
+// resolveMilestone turns a milestone number or title into its number.
+func resolveMilestone(ctx context.Context, service gh.IssueService, repo gh.Repo, milestone string) (int, error) {
+	if number, err := strconv.Atoi(milestone); err == nil {
+		return number, nil
+	}
+
+	milestones, err := service.ListMilestones(ctx, repo)
+	if err != nil {
+		return 0, err
+	}
+	for _, m := range milestones {
+		if strings.EqualFold(m.GetTitle(), milestone) {
+			return m.GetNumber(), nil
+		}
+	}
+	return 0, fmt.Errorf("no open milestone titled %q", milestone)
+}
+
+// splitList splits a comma separated list, dropping empty entries.
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
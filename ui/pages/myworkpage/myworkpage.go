@@ -0,0 +1,272 @@
+package myworkpage
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+var (
+	chipStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("230")).
+			Background(lipgloss.Color("62")).
+			Padding(0, 1).
+			MarginRight(1)
+	sectionStyle     = lipgloss.NewStyle().Bold(true)
+	repoStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	successStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	failureStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	pendingStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	mutedStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	noticeStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	noticeErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+// MyWorkPageModel lists what waits on the user across repositories: pull
+// requests to review, their own pull requests, issues assigned to them and
+// what mentions them.
+type MyWorkPageModel struct {
+	id     string
+	width  int
+	height int
+
+	search gh.SearchService
+	state  utils.ComponentState
+	work   gh.MyWork
+
+	notice        string
+	noticeIsError bool
+
+	componentGroup      utils.ComponentGroup
+	spinnerComponent    string
+	errorPanelComponent string
+	listComponent       string
+}
+
+type workLoadingMsg struct{}
+
+type workReadyMsg struct {
+	work gh.MyWork
+}
+
+type refreshFailedMsg struct {
+	err error
+}
+
+func NewMyWorkPage(id string, search gh.SearchService, width int, height int) MyWorkPageModel {
+	spinner := components.NewSpinnerComponent()
+	errorPanel := components.NewErrorPanelComponent(width)
+	list := components.NewListComponent(width, height-1, renderRow)
+
+	return MyWorkPageModel{
+		id:     id,
+		width:  width,
+		height: height,
+		search: search,
+		state:  utils.LoadingState,
+		componentGroup: utils.NewComponentGroup(
+			spinner,
+			errorPanel,
+			list,
+		),
+		spinnerComponent:    spinner.ID(),
+		errorPanelComponent: errorPanel.ID(),
+		listComponent:       list.ID(),
+	}
+}
+
+func (m MyWorkPageModel) ID() string {
+	return m.id
+}
+
+func (m MyWorkPageModel) Init() tea.Cmd {
+	return tea.Sequence(
+		m.fetchWork(),
+		m.componentGroup.Init(),
+	)
+}
+
+func (m MyWorkPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case utils.PageMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+		return m.Update(msg.Msg)
+	case utils.FocusMsg:
+		if m.id != msg.ID {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+		return m, m.fetchWork()
+	case utils.BlurMsg:
+		if m.id != msg.ID {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+		return m, nil
+	case utils.UpdateSizeMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+
+		if msg.Width == 0 && msg.Height == 0 {
+			return m, nil
+		}
+
+		if msg.Width > 0 {
+			m.width = msg.Width
+		}
+		if msg.Height > 0 {
+			m.height = msg.Height
+		}
+
+		return m, tea.Batch(
+			m.componentGroup.Update(m.listComponent, utils.UpdateSizeMsg{
+				ID:     m.listComponent,
+				Width:  m.width,
+				Height: m.height - 1,
+			}),
+			m.componentGroup.Update(m.errorPanelComponent, utils.UpdateSizeMsg{
+				ID:    m.errorPanelComponent,
+				Width: m.width,
+			}),
+		)
+	case tea.KeyMsg:
+		m.notice = ""
+		if m.state == utils.ErrorState {
+			if msg.String() == "r" {
+				return m, m.fetchWork()
+			}
+			return m, nil
+		}
+		if m.state != utils.ReadyState {
+			return m, nil
+		}
+		return m, m.handleKey(msg)
+	case workLoadingMsg:
+		m.state = utils.LoadingState
+		return m, m.componentGroup.FocusOn(m.spinnerComponent)
+	case workReadyMsg:
+		m.work = msg.work
+		cmds := []tea.Cmd{
+			m.componentGroup.Update(m.listComponent, components.ListSetItemsMsg[workRow]{
+				Items: sectionRows(m.work),
+			}),
+		}
+		if m.state != utils.ReadyState {
+			m.state = utils.ReadyState
+			cmds = append(cmds, m.componentGroup.FocusOn(m.listComponent))
+		}
+		return m, tea.Batch(cmds...)
+	case refreshFailedMsg:
+		m.setNotice("Failed to refresh: "+msg.err.Error(), true)
+		return m, nil
+	case utils.ErrorMsg:
+		if m.id != msg.Source {
+			return m, m.componentGroup.UpdateAll(msg)
+		}
+
+		m.state = utils.ErrorState
+		return m, tea.Batch(
+			m.componentGroup.Update(m.errorPanelComponent, components.ErrorPanelSetErrorMsg{Err: msg}),
+			m.componentGroup.FocusOn(m.errorPanelComponent),
+		)
+	default:
+		return m, m.componentGroup.UpdateAll(msg)
+	}
+}
+
+func (m MyWorkPageModel) View() string {
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Render(m.body())
+}
+
+func (m MyWorkPageModel) body() string {
+	switch m.state {
+	case utils.LoadingState:
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(fmt.Sprintf(
+				"%s Searching for your work",
+				m.componentGroup.GetComponent(m.spinnerComponent).View(),
+			))
+	case utils.ErrorState:
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(m.componentGroup.GetComponent(m.errorPanelComponent).View())
+	}
+
+	body := lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.chips(),
+		m.componentGroup.GetComponent(m.listComponent).View(),
+	)
+	if m.notice == "" {
+		return body
+	}
+	style := noticeStyle
+	if m.noticeIsError {
+		style = noticeErrorStyle
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lipgloss.NewStyle().MaxHeight(max(0, m.height-1)).Render(body),
+		style.Width(m.width).MaxHeight(1).Render(m.notice),
+	)
+}
+
+// chips renders how much is waiting and the keys on a single line.
+func (m MyWorkPageModel) chips() string {
+	waiting := len(m.work.ReviewRequested) + len(m.work.PullRequests) + len(m.work.AssignedIssues)
+	return lipgloss.NewStyle().
+		MaxWidth(m.width).
+		MaxHeight(1).
+		Render(lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			chipStyle.Render("my work"),
+			mutedStyle.Render(fmt.Sprintf(
+				"%d open · enter open · ] and [ next/previous section · o open in browser · r refresh",
+				waiting,
+			)),
+		))
+}
+
+// fetchWork searches for the user's work, showing the loading spinner unless
+// it is already shown.
+func (m *MyWorkPageModel) fetchWork() tea.Cmd {
+	ready := m.state == utils.ReadyState
+
+	cmds := make([]tea.Cmd, 0, 2)
+	if !ready {
+		cmds = append(cmds, utils.PageCmd(m.id, func() tea.Msg { return workLoadingMsg{} }))
+	}
+	cmds = append(cmds, utils.PageCmd(m.id, func() tea.Msg {
+		work, err := m.search.GetMyWork(context.Background())
+		if err != nil {
+			if ready {
+				return refreshFailedMsg{err: err}
+			}
+			return utils.NewErrorMsg(m.id, err)
+		}
+		return workReadyMsg{work: work}
+	}))
+	return tea.Sequence(cmds...)
+}
+
+func (m *MyWorkPageModel) setNotice(notice string, isError bool) {
+	m.notice = notice
+	m.noticeIsError = isError
+}
@@ -0,0 +1,168 @@
+package myworkpage
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+// A line of the list: the title of a section, or one of its items.
+type workRow struct {
+	section string
+	// nil for the title of the section.
+	item *gh.WorkItem
+	// Number of items of the section, on its title.
+	count int
+}
+
+func (m *MyWorkPageModel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	list := m.componentGroup.GetComponent(m.listComponent).(components.ListModel[workRow])
+	row, selected := list.GetSelectedItem()
+
+	switch msg.String() {
+	case "enter":
+		if !selected || row.item == nil {
+			return nil
+		}
+		item := *row.item
+		return func() tea.Msg {
+			return utils.OpenItemMsg{
+				Repo:        item.Repo,
+				Number:      item.Number,
+				Title:       item.Title,
+				PullRequest: item.PullRequest,
+			}
+		}
+	case "o":
+		if !selected || row.item == nil {
+			return nil
+		}
+		if err := utils.OpenInBrowser(row.item.URL); err != nil {
+			m.setNotice("Failed to open the browser: "+err.Error(), true)
+		}
+		return nil
+	case "]":
+		return m.jumpToSection(list, 1)
+	case "[":
+		return m.jumpToSection(list, -1)
+	case "r":
+		return m.fetchWork()
+	default:
+		return m.componentGroup.UpdateFocused(msg)
+	}
+}
+
+// jumpToSection moves the cursor to the title of the next section, or of the
+// previous one when direction is negative, wrapping around.
+func (m *MyWorkPageModel) jumpToSection(list components.ListModel[workRow], direction int) tea.Cmd {
+	rows := list.GetItems()
+	row, _ := list.GetSelectedItem()
+	titles := make([]int, 0, 4)
+	current := 0
+	for i, r := range rows {
+		if r.item == nil {
+			titles = append(titles, i)
+		}
+		if r.section == row.section && r.item == nil {
+			current = len(titles) - 1
+		}
+	}
+	if len(titles) == 0 {
+		return nil
+	}
+	// From an item, the previous section is the one it is in.
+	if direction < 0 && row.item != nil {
+		direction = 0
+	}
+	next := (current + direction + len(titles)) % len(titles)
+	return m.componentGroup.Update(m.listComponent, components.ListSetCursorMsg{Index: titles[next]})
+}
+
+// sectionRows lists the items of each section under its title.
+func sectionRows(work gh.MyWork) []workRow {
+	sections := []struct {
+		title string
+		items []gh.WorkItem
+	}{
+		{"Review requested", work.ReviewRequested},
+		{"My pull requests", work.PullRequests},
+		{"Assigned issues", work.AssignedIssues},
+		{"Mentions", work.Mentions},
+	}
+	rows := make([]workRow, 0)
+	for _, section := range sections {
+		rows = append(rows, workRow{section: section.title, count: len(section.items)})
+		for i := range section.items {
+			rows = append(rows, workRow{section: section.title, item: &section.items[i]})
+		}
+	}
+	return rows
+}
+
+// renderRow renders the title of a section with its count, or an item: its
+// kind, repository and number, title, state, author and last update. Pull
+// requests show their checks and review decision too.
+func renderRow(row workRow, width int) string {
+	if row.item == nil {
+		return sectionStyle.Render(row.section) + mutedStyle.Render(fmt.Sprintf(" · %d", row.count))
+	}
+
+	item := row.item
+	kind := "issue"
+	if item.PullRequest {
+		kind = "PR"
+	}
+	parts := []string{fmt.Sprintf(
+		"  %s %s %s",
+		mutedStyle.Render(fmt.Sprintf("%-5s", kind)),
+		repoStyle.Render(fmt.Sprintf("%s#%d", item.Repo, item.Number)),
+		item.Title,
+	)}
+	if item.State != "OPEN" {
+		parts = append(parts, mutedStyle.Render(strings.ToLower(item.State)))
+	}
+	if item.IsDraft {
+		parts = append(parts, mutedStyle.Render("draft"))
+	}
+	if item.PullRequest && item.State == "OPEN" {
+		if checks := describeChecks(item.CheckStatus); checks != "" {
+			parts = append(parts, checks)
+		}
+		if review := describeReview(item.ReviewDecision); review != "" {
+			parts = append(parts, review)
+		}
+	}
+	parts = append(parts, mutedStyle.Render(fmt.Sprintf("@%s · %s", item.Author, utils.Since(item.UpdatedAt))))
+	return strings.Join(parts, mutedStyle.Render(" · "))
+}
+
+func describeChecks(state string) string {
+	switch state {
+	case "SUCCESS":
+		return successStyle.Render("✓ checks")
+	case "FAILURE", "ERROR":
+		return failureStyle.Render("✗ checks")
+	case "PENDING", "EXPECTED":
+		return pendingStyle.Render("● checks")
+	default:
+		return ""
+	}
+}
+
+func describeReview(decision string) string {
+	switch decision {
+	case "APPROVED":
+		return successStyle.Render("approved")
+	case "CHANGES_REQUESTED":
+		return failureStyle.Render("changes requested")
+	case "REVIEW_REQUIRED":
+		return pendingStyle.Render("review required")
+	default:
+		return ""
+	}
+}
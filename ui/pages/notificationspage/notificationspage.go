@@ -0,0 +1,372 @@
+package notificationspage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+var (
+	chipStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("230")).
+			Background(lipgloss.Color("62")).
+			Padding(0, 1).
+			MarginRight(1)
+	repoStyle        = lipgloss.NewStyle().Bold(true)
+	unreadStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	mutedStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	noticeStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	noticeErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+type NotificationsPageModel struct {
+	id     string
+	width  int
+	height int
+
+	notifications gh.NotificationService
+	state         utils.ComponentState
+	// Whether read threads are listed too.
+	all     bool
+	threads []*github.Notification
+	// From the last poll, so the next one is only answered when something changed.
+	lastModified string
+	pollInterval time.Duration
+	// Increased whenever a poll is scheduled, so only the latest one runs.
+	poll int
+	// The action waiting for the prompt to be confirmed.
+	pending       *notificationAction
+	notice        string
+	noticeIsError bool
+
+	componentGroup      utils.ComponentGroup
+	spinnerComponent    string
+	errorPanelComponent string
+	listComponent       string
+	promptComponent     string
+}
+
+type notificationsLoadingMsg struct{}
+
+type notificationsReadyMsg struct {
+	all  bool
+	poll gh.NotificationPoll
+}
+
+// Sent after the poll interval GitHub asked for to check for new notifications.
+type pollMsg struct {
+	poll int
+}
+
+type pollFailedMsg struct {
+	err error
+}
+
+func NewNotificationsPage(id string, notifications gh.NotificationService, width int, height int) NotificationsPageModel {
+	spinner := components.NewSpinnerComponent()
+	errorPanel := components.NewErrorPanelComponent(width)
+	list := components.NewListComponent(width, height-1, renderRow)
+	prompt := components.NewPromptComponent(width)
+
+	return NotificationsPageModel{
+		id:            id,
+		width:         width,
+		height:        height,
+		notifications: notifications,
+		state:         utils.LoadingState,
+		componentGroup: utils.NewComponentGroup(
+			spinner,
+			errorPanel,
+			list,
+			prompt,
+		),
+		spinnerComponent:    spinner.ID(),
+		errorPanelComponent: errorPanel.ID(),
+		listComponent:       list.ID(),
+		promptComponent:     prompt.ID(),
+	}
+}
+
+func (m NotificationsPageModel) ID() string {
+	return m.id
+}
+
+// CapturesInput reports whether the prompt is taking input.
+func (m NotificationsPageModel) CapturesInput() bool {
+	return m.componentGroup.CapturesInput()
+}
+
+func (m NotificationsPageModel) Init() tea.Cmd {
+	return tea.Sequence(
+		m.fetchNotifications(),
+		m.componentGroup.Init(),
+	)
+}
+
+func (m NotificationsPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case utils.PageMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+		return m.Update(msg.Msg)
+	case utils.FocusMsg:
+		if m.id != msg.ID {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+		return m, m.fetchNotifications()
+	case utils.BlurMsg:
+		if m.id != msg.ID {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+		// Stop polling while the page is hidden.
+		m.poll++
+		return m, nil
+	case utils.UpdateSizeMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+
+		if msg.Width == 0 && msg.Height == 0 {
+			return m, nil
+		}
+
+		if msg.Width > 0 {
+			m.width = msg.Width
+		}
+		if msg.Height > 0 {
+			m.height = msg.Height
+		}
+
+		return m, tea.Batch(
+			m.componentGroup.Update(m.listComponent, utils.UpdateSizeMsg{
+				ID:     m.listComponent,
+				Width:  m.width,
+				Height: m.height - 1,
+			}),
+			m.componentGroup.Update(m.errorPanelComponent, utils.UpdateSizeMsg{
+				ID:    m.errorPanelComponent,
+				Width: m.width,
+			}),
+			m.componentGroup.Update(m.promptComponent, utils.UpdateSizeMsg{
+				ID:    m.promptComponent,
+				Width: m.width,
+			}),
+		)
+	case tea.KeyMsg:
+		m.notice = ""
+		if m.componentGroup.CapturesInput() {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+		if m.state == utils.ErrorState {
+			if msg.String() == "r" {
+				return m, m.fetchNotifications()
+			}
+			return m, nil
+		}
+		if m.state != utils.ReadyState {
+			return m, nil
+		}
+		return m, m.handleKey(msg)
+	case notificationsLoadingMsg:
+		m.state = utils.LoadingState
+		return m, m.componentGroup.FocusOn(m.spinnerComponent)
+	case notificationsReadyMsg:
+		if msg.all != m.all {
+			// Listed before read threads were shown or hidden.
+			return m, nil
+		}
+		m.pollInterval = msg.poll.PollInterval
+		m.lastModified = msg.poll.LastModified
+		cmds := []tea.Cmd{m.schedulePoll()}
+		if msg.poll.Modified {
+			m.threads = msg.poll.Threads
+			cmds = append(cmds, m.updateList())
+		}
+		if m.state != utils.ReadyState {
+			m.state = utils.ReadyState
+			cmds = append(cmds, m.componentGroup.FocusOn(m.listComponent))
+		}
+		return m, tea.Batch(cmds...)
+	case pollMsg:
+		if m.poll != msg.poll || m.state != utils.ReadyState {
+			return m, nil
+		}
+		return m, m.fetchNotifications()
+	case pollFailedMsg:
+		m.setNotice("Failed to check for notifications: "+msg.err.Error(), true)
+		return m, m.schedulePoll()
+	case components.PromptAnswerMsg:
+		if m.promptComponent != msg.ID || m.pending == nil {
+			return m, nil
+		}
+		action := *m.pending
+		m.pending = nil
+		focus := m.componentGroup.FocusOn(m.listComponent)
+		if msg.Value != "yes" {
+			return m, focus
+		}
+		return m, tea.Batch(focus, m.perform(action))
+	case notificationActionDoneMsg:
+		m.setNotice(msg.notice, false)
+		return m, nil
+	case notificationActionFailedMsg:
+		m.setNotice(fmt.Sprintf("Failed to %s: %s", msg.action, msg.err), true)
+		// Show the threads as GitHub has them again.
+		m.lastModified = ""
+		return m, m.fetchNotifications()
+	case utils.ErrorMsg:
+		if m.id != msg.Source {
+			return m, m.componentGroup.UpdateAll(msg)
+		}
+
+		m.state = utils.ErrorState
+		m.poll++
+		return m, tea.Batch(
+			m.componentGroup.Update(m.errorPanelComponent, components.ErrorPanelSetErrorMsg{Err: msg}),
+			m.componentGroup.FocusOn(m.errorPanelComponent),
+		)
+	default:
+		return m, m.componentGroup.UpdateAll(msg)
+	}
+}
+
+func (m NotificationsPageModel) View() string {
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Render(m.body())
+}
+
+func (m NotificationsPageModel) body() string {
+	switch m.state {
+	case utils.LoadingState:
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(fmt.Sprintf(
+				"%s Loading notifications",
+				m.componentGroup.GetComponent(m.spinnerComponent).View(),
+			))
+	case utils.ErrorState:
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(m.componentGroup.GetComponent(m.errorPanelComponent).View())
+	}
+
+	body := lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.chips(),
+		m.componentGroup.GetComponent(m.listComponent).View(),
+	)
+	if len(m.threads) == 0 {
+		empty := "No unread notifications."
+		if m.all {
+			empty = "No notifications."
+		}
+		body = lipgloss.JoinVertical(lipgloss.Left, m.chips(), mutedStyle.Render(empty))
+	}
+
+	var footer string
+	if m.componentGroup.IsFocused(m.promptComponent) {
+		footer = m.componentGroup.GetComponent(m.promptComponent).View()
+	} else if m.notice != "" {
+		style := noticeStyle
+		if m.noticeIsError {
+			style = noticeErrorStyle
+		}
+		footer = style.Width(m.width).MaxHeight(1).Render(m.notice)
+	}
+	if footer == "" {
+		return body
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lipgloss.NewStyle().MaxHeight(max(0, m.height-1)).Render(body),
+		footer,
+	)
+}
+
+// chips renders what is listed and the keys on a single line.
+func (m NotificationsPageModel) chips() string {
+	listed := "unread"
+	if m.all {
+		listed = "all"
+	}
+	unread := 0
+	for _, thread := range m.threads {
+		if thread.GetUnread() {
+			unread++
+		}
+	}
+	return lipgloss.NewStyle().
+		MaxWidth(m.width).
+		MaxHeight(1).
+		Render(lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			chipStyle.Render(listed),
+			mutedStyle.Render(fmt.Sprintf(
+				"%d unread · enter open · m read · d done · u unsubscribe · a all/unread · r refresh",
+				unread,
+			)),
+		))
+}
+
+// fetchNotifications polls the notifications, showing the loading spinner
+// unless they are already shown.
+func (m *NotificationsPageModel) fetchNotifications() tea.Cmd {
+	all := m.all
+	lastModified := m.lastModified
+	ready := m.state == utils.ReadyState
+
+	cmds := make([]tea.Cmd, 0, 2)
+	if !ready {
+		cmds = append(cmds, utils.PageCmd(m.id, func() tea.Msg { return notificationsLoadingMsg{} }))
+	}
+	cmds = append(cmds, utils.PageCmd(m.id, func() tea.Msg {
+		poll, err := m.notifications.ListNotifications(context.Background(), all, lastModified)
+		if err != nil {
+			if ready {
+				return pollFailedMsg{err: err}
+			}
+			return utils.NewErrorMsg(m.id, err)
+		}
+		return notificationsReadyMsg{all: all, poll: poll}
+	}))
+	return tea.Sequence(cmds...)
+}
+
+// schedulePoll checks for new notifications once the poll interval passed,
+// replacing the check scheduled before.
+func (m *NotificationsPageModel) schedulePoll() tea.Cmd {
+	m.poll++
+	poll := m.poll
+	return utils.PageCmd(m.id, tea.Tick(m.pollInterval, func(time.Time) tea.Msg {
+		return pollMsg{poll: poll}
+	}))
+}
+
+// updateList lists the threads grouped by repository.
+func (m *NotificationsPageModel) updateList() tea.Cmd {
+	return m.componentGroup.Update(m.listComponent, components.ListSetItemsMsg[notificationRow]{
+		Items: groupByRepo(m.threads),
+	})
+}
+
+func (m *NotificationsPageModel) setNotice(notice string, isError bool) {
+	m.notice = notice
+	m.noticeIsError = isError
+}
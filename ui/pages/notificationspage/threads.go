@@ -0,0 +1,279 @@
+package notificationspage
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+// A line of the list: the title of a repository's group, or one of its threads.
+type notificationRow struct {
+	repo string
+	// nil for the title of the group.
+	thread *github.Notification
+	// Threads of the group, on its title.
+	threads []*github.Notification
+}
+
+type notificationActionKind int
+
+const (
+	markReadAction notificationActionKind = iota
+	markDoneAction
+	unsubscribeAction
+)
+
+// notificationAction is a change to threads, confirmed first when it cannot
+// be undone from here.
+type notificationAction struct {
+	kind    notificationActionKind
+	threads []*github.Notification
+}
+
+type notificationActionDoneMsg struct {
+	notice string
+}
+
+type notificationActionFailedMsg struct {
+	action string
+	err    error
+}
+
+var subjectTypes = map[string]string{
+	"Issue":                        "issue",
+	"PullRequest":                  "PR",
+	"Release":                      "release",
+	"Discussion":                   "discussion",
+	"Commit":                       "commit",
+	"CheckSuite":                   "CI",
+	"RepositoryVulnerabilityAlert": "alert",
+}
+
+func (m *NotificationsPageModel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	row, selected := m.componentGroup.
+		GetComponent(m.listComponent).(components.ListModel[notificationRow]).
+		GetSelectedItem()
+	threads := row.threads
+	if row.thread != nil {
+		threads = []*github.Notification{row.thread}
+	}
+
+	switch msg.String() {
+	case "enter":
+		if !selected || row.thread == nil {
+			return nil
+		}
+		return m.open(row.thread)
+	case "m":
+		if !selected {
+			return nil
+		}
+		return m.perform(notificationAction{kind: markReadAction, threads: threads})
+	case "d":
+		if !selected {
+			return nil
+		}
+		if row.thread == nil {
+			m.pending = &notificationAction{kind: markDoneAction, threads: threads}
+			return m.showPrompt(fmt.Sprintf("Mark the %d notifications of %s as done?", len(threads), row.repo))
+		}
+		return m.perform(notificationAction{kind: markDoneAction, threads: threads})
+	case "u":
+		if !selected || row.thread == nil {
+			return nil
+		}
+		m.pending = &notificationAction{kind: unsubscribeAction, threads: threads}
+		return m.showPrompt(fmt.Sprintf("Unsubscribe from %q?", row.thread.GetSubject().GetTitle()))
+	case "a":
+		m.all = !m.all
+		m.lastModified = ""
+		return tea.Batch(
+			m.componentGroup.Update(m.listComponent, components.ListResetViewportMsg{}),
+			m.fetchNotifications(),
+		)
+	case "r":
+		return m.fetchNotifications()
+	default:
+		return m.componentGroup.UpdateFocused(msg)
+	}
+}
+
+// open shows the issue or pull request of thread in its page, marking the
+// thread as read.
+func (m *NotificationsPageModel) open(thread *github.Notification) tea.Cmd {
+	subject := thread.GetSubject()
+	kind := subject.GetType()
+	if kind != "Issue" && kind != "PullRequest" {
+		m.setNotice(fmt.Sprintf("Only issues and pull requests can be opened, this is a %s", describeType(kind)), true)
+		return nil
+	}
+	repo, err := gh.ParseRepo(thread.GetRepository().GetFullName())
+	if err != nil {
+		m.setNotice(err.Error(), true)
+		return nil
+	}
+	// The subject URL is the API URL of the issue or pull request, ending in its number.
+	number, err := strconv.Atoi(path.Base(subject.GetURL()))
+	if err != nil {
+		m.setNotice("GitHub did not say which issue or pull request this is", true)
+		return nil
+	}
+
+	open := func() tea.Msg {
+		return utils.OpenItemMsg{
+			Repo:        repo,
+			Number:      number,
+			Title:       subject.GetTitle(),
+			PullRequest: kind == "PullRequest",
+		}
+	}
+	if !thread.GetUnread() {
+		return open
+	}
+	return tea.Batch(open, m.perform(notificationAction{kind: markReadAction, threads: []*github.Notification{thread}}))
+}
+
+// perform applies action to the threads shown right away and sends it to
+// GitHub. A failure lists the threads as GitHub has them again.
+func (m *NotificationsPageModel) perform(action notificationAction) tea.Cmd {
+	changed := map[string]bool{}
+	for _, thread := range action.threads {
+		changed[thread.GetID()] = true
+	}
+	threads := make([]*github.Notification, 0, len(m.threads))
+	for _, thread := range m.threads {
+		if changed[thread.GetID()] {
+			if action.kind == markDoneAction {
+				continue
+			}
+			if action.kind == markReadAction {
+				read := *thread
+				read.Unread = github.Ptr(false)
+				thread = &read
+			}
+		}
+		threads = append(threads, thread)
+	}
+	m.threads = threads
+
+	service := m.notifications
+	return tea.Batch(
+		m.updateList(),
+		utils.PageCmd(m.id, func() tea.Msg {
+			ctx := context.Background()
+			for _, thread := range action.threads {
+				var err error
+				switch action.kind {
+				case markReadAction:
+					if !thread.GetUnread() {
+						continue
+					}
+					err = service.MarkThreadRead(ctx, thread.GetID())
+				case markDoneAction:
+					err = service.MarkThreadDone(ctx, thread.GetID())
+				case unsubscribeAction:
+					err = service.Unsubscribe(ctx, thread.GetID())
+				}
+				if err != nil {
+					return notificationActionFailedMsg{action: describeAction(action), err: err}
+				}
+			}
+			if action.kind == unsubscribeAction {
+				return notificationActionDoneMsg{notice: "Unsubscribed from " + action.threads[0].GetSubject().GetTitle()}
+			}
+			return nil
+		}),
+	)
+}
+
+func (m *NotificationsPageModel) showPrompt(question string) tea.Cmd {
+	return tea.Sequence(
+		m.componentGroup.Update(m.promptComponent, components.PromptResetMsg{
+			Question: question,
+			Options:  components.ConfirmOptions,
+		}),
+		m.componentGroup.FocusOn(m.promptComponent),
+	)
+}
+
+func describeAction(action notificationAction) string {
+	switch action.kind {
+	case markReadAction:
+		return "mark as read"
+	case markDoneAction:
+		return "mark as done"
+	default:
+		return "unsubscribe"
+	}
+}
+
+func describeType(kind string) string {
+	if label, ok := subjectTypes[kind]; ok {
+		return label
+	}
+	return strings.ToLower(kind)
+}
+
+// groupByRepo lists threads under the title of their repository, the
+// repository updated most recently first.
+func groupByRepo(threads []*github.Notification) []notificationRow {
+	groups := map[string][]*github.Notification{}
+	order := make([]string, 0)
+	// Threads come most recently updated first, so the groups do too.
+	for _, thread := range threads {
+		repo := thread.GetRepository().GetFullName()
+		if _, ok := groups[repo]; !ok {
+			order = append(order, repo)
+		}
+		groups[repo] = append(groups[repo], thread)
+	}
+
+	rows := make([]notificationRow, 0, len(threads)+len(order))
+	for _, repo := range order {
+		rows = append(rows, notificationRow{repo: repo, threads: groups[repo]})
+		for _, thread := range groups[repo] {
+			rows = append(rows, notificationRow{repo: repo, thread: thread})
+		}
+	}
+	return rows
+}
+
+// renderRow renders the title of a repository with its unread count, or a
+// thread: whether it is unread, its type, title, reason and last update.
+func renderRow(row notificationRow, width int) string {
+	if row.thread == nil {
+		unread := 0
+		for _, thread := range row.threads {
+			if thread.GetUnread() {
+				unread++
+			}
+		}
+		return repoStyle.Render(row.repo) + mutedStyle.Render(fmt.Sprintf(" · %d unread", unread))
+	}
+
+	thread := row.thread
+	marker := "  "
+	if thread.GetUnread() {
+		marker = unreadStyle.Render("● ")
+	}
+	line := fmt.Sprintf(
+		"  %s%s %s",
+		marker,
+		mutedStyle.Render(fmt.Sprintf("%-10s", describeType(thread.GetSubject().GetType()))),
+		thread.GetSubject().GetTitle(),
+	)
+	return line + mutedStyle.Render(fmt.Sprintf(
+		" · %s · %s",
+		strings.ReplaceAll(thread.GetReason(), "_", " "),
+		utils.Since(thread.GetUpdatedAt().Time),
+	))
+}
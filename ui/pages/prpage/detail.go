@@ -0,0 +1,341 @@
+package prpage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+const detailTimeFormat = "Jan 2, 2006 15:04"
+
+// pullRequestDetail is a pull request with its reviews and the checks of its head commit.
+type pullRequestDetail struct {
+	repo    gh.Repo
+	number  int
+	pull    *github.PullRequest
+	reviews []*github.PullRequestReview
+	checks  gh.CommitChecks
+	// What the pull request needs before it can be merged, nil once it is
+	// closed or when they failed to load with requirementsErr.
+	requirements    *gh.MergeRequirements
+	requirementsErr error
+
+	// Title shown until the pull request is loaded, and why it failed to.
+	title string
+	err   error
+}
+
+type pullRequestDetailReadyMsg struct {
+	detail pullRequestDetail
+}
+
+type pullRequestDetailFailedMsg struct {
+	repo   gh.Repo
+	number int
+	err    error
+}
+
+// fetchDetail loads the pull request, its reviews, the checks of its head
+// commit and, while it is open, its merge requirements. The detail is shown
+// without the requirements when only they fail to load.
+func (m *PullRequestsPageModel) fetchDetail(repo gh.Repo, number int) tea.Cmd {
+	return utils.PageCmd(m.id, func() tea.Msg {
+		ctx := context.Background()
+		pull, err := m.pulls.GetPullRequest(ctx, repo, number)
+		if err != nil {
+			return pullRequestDetailFailedMsg{repo: repo, number: number, err: err}
+		}
+		reviews, err := m.pulls.ListReviews(ctx, repo, number)
+		if err != nil {
+			return pullRequestDetailFailedMsg{repo: repo, number: number, err: err}
+		}
+		checks, err := m.pulls.GetChecks(ctx, repo, pull.GetHead().GetSHA())
+		if err != nil {
+			return pullRequestDetailFailedMsg{repo: repo, number: number, err: err}
+		}
+
+		detail := pullRequestDetail{
+			repo:    repo,
+			number:  number,
+			pull:    pull,
+			reviews: reviews,
+			checks:  checks,
+			title:   pull.GetTitle(),
+		}
+		if pull.GetState() == "open" {
+			requirements, err := m.pulls.GetMergeRequirements(ctx, repo, number)
+			if err != nil {
+				detail.requirementsErr = err
+			} else {
+				detail.requirements = &requirements
+			}
+		}
+		return pullRequestDetailReadyMsg{detail: detail}
+	})
+}
+
+// renderPullRequestDetail renders the pull request's metadata, reviewers,
+// description and checks as markdown.
+func renderPullRequestDetail(detail pullRequestDetail) string {
+	pull := detail.pull
+	doc := strings.Builder{}
+
+	if pull == nil {
+		fmt.Fprintf(&doc, "# %s #%d\n\n", detail.title, detail.number)
+		if detail.err != nil {
+			fmt.Fprintf(&doc, "**Failed to load the pull request:** %s\n\n*Press r to retry.*\n", detail.err)
+		} else {
+			doc.WriteString("*Loading…*")
+		}
+		return doc.String()
+	}
+
+	fmt.Fprintf(&doc, "# %s #%d\n\n", pull.GetTitle(), pull.GetNumber())
+	if detail.err != nil {
+		fmt.Fprintf(&doc, "**Failed to refresh the pull request:** %s · *press r to retry*\n\n", detail.err)
+	}
+
+	state := "Open"
+	switch {
+	case pull.GetMerged():
+		state = "Merged"
+	case pull.GetState() == "closed":
+		state = "Closed"
+	case pull.GetDraft():
+		state = "Draft"
+	}
+	fmt.Fprintf(
+		&doc,
+		"**%s** · @%s wants to merge %d commits into `%s` from `%s` · opened on %s\n\n",
+		state,
+		pull.GetUser().GetLogin(),
+		pull.GetCommits(),
+		pull.GetBase().GetRef(),
+		pull.GetHead().GetLabel(),
+		pull.GetCreatedAt().Format(detailTimeFormat),
+	)
+	fmt.Fprintf(
+		&doc,
+		"**+%d −%d** in %d files · %d comments\n\n",
+		pull.GetAdditions(),
+		pull.GetDeletions(),
+		pull.GetChangedFiles(),
+		pull.GetComments()+pull.GetReviewComments(),
+	)
+	if merge := describeMergeability(pull); merge != "" {
+		fmt.Fprintf(&doc, "**Merge:** %s\n\n", merge)
+	}
+	if detail.requirements != nil {
+		doc.WriteString(renderMergeRequirements(*detail.requirements))
+	} else if detail.requirementsErr != nil {
+		fmt.Fprintf(&doc, "*Merge requirements unavailable: %s*\n\n", detail.requirementsErr)
+	} else if pull.GetMerged() && pull.GetHead().GetRepo().GetFullName() == pull.GetBase().GetRepo().GetFullName() {
+		doc.WriteString("*D delete branch*\n\n")
+	}
+
+	if len(pull.Labels) > 0 {
+		labels := make([]string, len(pull.Labels))
+		for i, label := range pull.Labels {
+			labels[i] = "`" + label.GetName() + "`"
+		}
+		fmt.Fprintf(&doc, "**Labels:** %s\n\n", strings.Join(labels, " "))
+	}
+	if reviewers := describeReviewers(pull, detail.reviews); len(reviewers) > 0 {
+		fmt.Fprintf(&doc, "**Reviewers:** %s\n\n", strings.Join(reviewers, ", "))
+	}
+
+	doc.WriteString("---\n\n")
+	if body := pull.GetBody(); body != "" {
+		doc.WriteString(body + "\n\n")
+	} else {
+		doc.WriteString("*No description provided.*\n\n")
+	}
+
+	doc.WriteString("---\n\n## Checks\n\n")
+	statuses := detail.checks.Status.Statuses
+	runs := detail.checks.CheckRuns
+	if len(statuses) == 0 && len(runs) == 0 {
+		doc.WriteString("*No checks reported on the head commit.*\n")
+		return doc.String()
+	}
+	fmt.Fprintf(&doc, "%s\n\n", summarizeChecks(detail.checks))
+	for _, status := range statuses {
+		fmt.Fprintf(&doc, "- %s **%s** %s\n", statusIcon(status.GetState()), status.GetContext(), status.GetDescription())
+	}
+	for _, run := range runs {
+		result := run.GetConclusion()
+		if run.GetStatus() != "completed" {
+			result = run.GetStatus()
+		}
+		fmt.Fprintf(&doc, "- %s **%s** %s\n", statusIcon(result), run.GetName(), strings.ReplaceAll(result, "_", " "))
+	}
+	return doc.String()
+}
+
+// describeMergeability returns why the pull request can or cannot be merged,
+// empty once it is closed.
+func describeMergeability(pull *github.PullRequest) string {
+	if pull.GetState() != "open" {
+		return ""
+	}
+	if pull.Mergeable == nil {
+		return "checking…"
+	}
+	switch pull.GetMergeableState() {
+	case "clean":
+		return "ready to merge"
+	case "dirty":
+		return "has conflicts that must be resolved"
+	case "blocked":
+		return "blocked by branch protection"
+	case "behind":
+		return "head branch is out of date with the base branch"
+	case "unstable":
+		return "mergeable with failing checks"
+	case "draft":
+		return "draft pull requests cannot be merged"
+	case "has_hooks":
+		return "mergeable, pre-receive hooks will run"
+	default:
+		if pull.GetMergeable() {
+			return "mergeable"
+		}
+		return "not mergeable"
+	}
+}
+
+// describeReviewers returns each reviewer's latest review state, followed by
+// the users and teams whose review is still requested.
+func describeReviewers(pull *github.PullRequest, reviews []*github.PullRequestReview) []string {
+	latest := map[string]string{}
+	order := make([]string, 0)
+	for _, review := range reviews {
+		login := review.GetUser().GetLogin()
+		state := review.GetState()
+		if state == "PENDING" {
+			// The viewer's review, not submitted yet.
+			continue
+		}
+		if state == "COMMENTED" && latest[login] != "" {
+			// A comment does not replace an approval or change request.
+			continue
+		}
+		if _, seen := latest[login]; !seen {
+			order = append(order, login)
+		}
+		latest[login] = state
+	}
+
+	reviewers := make([]string, 0, len(order))
+	for _, login := range order {
+		if login == pull.GetUser().GetLogin() {
+			continue
+		}
+		reviewers = append(reviewers, fmt.Sprintf("@%s %s", login, strings.ToLower(strings.ReplaceAll(latest[login], "_", " "))))
+	}
+	for _, user := range pull.RequestedReviewers {
+		reviewers = append(reviewers, fmt.Sprintf("@%s requested", user.GetLogin()))
+	}
+	for _, team := range pull.RequestedTeams {
+		reviewers = append(reviewers, fmt.Sprintf("@%s requested", team.GetSlug()))
+	}
+	return reviewers
+}
+
+// summarizeChecks counts the statuses and check runs by outcome.
+func summarizeChecks(checks gh.CommitChecks) string {
+	var passed, failed, pending, skipped int
+	count := func(state string) {
+		switch state {
+		case "success", "neutral":
+			passed++
+		case "failure", "error", "timed_out", "action_required", "cancelled", "startup_failure":
+			failed++
+		case "skipped", "stale":
+			skipped++
+		default:
+			pending++
+		}
+	}
+	for _, status := range checks.Status.Statuses {
+		count(status.GetState())
+	}
+	for _, run := range checks.CheckRuns {
+		if run.GetStatus() != "completed" {
+			pending++
+			continue
+		}
+		count(run.GetConclusion())
+	}
+
+	parts := []string{fmt.Sprintf("%d passed", passed)}
+	if failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", failed))
+	}
+	if pending > 0 {
+		parts = append(parts, fmt.Sprintf("%d pending", pending))
+	}
+	if skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", skipped))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// statusIcon returns an icon for the state of a status or check run.
+func statusIcon(state string) string {
+	switch state {
+	case "success", "neutral":
+		return "✓"
+	case "failure", "error", "timed_out", "action_required", "cancelled", "startup_failure":
+		return "✗"
+	case "skipped", "stale":
+		return "–"
+	default:
+		return "●"
+	}
+}
+
+type pullRequestFilesReadyMsg struct {
+	repo   gh.Repo
+	number int
+	files  []*github.CommitFile
+	err    error
+}
+
+// A patch for the diff viewer, of a file of the pull request number of repo.
+type pullRequestPatchReadyMsg struct {
+	repo   gh.Repo
+	number int
+	patch  components.DiffViewerSetPatchMsg
+}
+
+// fetchFiles loads the files changed by the pull request for the diff viewer.
+// A failure is shown in place of the diff, not as the page's error.
+func (m *PullRequestsPageModel) fetchFiles(repo gh.Repo, number int) tea.Cmd {
+	return utils.PageCmd(m.id, func() tea.Msg {
+		files, err := m.pulls.ListFiles(context.Background(), repo, number)
+		return pullRequestFilesReadyMsg{repo: repo, number: number, files: files, err: err}
+	})
+}
+
+// fetchPatch loads the patch GitHub left out of a file's listing from the
+// pull request's whole diff.
+func (m *PullRequestsPageModel) fetchPatch(repo gh.Repo, number int, filename string) tea.Cmd {
+	return utils.PageCmd(m.id, func() tea.Msg {
+		msg := pullRequestPatchReadyMsg{repo: repo, number: number}
+		diff, err := m.pulls.GetDiff(context.Background(), repo, number)
+		if err != nil {
+			msg.patch = components.DiffViewerSetPatchMsg{Filename: filename, Err: err}
+		} else {
+			msg.patch = components.DiffViewerSetPatchMsg{Filename: filename, Patch: gh.FilePatch(diff, filename)}
+		}
+		return msg
+	})
+}
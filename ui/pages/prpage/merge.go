@@ -0,0 +1,324 @@
+package prpage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+type mergeStep int
+
+const (
+	chooseMergeStep mergeStep = iota
+	chooseAutoMergeStep
+	confirmMergeStep
+	confirmUpdateStep
+	confirmDeleteStep
+)
+
+// mergeTask is the merge, auto-merge, branch update or deletion being
+// prepared through the prompt.
+type mergeTask struct {
+	step   mergeStep
+	repo   gh.Repo
+	number int
+	// Requirements of the pull request, nil once it is closed.
+	requirements *gh.MergeRequirements
+	base         string
+	// Head branch, deleted by confirmDeleteStep.
+	branch string
+	method string
+	// Component to focus again once the prompt is answered.
+	returnFocus string
+}
+
+// Sent once a merge, branch update or deletion succeeded.
+type mergeActionDoneMsg struct {
+	number int
+	notice string
+	// Set after merging, to offer deleting the head branch.
+	merged *mergeTask
+}
+
+type mergeActionFailedMsg struct {
+	number int
+	action string
+	err    error
+}
+
+var mergeMethodLabels = map[string]string{
+	"merge":  "merge commit",
+	"squash": "squash",
+	"rebase": "rebase",
+}
+
+var mergeMethodKeys = map[string]string{
+	"merge":  "m",
+	"squash": "s",
+	"rebase": "r",
+}
+
+// mergeBlockers returns the requirements the pull request does not meet yet.
+func mergeBlockers(requirements gh.MergeRequirements) []string {
+	blockers := make([]string, 0)
+	switch requirements.MergeStateStatus {
+	case "DIRTY":
+		blockers = append(blockers, "The head branch has conflicts with the base branch")
+	case "DRAFT":
+		blockers = append(blockers, "Draft pull requests cannot be merged")
+	case "BEHIND":
+		blockers = append(blockers, "The head branch must be up to date with the base branch, press u to update it")
+	}
+	for _, check := range requirements.FailingChecks {
+		blockers = append(blockers, fmt.Sprintf("Required check `%s` failed", check))
+	}
+	for _, check := range requirements.PendingChecks {
+		blockers = append(blockers, fmt.Sprintf("Required check `%s` has not finished", check))
+	}
+	switch requirements.ReviewDecision {
+	case "REVIEW_REQUIRED":
+		if requirements.RequiredApprovals > 1 {
+			blockers = append(blockers, fmt.Sprintf("%d approving reviews are required", requirements.RequiredApprovals))
+		} else {
+			blockers = append(blockers, "An approving review is required")
+		}
+	case "CHANGES_REQUESTED":
+		blockers = append(blockers, "Changes were requested")
+	}
+	if requirements.RequiresConversationResolution && requirements.UnresolvedThreads > 0 {
+		blockers = append(blockers, fmt.Sprintf("%d conversations must be resolved", requirements.UnresolvedThreads))
+	}
+	if len(blockers) == 0 && requirements.MergeStateStatus == "BLOCKED" {
+		blockers = append(blockers, "Blocked by branch protection")
+	}
+	return blockers
+}
+
+// renderMergeRequirements lists the requirements the pull request does not
+// meet yet and the merge keys, as markdown.
+func renderMergeRequirements(requirements gh.MergeRequirements) string {
+	doc := strings.Builder{}
+	if requirements.AutoMergeMethod != "" {
+		fmt.Fprintf(&doc, "**Auto-merge:** enabled, will %s once the requirements are met\n\n", mergeMethodLabels[requirements.AutoMergeMethod])
+	}
+	if requirements.MergeStateStatus == "UNKNOWN" {
+		doc.WriteString("*GitHub is still checking whether this pull request can be merged.*\n\n")
+		return doc.String()
+	}
+	blockers := mergeBlockers(requirements)
+	for _, blocker := range blockers {
+		fmt.Fprintf(&doc, "- ✗ %s\n", blocker)
+	}
+	if len(blockers) > 0 {
+		doc.WriteString("\n")
+	}
+	doc.WriteString("*m merge · u update branch*\n\n")
+	return doc.String()
+}
+
+// startMerge asks how to merge the pull request shown, or whether to enable
+// or disable auto-merge while it cannot be merged yet.
+func (m *PullRequestsPageModel) startMerge() tea.Cmd {
+	detail := m.detail
+	if detail.pull.GetState() != "open" {
+		m.setNotice(fmt.Sprintf("#%d is not open", detail.number), true)
+		return nil
+	}
+	requirements := detail.requirements
+
+	options := make([]components.PromptOption, 0)
+	blockers := mergeBlockers(*requirements)
+	if len(blockers) == 0 && requirements.MergeStateStatus != "UNKNOWN" {
+		for _, method := range requirements.MergeMethods {
+			options = append(options, components.PromptOption{
+				Key:   mergeMethodKeys[method],
+				Label: mergeMethodLabels[method],
+				Value: method,
+			})
+		}
+	}
+	switch {
+	case requirements.AutoMergeMethod != "":
+		options = append(options, components.PromptOption{Key: "x", Label: "disable auto-merge", Value: "disable-auto"})
+	case requirements.AutoMergeAllowed && len(blockers) > 0:
+		options = append(options, components.PromptOption{Key: "a", Label: "auto-merge when ready", Value: "auto"})
+	}
+	if len(options) == 0 {
+		notice := "Cannot merge yet"
+		if len(blockers) > 0 {
+			notice = "Cannot merge: " + blockers[0]
+		}
+		m.setNotice(notice, true)
+		return nil
+	}
+
+	m.mergeTask = &mergeTask{
+		step:         chooseMergeStep,
+		repo:         detail.repo,
+		number:       detail.number,
+		requirements: requirements,
+		base:         detail.pull.GetBase().GetRef(),
+		branch:       requirements.HeadRef,
+		returnFocus:  m.componentGroup.GetFocusedComponentName(),
+	}
+	return m.showPrompt(fmt.Sprintf("Merge #%d into %s with", detail.number, m.mergeTask.base), options)
+}
+
+// startUpdateBranch asks to merge the base branch into the head branch.
+func (m *PullRequestsPageModel) startUpdateBranch() tea.Cmd {
+	detail := m.detail
+	if detail.pull.GetState() != "open" {
+		m.setNotice(fmt.Sprintf("#%d is not open", detail.number), true)
+		return nil
+	}
+	m.mergeTask = &mergeTask{
+		step:         confirmUpdateStep,
+		repo:         detail.repo,
+		number:       detail.number,
+		requirements: detail.requirements,
+		base:         detail.pull.GetBase().GetRef(),
+		returnFocus:  m.componentGroup.GetFocusedComponentName(),
+	}
+	return m.showPrompt(
+		fmt.Sprintf("Update %s with the changes of %s?", detail.requirements.HeadRef, m.mergeTask.base),
+		components.ConfirmOptions,
+	)
+}
+
+// startDeleteBranch asks to delete the head branch of a closed pull request.
+func (m *PullRequestsPageModel) startDeleteBranch() tea.Cmd {
+	pull := m.detail.pull
+	switch {
+	case pull.GetState() == "open":
+		m.setNotice("Close or merge the pull request before deleting its branch", true)
+		return nil
+	case pull.GetHead().GetRepo().GetFullName() != pull.GetBase().GetRepo().GetFullName():
+		m.setNotice("The head branch is in a fork", true)
+		return nil
+	}
+	m.mergeTask = &mergeTask{
+		step:        confirmDeleteStep,
+		repo:        m.detail.repo,
+		number:      m.detail.number,
+		branch:      pull.GetHead().GetRef(),
+		returnFocus: m.componentGroup.GetFocusedComponentName(),
+	}
+	return m.showPrompt(fmt.Sprintf("Delete the branch %s?", m.mergeTask.branch), components.ConfirmOptions)
+}
+
+func (m *PullRequestsPageModel) showPrompt(question string, options []components.PromptOption) tea.Cmd {
+	return tea.Sequence(
+		m.componentGroup.Update(m.promptComponent, components.PromptResetMsg{
+			Question: question,
+			Options:  options,
+		}),
+		m.componentGroup.FocusOn(m.promptComponent),
+	)
+}
+
+// handleMergeAnswer moves the merge task to its next step, or performs it.
+func (m *PullRequestsPageModel) handleMergeAnswer(value string) tea.Cmd {
+	task := m.mergeTask
+	if value == "" || value == "no" {
+		m.mergeTask = nil
+		return m.componentGroup.FocusOn(task.returnFocus)
+	}
+
+	switch task.step {
+	case chooseMergeStep:
+		switch value {
+		case "auto":
+			task.step = chooseAutoMergeStep
+			options := make([]components.PromptOption, 0, len(task.requirements.MergeMethods))
+			for _, method := range task.requirements.MergeMethods {
+				options = append(options, components.PromptOption{
+					Key:   mergeMethodKeys[method],
+					Label: mergeMethodLabels[method],
+					Value: method,
+				})
+			}
+			return m.showPrompt(fmt.Sprintf("Once ready, merge #%d with", task.number), options)
+		case "disable-auto":
+			return m.performMerge(task, "disable auto-merge", "Auto-merge disabled", func(ctx context.Context) error {
+				return m.pulls.SetAutoMerge(ctx, task.requirements.PullRequestID, "")
+			})
+		}
+		task.method = value
+		task.step = confirmMergeStep
+		return m.showPrompt(
+			fmt.Sprintf("Merge #%d into %s with a %s?", task.number, task.base, mergeMethodLabels[value]),
+			components.ConfirmOptions,
+		)
+	case chooseAutoMergeStep:
+		return m.performMerge(task, "enable auto-merge", "Auto-merge enabled", func(ctx context.Context) error {
+			return m.pulls.SetAutoMerge(ctx, task.requirements.PullRequestID, value)
+		})
+	case confirmMergeStep:
+		repo := task.repo
+		m.mergeTask = nil
+		m.setNotice(fmt.Sprintf("Merging #%d…", task.number), false)
+		return tea.Batch(
+			m.componentGroup.FocusOn(task.returnFocus),
+			utils.PageCmd(m.id, func() tea.Msg {
+				err := m.pulls.Merge(context.Background(), repo, task.number, task.method, task.requirements.HeadSHA)
+				if err != nil {
+					return mergeActionFailedMsg{number: task.number, action: "merge", err: err}
+				}
+				return mergeActionDoneMsg{number: task.number, notice: fmt.Sprintf("Merged #%d", task.number), merged: task}
+			}),
+		)
+	case confirmUpdateStep:
+		repo := task.repo
+		return m.performMerge(task, "update the branch", "Branch update started", func(ctx context.Context) error {
+			return m.pulls.UpdateBranch(ctx, repo, task.number, task.requirements.HeadSHA)
+		})
+	default:
+		repo := task.repo
+		return m.performMerge(task, "delete the branch", fmt.Sprintf("Deleted %s", task.branch), func(ctx context.Context) error {
+			return m.pulls.DeleteBranch(ctx, repo, task.branch)
+		})
+	}
+}
+
+// performMerge ends the merge task by running action.
+func (m *PullRequestsPageModel) performMerge(task *mergeTask, action string, notice string, run func(context.Context) error) tea.Cmd {
+	m.mergeTask = nil
+	return tea.Batch(
+		m.componentGroup.FocusOn(task.returnFocus),
+		utils.PageCmd(m.id, func() tea.Msg {
+			if err := run(context.Background()); err != nil {
+				return mergeActionFailedMsg{number: task.number, action: action, err: err}
+			}
+			return mergeActionDoneMsg{number: task.number, notice: notice}
+		}),
+	)
+}
+
+// handleMergeActionDone shows the pull request as it is now, offering to
+// delete the head branch of a merged pull request unless GitHub does.
+func (m *PullRequestsPageModel) handleMergeActionDone(msg mergeActionDoneMsg) tea.Cmd {
+	m.setNotice(msg.notice, false)
+	cmds := []tea.Cmd{m.fetchPullRequests()}
+	if m.detail != nil && m.detail.number == msg.number {
+		cmds = append(cmds, m.fetchDetail(m.detail.repo, msg.number))
+	}
+
+	merged := msg.merged
+	if merged != nil && !merged.requirements.IsCrossRepository && !merged.requirements.DeleteBranchOnMerge && m.mergeTask == nil {
+		m.mergeTask = &mergeTask{
+			step:        confirmDeleteStep,
+			repo:        merged.repo,
+			number:      merged.number,
+			branch:      merged.branch,
+			returnFocus: merged.returnFocus,
+		}
+		cmds = append(cmds, m.showPrompt(fmt.Sprintf("Merged #%d. Delete the branch %s?", merged.number, merged.branch), components.ConfirmOptions))
+	}
+	return tea.Batch(cmds...)
+}
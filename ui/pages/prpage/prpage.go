@@ -0,0 +1,667 @@
+package prpage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+var pullRequestStates = []string{"open", "closed", "merged"}
+
+var (
+	chipStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("230")).
+			Background(lipgloss.Color("62")).
+			Padding(0, 1).
+			MarginRight(1)
+	successStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	failureStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	pendingStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	mutedStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	noticeStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	noticeErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+type PullRequestsPageModel struct {
+	id     string
+	width  int
+	height int
+
+	repo  gh.Repo
+	pulls gh.PullRequestService
+	state utils.ComponentState
+	// One of `pullRequestStates`.
+	filterState     string
+	reviewRequested bool
+	// Cursors of the pages before the current one, the first page's is empty.
+	pageCursors []string
+	// Cursor of the current page and of the next one, empty on the last page.
+	currentCursor string
+	nextCursor    string
+	total         int
+	detail        *pullRequestDetail
+	// Repository and number of the pull request whose files are shown, 0 when
+	// none are.
+	diffRepo    gh.Repo
+	diffNumber  int
+	diffLoading bool
+	// Why the files of the pull request could not be listed.
+	diffErr error
+	// Component to focus again once the diff is closed.
+	diffReturnFocus string
+	// Review threads of the pull request whose files are shown.
+	threads []gh.ReviewThread
+	// The viewer's pending reviews, by pull request.
+	pendingReviews map[pullRequestKey]gh.PendingReview
+	// What the editor or prompt is open for.
+	task          *reviewTask
+	mergeTask     *mergeTask
+	notice        string
+	noticeIsError bool
+
+	componentGroup          utils.ComponentGroup
+	spinnerComponent        string
+	listComponent           string
+	markdownViewerComponent string
+	errorPanelComponent     string
+	diffViewerComponent     string
+	promptComponent         string
+}
+
+type pullRequestsLoadingMsg struct{}
+
+type pullRequestsReadyMsg struct {
+	page gh.PullRequestPage
+}
+
+func NewPullRequestsPage(id string, pulls gh.PullRequestService, repo gh.Repo, width int, height int) PullRequestsPageModel {
+	spinner := components.NewSpinnerComponent()
+	list := components.NewListComponent(width, height-1, renderPullRequest)
+	markdownViewer := components.NewMarkdownViewerComponent(
+		width/2,
+		height,
+		lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder()).
+			BorderForeground(lipgloss.Color("62")).
+			UnsetBorderTop().
+			UnsetBorderRight().
+			UnsetBorderBottom().
+			PaddingRight(2),
+	)
+	errorPanel := components.NewErrorPanelComponent(width)
+	diffViewer := components.NewDiffViewerComponent(width, height-1)
+	prompt := components.NewPromptComponent(width)
+
+	return PullRequestsPageModel{
+		id:             id,
+		width:          width,
+		height:         height,
+		repo:           repo,
+		pulls:          pulls,
+		state:          utils.LoadingState,
+		filterState:    "open",
+		pendingReviews: map[pullRequestKey]gh.PendingReview{},
+		componentGroup: utils.NewComponentGroup(
+			spinner,
+			list,
+			markdownViewer,
+			errorPanel,
+			diffViewer,
+			prompt,
+		),
+		spinnerComponent:        spinner.ID(),
+		listComponent:           list.ID(),
+		markdownViewerComponent: markdownViewer.ID(),
+		errorPanelComponent:     errorPanel.ID(),
+		diffViewerComponent:     diffViewer.ID(),
+		promptComponent:         prompt.ID(),
+	}
+}
+
+func (m PullRequestsPageModel) ID() string {
+	return m.id
+}
+
+// CapturesInput reports whether the diff viewer, which uses tab, or the
+// prompt is focused.
+func (m PullRequestsPageModel) CapturesInput() bool {
+	return m.componentGroup.CapturesInput()
+}
+
+func (m PullRequestsPageModel) Init() tea.Cmd {
+	return tea.Sequence(
+		m.fetchPullRequests(),
+		m.componentGroup.Init(),
+	)
+}
+
+func (m PullRequestsPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case utils.PageMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+		return m.Update(msg.Msg)
+	case utils.FocusMsg:
+		if m.id != msg.ID {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+		return m, m.fetchPullRequests()
+	case utils.BlurMsg:
+		if m.id != msg.ID {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+		m.diffNumber = 0
+		return m, m.closeDetail()
+	case utils.UpdateSizeMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+
+		if msg.Width == 0 && msg.Height == 0 {
+			return m, nil
+		}
+
+		if msg.Width > 0 {
+			m.width = msg.Width
+		}
+		if msg.Height > 0 {
+			m.height = msg.Height
+		}
+
+		return m, tea.Batch(
+			m.componentGroup.Update(m.listComponent, utils.UpdateSizeMsg{
+				ID:     m.listComponent,
+				Width:  m.listWidth(),
+				Height: m.height - 1,
+			}),
+			m.componentGroup.Update(m.markdownViewerComponent, utils.UpdateSizeMsg{
+				ID:     m.markdownViewerComponent,
+				Width:  m.width - m.width/2,
+				Height: m.height,
+			}),
+			m.componentGroup.Update(m.errorPanelComponent, utils.UpdateSizeMsg{
+				ID:    m.errorPanelComponent,
+				Width: m.width,
+			}),
+			m.componentGroup.Update(m.diffViewerComponent, utils.UpdateSizeMsg{
+				ID:     m.diffViewerComponent,
+				Width:  m.width,
+				Height: m.height - 1,
+			}),
+			m.componentGroup.Update(m.promptComponent, utils.UpdateSizeMsg{
+				ID:    m.promptComponent,
+				Width: m.width,
+			}),
+		)
+	case tea.KeyMsg:
+		m.notice = ""
+		if m.componentGroup.IsFocused(m.promptComponent) {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+
+		browsing := m.state == utils.ReadyState && m.componentGroup.IsFocused(m.listComponent)
+		switch k := msg.String(); {
+		case k == "r" && m.detail != nil && m.detail.err != nil && m.componentGroup.IsFocused(m.markdownViewerComponent):
+			m.detail.err = nil
+			return m, tea.Sequence(
+				m.componentGroup.Update(m.markdownViewerComponent, components.MarkdownViewerSetContentMsg{
+					Content: renderPullRequestDetail(*m.detail),
+				}),
+				m.fetchDetail(m.detail.repo, m.detail.number),
+			)
+		case k == "r" && m.state == utils.ErrorState:
+			return m, m.fetchPullRequests()
+		case k == "enter" && browsing:
+			pull, ok := m.getSelectedPullRequest()
+			if !ok {
+				return m, nil
+			}
+			return m, m.openDetail(m.repo, pull.Number, pull.Title)
+		case k == "esc" && m.componentGroup.IsFocused(m.diffViewerComponent):
+			m.diffNumber = 0
+			m.threads = nil
+			return m, m.componentGroup.FocusOn(m.diffReturnFocus)
+		case k == "r" && m.componentGroup.IsFocused(m.diffViewerComponent) && m.diffErr != nil:
+			m.diffLoading = true
+			m.diffErr = nil
+			return m, m.fetchFiles(m.diffRepo, m.diffNumber)
+		case k == "R" && m.componentGroup.IsFocused(m.diffViewerComponent) && !m.diffLoading && m.diffErr == nil:
+			return m, m.startSubmit()
+		case k == "esc" && m.componentGroup.IsFocused(m.markdownViewerComponent):
+			return m, m.closeDetail()
+		case k == "m" && m.showingDetail() && m.detail.requirements != nil:
+			return m, m.startMerge()
+		case k == "u" && m.showingDetail() && m.detail.requirements != nil:
+			return m, m.startUpdateBranch()
+		case k == "D" && m.showingDetail():
+			return m, m.startDeleteBranch()
+		case k == "d" && m.state == utils.ReadyState &&
+			(m.componentGroup.IsFocused(m.listComponent) || m.componentGroup.IsFocused(m.markdownViewerComponent)):
+			repo, number := m.repo, 0
+			if m.detail != nil && m.componentGroup.IsFocused(m.markdownViewerComponent) {
+				repo, number = m.detail.repo, m.detail.number
+			} else if pull, ok := m.getSelectedPullRequest(); ok {
+				number = pull.Number
+			}
+			if number == 0 {
+				return m, nil
+			}
+			m.diffRepo = repo
+			m.diffNumber = number
+			m.diffLoading = true
+			m.diffErr = nil
+			m.threads = nil
+			m.diffReturnFocus = m.componentGroup.GetFocusedComponentName()
+			return m, tea.Sequence(
+				m.componentGroup.Update(m.diffViewerComponent, components.DiffViewerSetFilesMsg{}),
+				m.componentGroup.FocusOn(m.diffViewerComponent),
+				tea.Batch(m.fetchFiles(repo, number), m.fetchThreads(m.diffPull())),
+			)
+		case k == "s" && browsing:
+			for i, state := range pullRequestStates {
+				if state == m.filterState {
+					m.filterState = pullRequestStates[(i+1)%len(pullRequestStates)]
+					break
+				}
+			}
+			return m, m.refetchFromStart()
+		case k == "v" && browsing:
+			m.reviewRequested = !m.reviewRequested
+			return m, m.refetchFromStart()
+		case k == "]" && browsing && m.nextCursor != "":
+			m.pageCursors = append(m.pageCursors, m.currentCursor)
+			m.currentCursor = m.nextCursor
+			return m, tea.Batch(
+				m.componentGroup.Update(m.listComponent, components.ListResetViewportMsg{}),
+				m.fetchPullRequests(),
+			)
+		case k == "[" && browsing && len(m.pageCursors) > 0:
+			m.currentCursor = m.pageCursors[len(m.pageCursors)-1]
+			m.pageCursors = m.pageCursors[:len(m.pageCursors)-1]
+			return m, tea.Batch(
+				m.componentGroup.Update(m.listComponent, components.ListResetViewportMsg{}),
+				m.fetchPullRequests(),
+			)
+		default:
+			if m.state == utils.LoadingState {
+				return m, nil
+			}
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+	case pullRequestsReadyMsg:
+		m.nextCursor = msg.page.EndCursor
+		m.total = msg.page.Total
+		cmds := []tea.Cmd{
+			m.componentGroup.Update(m.listComponent, components.ListSetItemsMsg[gh.PullRequestSummary]{
+				Items: msg.page.PullRequests,
+			}),
+		}
+		if m.state != utils.ReadyState {
+			m.state = utils.ReadyState
+			if m.detail != nil {
+				// A pull request was opened from another page while the list loaded.
+				cmds = append(cmds, m.componentGroup.FocusOn(m.markdownViewerComponent))
+			} else {
+				cmds = append(cmds, m.componentGroup.FocusOn(m.listComponent))
+			}
+		}
+		return m, tea.Batch(cmds...)
+	case utils.OpenItemMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+		m.diffNumber = 0
+		return m, m.openDetail(msg.Repo, msg.Number, msg.Title)
+	case pullRequestDetailReadyMsg:
+		if m.detail == nil || m.detail.repo != msg.detail.repo || m.detail.number != msg.detail.number {
+			// The user moved on before the detail arrived.
+			return m, nil
+		}
+
+		m.detail = &msg.detail
+		return m, m.componentGroup.Update(m.markdownViewerComponent, components.MarkdownViewerSetContentMsg{
+			Content: renderPullRequestDetail(*m.detail),
+		})
+	case pullRequestDetailFailedMsg:
+		if m.detail == nil || m.detail.repo != msg.repo || m.detail.number != msg.number {
+			return m, nil
+		}
+
+		m.detail.err = msg.err
+		return m, m.componentGroup.Update(m.markdownViewerComponent, components.MarkdownViewerSetContentMsg{
+			Content: renderPullRequestDetail(*m.detail),
+		})
+	case pullRequestFilesReadyMsg:
+		if m.diffNumber != msg.number || m.diffRepo != msg.repo {
+			return m, nil
+		}
+		m.diffLoading = false
+		if msg.err != nil {
+			m.diffErr = msg.err
+			return m, nil
+		}
+		return m, tea.Sequence(
+			m.componentGroup.Update(m.diffViewerComponent, components.DiffViewerSetFilesMsg{
+				Files:       msg.files,
+				Commentable: true,
+			}),
+			m.updateAnnotations(),
+		)
+	case pullRequestPatchReadyMsg:
+		if m.diffNumber != msg.number || m.diffRepo != msg.repo {
+			// The patch is of a file of another pull request.
+			return m, nil
+		}
+		return m, m.componentGroup.Update(m.diffViewerComponent, msg.patch)
+	case components.DiffViewerLoadPatchMsg:
+		if m.diffViewerComponent != msg.ID || m.diffNumber == 0 {
+			return m, nil
+		}
+		return m, m.fetchPatch(m.diffRepo, m.diffNumber, msg.File.GetFilename())
+	case components.DiffViewerCommentMsg:
+		if m.diffViewerComponent != msg.ID || m.diffNumber == 0 {
+			return m, nil
+		}
+		return m, m.startComment(msg)
+	case components.DiffViewerAnnotationKeyMsg:
+		if m.diffViewerComponent != msg.ID || m.diffNumber == 0 {
+			return m, nil
+		}
+		return m, m.handleAnnotationKey(msg)
+	case components.PromptAnswerMsg:
+		if m.promptComponent != msg.ID {
+			return m, nil
+		}
+		if m.mergeTask != nil {
+			return m, m.handleMergeAnswer(msg.Value)
+		}
+		if m.task == nil {
+			return m, nil
+		}
+		return m, m.handleReviewEvent(msg.Value)
+	case components.ComposerDoneMsg:
+		if m.id != msg.ID || m.task == nil {
+			return m, nil
+		}
+		return m, m.handleComposed(msg)
+	case reviewThreadsReadyMsg:
+		if m.diffPull() != msg.pull {
+			return m, nil
+		}
+		m.threads = msg.threads
+		m.pendingReviews[msg.pull] = msg.pending
+		return m, m.updateAnnotations()
+	case mergeActionDoneMsg:
+		return m, m.handleMergeActionDone(msg)
+	case mergeActionFailedMsg:
+		m.setNotice(fmt.Sprintf("Failed to %s #%d: %s", msg.action, msg.number, msg.err), true)
+		return m, nil
+	case reviewActionDoneMsg:
+		return m, m.handleReviewActionDone(msg)
+	case reviewActionFailedMsg:
+		if m.diffPull() == msg.pull {
+			m.setNotice(fmt.Sprintf("Failed to %s: %s", msg.action, msg.err), true)
+		}
+		return m, nil
+	case pullRequestsLoadingMsg:
+		m.state = utils.LoadingState
+		return m, m.componentGroup.FocusOn(m.spinnerComponent)
+	case utils.ErrorMsg:
+		if m.id != msg.Source {
+			return m, m.componentGroup.UpdateAll(msg)
+		}
+
+		m.state = utils.ErrorState
+		m.detail = nil
+		m.diffNumber = 0
+		return m, tea.Batch(
+			m.componentGroup.Update(m.errorPanelComponent, components.ErrorPanelSetErrorMsg{Err: msg}),
+			m.componentGroup.FocusOn(m.errorPanelComponent),
+		)
+	default:
+		return m, m.componentGroup.UpdateAll(msg)
+	}
+}
+
+func (m PullRequestsPageModel) View() string {
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Render(m.body())
+}
+
+func (m PullRequestsPageModel) body() string {
+	switch m.state {
+	case utils.LoadingState:
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(fmt.Sprintf(
+				"%s Loading Pull Requests from %s",
+				m.componentGroup.GetComponent(m.spinnerComponent).View(),
+				m.repo,
+			))
+	case utils.ReadyState:
+		if m.diffNumber != 0 {
+			if m.diffLoading {
+				return fmt.Sprintf(
+					"%s Loading the files of #%d",
+					m.componentGroup.GetComponent(m.spinnerComponent).View(),
+					m.diffNumber,
+				)
+			}
+			if m.diffErr != nil {
+				return lipgloss.JoinVertical(
+					lipgloss.Left,
+					lipgloss.NewStyle().Width(m.width).Height(max(0, m.height-1)).Render(fmt.Sprintf(
+						"Failed to load the files of #%d: %s. Press r to try again, esc to go back.",
+						m.diffNumber,
+						m.diffErr,
+					)),
+					m.diffFooter(),
+				)
+			}
+			return lipgloss.JoinVertical(
+				lipgloss.Left,
+				m.componentGroup.GetComponent(m.diffViewerComponent).View(),
+				m.diffFooter(),
+			)
+		}
+
+		body := lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.chips(),
+			m.componentGroup.GetComponent(m.listComponent).View(),
+		)
+		if m.detail != nil {
+			body = lipgloss.JoinHorizontal(
+				lipgloss.Top,
+				body,
+				m.componentGroup.GetComponent(m.markdownViewerComponent).View(),
+			)
+		}
+		if !m.componentGroup.IsFocused(m.promptComponent) && m.notice == "" {
+			return body
+		}
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			lipgloss.NewStyle().MaxHeight(max(0, m.height-1)).Render(body),
+			m.diffFooter(),
+		)
+	case utils.ErrorState:
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(m.componentGroup.GetComponent(m.errorPanelComponent).View())
+	default:
+		return ""
+	}
+}
+
+// diffFooter renders the prompt, the last notice or, below the diff, the state
+// of the pending review.
+func (m PullRequestsPageModel) diffFooter() string {
+	switch {
+	case m.componentGroup.IsFocused(m.promptComponent):
+		return m.componentGroup.GetComponent(m.promptComponent).View()
+	case m.notice != "":
+		style := noticeStyle
+		if m.noticeIsError {
+			style = noticeErrorStyle
+		}
+		return style.Width(m.width).MaxHeight(1).Render(m.notice)
+	default:
+		return mutedStyle.Width(m.width).MaxHeight(1).Render(m.reviewStatus())
+	}
+}
+
+// chips renders the active filters and the page shown on a single line.
+func (m PullRequestsPageModel) chips() string {
+	chips := []string{chipStyle.Render("is:" + m.filterState)}
+	if m.reviewRequested {
+		chips = append(chips, chipStyle.Render("review-requested:@me"))
+	}
+	chips = append(chips, mutedStyle.Render(fmt.Sprintf(
+		"page %d · %d total · s state · v review requested · d diff · [ ] pages",
+		len(m.pageCursors)+1,
+		m.total,
+	)))
+	return lipgloss.NewStyle().
+		MaxWidth(m.listWidth()).
+		MaxHeight(1).
+		Render(lipgloss.JoinHorizontal(lipgloss.Top, chips...))
+}
+
+// showingDetail reports whether the loaded detail of a pull request has focus.
+func (m PullRequestsPageModel) showingDetail() bool {
+	return m.state == utils.ReadyState &&
+		m.componentGroup.IsFocused(m.markdownViewerComponent) &&
+		m.detail != nil &&
+		m.detail.pull != nil
+}
+
+func (m PullRequestsPageModel) listWidth() int {
+	if m.detail != nil {
+		return m.width / 2
+	}
+	return m.width
+}
+
+// openDetail shows the pull request number of repo next to the list.
+func (m *PullRequestsPageModel) openDetail(repo gh.Repo, number int, title string) tea.Cmd {
+	m.detail = &pullRequestDetail{repo: repo, number: number, title: title}
+	cmds := []tea.Cmd{
+		m.componentGroup.Update(m.listComponent, utils.UpdateSizeMsg{
+			ID:    m.listComponent,
+			Width: m.listWidth(),
+		}),
+		m.componentGroup.Update(m.markdownViewerComponent, components.MarkdownViewerSetContentMsg{
+			Content: renderPullRequestDetail(*m.detail),
+		}),
+	}
+	if m.state == utils.ReadyState {
+		cmds = append(cmds, m.componentGroup.FocusOn(m.markdownViewerComponent))
+	}
+	cmds = append(cmds, m.fetchDetail(repo, number))
+	return tea.Sequence(cmds...)
+}
+
+func (m *PullRequestsPageModel) closeDetail() tea.Cmd {
+	m.detail = nil
+	return tea.Sequence(
+		m.componentGroup.Update(m.listComponent, utils.UpdateSizeMsg{
+			ID:    m.listComponent,
+			Width: m.width,
+		}),
+		m.componentGroup.FocusOn(m.listComponent),
+	)
+}
+
+// refetchFromStart lists the first page after the filters changed.
+func (m *PullRequestsPageModel) refetchFromStart() tea.Cmd {
+	m.pageCursors = nil
+	m.currentCursor = ""
+	return tea.Batch(
+		m.componentGroup.Update(m.listComponent, components.ListResetViewportMsg{}),
+		m.fetchPullRequests(),
+	)
+}
+
+// fetchPullRequests lists the current page, showing the loading spinner unless
+// pull requests are already shown.
+func (m *PullRequestsPageModel) fetchPullRequests() tea.Cmd {
+	search := gh.PullRequestSearch{
+		State:           m.filterState,
+		ReviewRequested: m.reviewRequested,
+		After:           m.currentCursor,
+		PerPage:         50,
+	}
+
+	cmds := make([]tea.Cmd, 0, 2)
+	if m.state != utils.ReadyState {
+		cmds = append(cmds, utils.PageCmd(m.id, func() tea.Msg { return pullRequestsLoadingMsg{} }))
+	}
+	cmds = append(cmds, utils.PageCmd(m.id, func() tea.Msg {
+		page, err := m.pulls.ListPullRequests(context.Background(), m.repo, search)
+		if err != nil {
+			return utils.NewErrorMsg(m.id, err)
+		}
+		return pullRequestsReadyMsg{page: page}
+	}))
+	return tea.Sequence(cmds...)
+}
+
+func (m PullRequestsPageModel) getSelectedPullRequest() (gh.PullRequestSummary, bool) {
+	return m.componentGroup.
+		GetComponent(m.listComponent).(components.ListModel[gh.PullRequestSummary]).
+		GetSelectedItem()
+}
+
+// renderPullRequest renders a pull request as a line of the list: its checks,
+// number, title, author and review and merge state.
+func renderPullRequest(pull gh.PullRequestSummary, width int) string {
+	parts := []string{fmt.Sprintf("%s #%d %s", checkIcon(pull.CheckStatus), pull.Number, pull.Title)}
+	parts = append(parts, "@"+pull.Author)
+	if pull.IsDraft {
+		parts = append(parts, "draft")
+	}
+	switch pull.State {
+	case "MERGED":
+		parts = append(parts, "merged")
+	case "CLOSED":
+		parts = append(parts, "closed")
+	}
+	if decision := pull.ReviewDecision; decision != "" {
+		parts = append(parts, strings.ToLower(strings.ReplaceAll(decision, "_", " ")))
+	}
+	if pull.State == "OPEN" && pull.Mergeable == "CONFLICTING" {
+		parts = append(parts, "conflicts")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// checkIcon returns an icon for the combined state of a commit's checks.
+func checkIcon(state string) string {
+	switch strings.ToUpper(state) {
+	case "SUCCESS":
+		return successStyle.Render("✓")
+	case "FAILURE", "ERROR":
+		return failureStyle.Render("✗")
+	case "PENDING", "EXPECTED":
+		return pendingStyle.Render("●")
+	default:
+		return " "
+	}
+}
@@ -0,0 +1,166 @@
+package prpage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/gh/ghtest"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/ui/uitest"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+var testRepo = gh.Repo{Owner: "owner", Name: "repo"}
+
+func page(model tea.Model) PullRequestsPageModel {
+	return model.(PullRequestsPageModel)
+}
+
+// newTestPage starts the page on pulls with an open pull request, once listed.
+func newTestPage(t *testing.T, pulls *ghtest.PullRequestService) *uitest.Program {
+	t.Helper()
+	pulls.AddPullRequest(testRepo, &github.PullRequest{
+		Title: github.Ptr("Add a feature"),
+		Head:  &github.PullRequestBranch{Ref: github.Ptr("feature"), SHA: github.Ptr("abc123")},
+		Base:  &github.PullRequestBranch{Ref: github.Ptr("main")},
+	})
+	p := uitest.Start(t, NewPullRequestsPage("pulls", pulls, testRepo, 120, 40))
+	p.WaitFor("the pull requests", func(model tea.Model) bool {
+		_, ok := page(model).getSelectedPullRequest()
+		return page(model).state == utils.ReadyState && ok
+	})
+	return p
+}
+
+func TestPullRequestsPageShowsDetailWithoutMergeRequirements(t *testing.T) {
+	pulls := &ghtest.PullRequestService{}
+	pulls.FailWith("GetMergeRequirements", errors.New("502 Bad Gateway"))
+	p := newTestPage(t, pulls)
+
+	p.Type("enter")
+	p.WaitFor("the detail", func(model tea.Model) bool {
+		detail := page(model).detail
+		return detail != nil && detail.pull != nil
+	})
+	p.Type("m")
+	p.Inspect(func(model tea.Model) {
+		m := page(model)
+		if m.detail.requirementsErr == nil || m.detail.requirements != nil {
+			t.Errorf("detail requirements %v, error %v, want only the error", m.detail.requirements, m.detail.requirementsErr)
+		}
+		if m.mergeTask != nil {
+			t.Error("m started a merge without the merge requirements")
+		}
+		if m.state != utils.ReadyState {
+			t.Errorf("page state %v, want the list to stay shown", m.state)
+		}
+	})
+}
+
+func TestPullRequestsPageRetriesDetailInDetailPane(t *testing.T) {
+	pulls := &ghtest.PullRequestService{}
+	pulls.FailWith("ListReviews", errors.New("502 Bad Gateway"))
+	p := newTestPage(t, pulls)
+
+	p.Type("enter")
+	p.WaitFor("the detail error", func(model tea.Model) bool {
+		detail := page(model).detail
+		return detail != nil && detail.err != nil
+	})
+	p.Inspect(func(model tea.Model) {
+		if state := page(model).state; state != utils.ReadyState {
+			t.Errorf("page state %v, want the list to stay shown", state)
+		}
+	})
+
+	pulls.FailWith("ListReviews", nil)
+	lists := len(pulls.Calls("ListPullRequests"))
+	p.Type("r")
+	p.WaitFor("the detail", func(model tea.Model) bool {
+		detail := page(model).detail
+		return detail != nil && detail.pull != nil && detail.err == nil
+	})
+	if got := len(pulls.Calls("ListPullRequests")); got != lists {
+		t.Errorf("retrying the detail listed the pull requests %d more times", got-lists)
+	}
+}
+
+func TestPullRequestsPageRetriesFilesInDiffPane(t *testing.T) {
+	pulls := &ghtest.PullRequestService{}
+	pulls.FailWith("ListFiles", errors.New("502 Bad Gateway"))
+	p := newTestPage(t, pulls)
+
+	p.Type("d")
+	p.WaitFor("the files error", func(model tea.Model) bool {
+		return page(model).diffErr != nil
+	})
+	p.Inspect(func(model tea.Model) {
+		m := page(model)
+		if m.state != utils.ReadyState || m.diffNumber != 1 {
+			t.Errorf("page state %v showing the diff of #%d, want the diff of #1 to stay shown", m.state, m.diffNumber)
+		}
+	})
+
+	pulls.FailWith("ListFiles", nil)
+	p.Type("r")
+	p.WaitFor("the files", func(model tea.Model) bool {
+		return !page(model).diffLoading && page(model).diffErr == nil
+	})
+}
+
+// useEditor makes the editor write text, followed by a newline.
+func useEditor(t *testing.T, text string) {
+	t.Helper()
+	editor := filepath.Join(t.TempDir(), "editor")
+	script := "#!/bin/sh\nprintf '%s\\n' '" + text + "' > \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", editor)
+}
+
+func TestPullRequestsPageAddsCommentsToPendingReview(t *testing.T) {
+	pulls := &ghtest.PullRequestService{}
+	item := ghtest.Item{Repo: testRepo, Number: 1}
+	pulls.Files = map[ghtest.Item][]*github.CommitFile{item: {{
+		Filename: github.Ptr("main.go"),
+		Status:   github.Ptr("modified"),
+		Patch:    github.Ptr("@@ -1,1 +1,1 @@\n-old\n+new"),
+	}}}
+	useEditor(t, "Why?")
+	p := newTestPage(t, pulls)
+
+	p.Type("d")
+	var viewer string
+	p.WaitFor("the diff", func(model tea.Model) bool {
+		viewer = page(model).diffViewerComponent
+		return !page(model).diffLoading
+	})
+	p.Send(components.DiffViewerCommentMsg{ID: viewer, Path: "main.go", Side: "LEFT", Line: 1})
+	p.WaitFor("the pending comment", func(model tea.Model) bool {
+		return len(page(model).pendingReviews[pullRequestKey{testRepo, 1}].Comments) == 1
+	})
+	if calls := pulls.Calls("StartReview"); len(calls) != 1 {
+		t.Errorf("started %d reviews, want 1", len(calls))
+	}
+	if pending := pulls.PendingReviews[item]; len(pending.Comments) != 1 || pending.Comments[0].Body != "Why?" {
+		t.Errorf("pending review %+v, want the comment added to it", pending)
+	}
+
+	p.Type("R", "c")
+	p.WaitFor("the review", func(model tea.Model) bool {
+		return len(page(model).threads) == 1
+	})
+	if _, ok := pulls.PendingReviews[item]; ok {
+		t.Error("the pending review is still pending once submitted")
+	}
+	if reviews := pulls.Reviews[item]; len(reviews) != 1 || reviews[0].GetState() != "COMMENTED" {
+		t.Errorf("reviews %v, want the pending review submitted as a comment", reviews)
+	}
+}
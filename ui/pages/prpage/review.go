@@ -0,0 +1,428 @@
+package prpage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+var (
+	threadHeaderStyle   = lipgloss.NewStyle().Bold(true)
+	threadAuthorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	pendingCommentStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+)
+
+// Prefix of the IDs of the annotations showing pending comments, followed by
+// the comment's ID.
+const pendingAnnotationPrefix = "pending:"
+
+type reviewTaskKind int
+
+const (
+	commentTask reviewTaskKind = iota
+	replyTask
+	submitTask
+)
+
+// A pull request of a repository, as pending reviews and review threads
+// belong to.
+type pullRequestKey struct {
+	repo   gh.Repo
+	number int
+}
+
+// reviewTask is what the text being written in the editor is for.
+type reviewTask struct {
+	kind reviewTaskKind
+	pull pullRequestKey
+	// The pending comment written, with its position, and its ID when it is
+	// edited.
+	comment gh.ReviewComment
+	// Thread replied to.
+	thread gh.ReviewThread
+	// Event of the review submitted.
+	event string
+}
+
+type reviewThreadsReadyMsg struct {
+	pull    pullRequestKey
+	threads []gh.ReviewThread
+	pending gh.PendingReview
+}
+
+// Sent once a pending comment, reply, resolution or review was sent.
+type reviewActionDoneMsg struct {
+	pull      pullRequestKey
+	notice    string
+	submitted bool
+}
+
+type reviewActionFailedMsg struct {
+	pull   pullRequestKey
+	action string
+	err    error
+}
+
+var reviewEventOptions = []components.PromptOption{
+	{Key: "c", Label: "comment", Value: "COMMENT"},
+	{Key: "a", Label: "approve", Value: "APPROVE"},
+	{Key: "r", Label: "request changes", Value: "REQUEST_CHANGES"},
+}
+
+// fetchThreads loads the review threads of a pull request and the viewer's
+// pending review of it.
+func (m *PullRequestsPageModel) fetchThreads(pull pullRequestKey) tea.Cmd {
+	return utils.PageCmd(m.id, func() tea.Msg {
+		ctx := context.Background()
+		threads, err := m.pulls.ListReviewThreads(ctx, pull.repo, pull.number)
+		if err != nil {
+			return reviewActionFailedMsg{pull: pull, action: "load review threads", err: err}
+		}
+		pending, err := m.pulls.GetPendingReview(ctx, pull.repo, pull.number)
+		if err != nil {
+			return reviewActionFailedMsg{pull: pull, action: "load your pending review", err: err}
+		}
+		return reviewThreadsReadyMsg{pull: pull, threads: threads, pending: pending}
+	})
+}
+
+// diffPull returns the pull request whose files are shown.
+func (m PullRequestsPageModel) diffPull() pullRequestKey {
+	return pullRequestKey{repo: m.diffRepo, number: m.diffNumber}
+}
+
+// updateAnnotations shows the review threads and pending comments in the diff.
+func (m *PullRequestsPageModel) updateAnnotations() tea.Cmd {
+	annotations := make([]components.DiffAnnotation, 0, len(m.threads))
+	for _, thread := range m.threads {
+		annotations = append(annotations, threadAnnotation(thread))
+	}
+	for _, comment := range m.pendingReviews[m.diffPull()].Comments {
+		annotations = append(annotations, pendingAnnotation(comment))
+	}
+	return m.componentGroup.Update(m.diffViewerComponent, components.DiffViewerSetAnnotationsMsg{
+		Annotations: annotations,
+	})
+}
+
+// threadAnnotation shows a thread's comments under its line, only its header
+// once it is resolved.
+func threadAnnotation(thread gh.ReviewThread) components.DiffAnnotation {
+	header := []string{fmt.Sprintf("💬 %d comments", len(thread.Comments))}
+	if thread.IsResolved {
+		header = append(header, "resolved")
+	}
+	if thread.IsOutdated {
+		header = append(header, "outdated")
+	}
+	resolve := "x resolve"
+	if thread.IsResolved {
+		resolve = "x unresolve"
+	}
+	lines := []string{
+		threadHeaderStyle.Render(strings.Join(header, " · ")) + mutedStyle.Render(" · r reply · "+resolve),
+	}
+	if !thread.IsResolved {
+		for _, comment := range thread.Comments {
+			lines = append(lines, threadAuthorStyle.Render("@"+comment.Author)+mutedStyle.Render(" · "+comment.CreatedAt.Format(detailTimeFormat)))
+			lines = append(lines, strings.Split(strings.TrimSpace(comment.Body), "\n")...)
+		}
+	}
+
+	line := thread.Line
+	if thread.IsOutdated {
+		line = 0
+	}
+	return components.DiffAnnotation{
+		ID:    thread.ID,
+		Path:  thread.Path,
+		Side:  thread.Side,
+		Line:  line,
+		Lines: lines,
+	}
+}
+
+func pendingAnnotation(comment gh.ReviewComment) components.DiffAnnotation {
+	lines := []string{
+		pendingCommentStyle.Render("✎ Pending comment on "+describeLines(comment)) + mutedStyle.Render(" · e edit · d delete"),
+	}
+	lines = append(lines, strings.Split(strings.TrimSpace(comment.Body), "\n")...)
+	return components.DiffAnnotation{
+		ID:    pendingAnnotationPrefix + comment.ID,
+		Path:  comment.Path,
+		Side:  comment.Side,
+		Line:  comment.Line,
+		Lines: lines,
+	}
+}
+
+// describeLines returns the line or range of lines a comment is on.
+func describeLines(comment gh.ReviewComment) string {
+	lines := fmt.Sprintf("line %d", comment.Line)
+	if comment.StartLine != 0 {
+		lines = fmt.Sprintf("lines %d–%d", comment.StartLine, comment.Line)
+	}
+	if comment.Side == "LEFT" {
+		lines += " of the base"
+	}
+	return lines
+}
+
+// startComment writes a comment on the lines selected in the diff, starting
+// from a suggestion block of their code if asked.
+func (m *PullRequestsPageModel) startComment(msg components.DiffViewerCommentMsg) tea.Cmd {
+	if msg.Suggestion && (msg.Side != "RIGHT" || (msg.StartLine != 0 && msg.StartSide != "RIGHT")) {
+		m.setNotice("Changes can only be suggested on lines of the head branch", true)
+		return nil
+	}
+
+	comment := gh.ReviewComment{
+		Path:      msg.Path,
+		Side:      msg.Side,
+		Line:      msg.Line,
+		StartSide: msg.StartSide,
+		StartLine: msg.StartLine,
+	}
+	body := ""
+	if msg.Suggestion {
+		body = "```suggestion\n" + strings.Join(msg.Code, "\n") + "\n```\n"
+	}
+	m.task = &reviewTask{kind: commentTask, pull: m.diffPull(), comment: comment}
+	return m.compose(body)
+}
+
+// handleAnnotationKey replies to or resolves a thread, or edits or deletes a
+// pending comment.
+func (m *PullRequestsPageModel) handleAnnotationKey(msg components.DiffViewerAnnotationKeyMsg) tea.Cmd {
+	if id, ok := strings.CutPrefix(msg.AnnotationID, pendingAnnotationPrefix); ok {
+		var comment gh.ReviewComment
+		for _, c := range m.pendingReviews[m.diffPull()].Comments {
+			if c.ID == id {
+				comment = c
+			}
+		}
+		if comment.ID == "" {
+			return nil
+		}
+		switch msg.Key {
+		case "e":
+			m.task = &reviewTask{kind: commentTask, pull: m.diffPull(), comment: comment}
+			return m.compose(comment.Body)
+		case "d":
+			return m.deletePendingComment(m.diffPull(), comment)
+		}
+		return nil
+	}
+
+	var thread gh.ReviewThread
+	for _, t := range m.threads {
+		if t.ID == msg.AnnotationID {
+			thread = t
+		}
+	}
+	if thread.ID == "" {
+		return nil
+	}
+	switch msg.Key {
+	case "r":
+		if len(thread.Comments) == 0 {
+			return nil
+		}
+		m.task = &reviewTask{kind: replyTask, pull: m.diffPull(), thread: thread}
+		return m.compose("")
+	case "x":
+		return m.resolveThread(m.diffPull(), thread)
+	}
+	return nil
+}
+
+// startSubmit asks how to submit the pending review.
+func (m *PullRequestsPageModel) startSubmit() tea.Cmd {
+	m.task = &reviewTask{kind: submitTask, pull: m.diffPull()}
+	return m.showPrompt(
+		fmt.Sprintf("Submit your review of #%d with %d comments as", m.diffNumber, len(m.pendingReviews[m.diffPull()].Comments)),
+		reviewEventOptions,
+	)
+}
+
+// handleReviewEvent writes the summary of the review once its event is chosen.
+func (m *PullRequestsPageModel) handleReviewEvent(event string) tea.Cmd {
+	focus := m.componentGroup.FocusOn(m.diffViewerComponent)
+	if event == "" {
+		m.task = nil
+		return focus
+	}
+	m.task.event = event
+	return tea.Sequence(focus, m.compose(""))
+}
+
+// compose opens the editor on body with what the text is for below it.
+func (m *PullRequestsPageModel) compose(body string) tea.Cmd {
+	task := m.task
+	var instructions, context string
+	switch task.kind {
+	case commentTask:
+		instructions = fmt.Sprintf("Write your comment on %s of %s above, in markdown.", describeLines(task.comment), task.comment.Path) +
+			"\nIt is added to your pending review, submitted with R."
+	case replyTask:
+		instructions = fmt.Sprintf("Write your reply on %s above, in markdown.", task.thread.Path)
+		comments := make([]string, len(task.thread.Comments))
+		for i, comment := range task.thread.Comments {
+			comments[i] = fmt.Sprintf("@%s:\n%s", comment.Author, comment.Body)
+		}
+		context = strings.Join(comments, "\n\n")
+	case submitTask:
+		instructions = fmt.Sprintf("Write the summary of your review of #%d above, in markdown.", task.pull.number)
+		if task.event == "APPROVE" {
+			instructions += "\nSave it empty to approve without a summary."
+		}
+		pending := m.pendingReviews[task.pull].Comments
+		comments := make([]string, len(pending))
+		for i, comment := range pending {
+			comments[i] = fmt.Sprintf("%s, %s:\n%s", comment.Path, describeLines(comment), comment.Body)
+		}
+		context = strings.Join(comments, "\n\n")
+	}
+	return components.Compose(m.id, components.ComposerTemplate(body, instructions, context))
+}
+
+// handleComposed adds the comment written to the pending review, or sends the
+// reply or review.
+func (m *PullRequestsPageModel) handleComposed(msg components.ComposerDoneMsg) tea.Cmd {
+	task := m.task
+	m.task = nil
+	if msg.Err != nil {
+		m.setNotice("Editor failed: "+msg.Err.Error(), true)
+		return nil
+	}
+	if msg.Cancelled {
+		m.setNotice("Cancelled", false)
+		return nil
+	}
+	// An approval, or a comment with inline comments, needs no summary.
+	emptyAllowed := task.kind == submitTask &&
+		(task.event == "APPROVE" || (task.event == "COMMENT" && len(m.pendingReviews[task.pull].Comments) > 0))
+	if msg.Text == "" && !emptyAllowed {
+		m.setNotice("Nothing was written, cancelled", true)
+		return nil
+	}
+
+	switch task.kind {
+	case commentTask:
+		if task.comment.ID != "" {
+			m.setNotice("Saving comment…", false)
+			return utils.PageCmd(m.id, func() tea.Msg {
+				if err := m.pulls.EditReviewComment(context.Background(), task.comment.ID, msg.Text); err != nil {
+					return reviewActionFailedMsg{pull: task.pull, action: "save the comment", err: err}
+				}
+				return reviewActionDoneMsg{pull: task.pull, notice: "Pending comment saved"}
+			})
+		}
+		task.comment.Body = msg.Text
+		m.setNotice("Adding comment…", false)
+		review := m.pendingReviews[task.pull]
+		return utils.PageCmd(m.id, func() tea.Msg {
+			ctx := context.Background()
+			if review.ID == "" {
+				started, err := m.pulls.StartReview(ctx, task.pull.repo, task.pull.number)
+				if err != nil {
+					return reviewActionFailedMsg{pull: task.pull, action: "start a review", err: err}
+				}
+				review = started
+			}
+			if _, err := m.pulls.AddReviewComment(ctx, review.ID, task.comment); err != nil {
+				return reviewActionFailedMsg{pull: task.pull, action: "add the comment", err: err}
+			}
+			return reviewActionDoneMsg{pull: task.pull, notice: "Added to your pending review"}
+		})
+	case replyTask:
+		m.setNotice("Sending reply…", false)
+		commentID := task.thread.Comments[0].ID
+		return utils.PageCmd(m.id, func() tea.Msg {
+			if err := m.pulls.ReplyToReviewComment(context.Background(), task.pull.repo, task.pull.number, commentID, msg.Text); err != nil {
+				return reviewActionFailedMsg{pull: task.pull, action: "reply", err: err}
+			}
+			return reviewActionDoneMsg{pull: task.pull, notice: "Reply sent"}
+		})
+	default:
+		m.setNotice("Submitting review…", false)
+		review := m.pendingReviews[task.pull]
+		return utils.PageCmd(m.id, func() tea.Msg {
+			ctx := context.Background()
+			if review.ID == "" {
+				started, err := m.pulls.StartReview(ctx, task.pull.repo, task.pull.number)
+				if err != nil {
+					return reviewActionFailedMsg{pull: task.pull, action: "start a review", err: err}
+				}
+				review = started
+			}
+			if err := m.pulls.SubmitReview(ctx, review.ID, task.event, msg.Text); err != nil {
+				return reviewActionFailedMsg{pull: task.pull, action: "submit the review", err: err}
+			}
+			return reviewActionDoneMsg{pull: task.pull, notice: "Review submitted", submitted: true}
+		})
+	}
+}
+
+func (m *PullRequestsPageModel) deletePendingComment(pull pullRequestKey, comment gh.ReviewComment) tea.Cmd {
+	m.setNotice("Deleting comment…", false)
+	return utils.PageCmd(m.id, func() tea.Msg {
+		if err := m.pulls.DeleteReviewComment(context.Background(), comment.ID); err != nil {
+			return reviewActionFailedMsg{pull: pull, action: "delete the comment", err: err}
+		}
+		return reviewActionDoneMsg{pull: pull, notice: "Pending comment deleted"}
+	})
+}
+
+func (m *PullRequestsPageModel) resolveThread(pull pullRequestKey, thread gh.ReviewThread) tea.Cmd {
+	resolved := !thread.IsResolved
+	notice := "Thread resolved"
+	if !resolved {
+		notice = "Thread unresolved"
+	}
+	return utils.PageCmd(m.id, func() tea.Msg {
+		if err := m.pulls.SetReviewThreadResolved(context.Background(), thread.ID, resolved); err != nil {
+			return reviewActionFailedMsg{pull: pull, action: "resolve the thread", err: err}
+		}
+		return reviewActionDoneMsg{pull: pull, notice: notice}
+	})
+}
+
+// handleReviewActionDone clears a submitted review and shows the threads as
+// they are now.
+func (m *PullRequestsPageModel) handleReviewActionDone(msg reviewActionDoneMsg) tea.Cmd {
+	if msg.submitted {
+		delete(m.pendingReviews, msg.pull)
+	}
+	if msg.pull != m.diffPull() {
+		return nil
+	}
+	m.setNotice(msg.notice, false)
+
+	cmds := []tea.Cmd{m.updateAnnotations(), m.fetchThreads(msg.pull)}
+	if msg.submitted && m.detail != nil && m.detail.repo == msg.pull.repo && m.detail.number == msg.pull.number {
+		cmds = append(cmds, m.fetchDetail(msg.pull.repo, msg.pull.number))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *PullRequestsPageModel) setNotice(notice string, isError bool) {
+	m.notice = notice
+	m.noticeIsError = isError
+}
+
+// reviewStatus describes the pending review of the pull request whose diff is shown.
+func (m PullRequestsPageModel) reviewStatus() string {
+	pending := len(m.pendingReviews[m.diffPull()].Comments)
+	if pending == 0 {
+		return fmt.Sprintf("#%d · R submit a review", m.diffNumber)
+	}
+	return fmt.Sprintf("#%d · pending review with %d comments · R submit", m.diffNumber, pending)
+}
@@ -0,0 +1,312 @@
+package repopage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+type branchTaskKind int
+
+const (
+	createBranchTask branchTaskKind = iota
+	renameBranchTask
+	deleteBranchTask
+)
+
+// branchTask is a change to a branch waiting for its form or confirmation.
+type branchTask struct {
+	kind   branchTaskKind
+	branch string
+}
+
+type branchesReadyMsg struct {
+	list gh.BranchList
+	err  error
+}
+
+type branchActionDoneMsg struct {
+	notice string
+}
+
+type branchActionFailedMsg struct {
+	action string
+	err    error
+}
+
+// openBranches shows the branches and tags, listing them the first time.
+func (m *RepoPageModel) openBranches() tea.Cmd {
+	m.view = branchesView
+	focus := m.componentGroup.FocusOn(m.branchesListComponent)
+	if m.showTags {
+		focus = m.componentGroup.FocusOn(m.tagsListComponent)
+	}
+	if m.branches != nil {
+		return focus
+	}
+	return tea.Batch(focus, m.fetchBranches())
+}
+
+func (m *RepoPageModel) handleBranchesKey(msg tea.KeyMsg) tea.Cmd {
+	ref, selected := m.selectedRef()
+	branch, isBranch := m.componentGroup.
+		GetComponent(m.branchesListComponent).(components.ListModel[gh.Branch]).
+		GetSelectedItem()
+	isBranch = isBranch && !m.showTags
+
+	switch msg.String() {
+	case "t":
+		m.showTags = !m.showTags
+		if m.showTags {
+			return m.componentGroup.FocusOn(m.tagsListComponent)
+		}
+		return m.componentGroup.FocusOn(m.branchesListComponent)
+	case "enter", "f":
+		if !selected {
+			return nil
+		}
+		m.view = filesView
+		return tea.Batch(
+			m.componentGroup.FocusOn(m.entriesListComponent),
+			m.fetchDirectory(ref, ""),
+		)
+	case "c":
+		if !selected {
+			return nil
+		}
+		m.setRef(ref)
+		return m.openCommits("")
+	case "n":
+		m.branchTask = &branchTask{kind: createBranchTask}
+		return m.showBranchForm("Create a branch", []components.FormField{
+			{Label: "Name"},
+			{Label: "From", Value: ref, Hint: "A branch, tag or commit SHA"},
+		})
+	case "R":
+		if !isBranch {
+			return nil
+		}
+		m.branchTask = &branchTask{kind: renameBranchTask, branch: branch.Name}
+		return m.showBranchForm("Rename "+branch.Name, []components.FormField{
+			{Label: "New name", Value: branch.Name, Hint: "Open pull requests are retargeted to it"},
+		})
+	case "D":
+		if !isBranch {
+			return nil
+		}
+		if branch.Name == m.branches.DefaultBranch {
+			m.setNotice("The default branch cannot be deleted", true)
+			return nil
+		}
+		question := fmt.Sprintf("Delete the branch %s?", branch.Name)
+		if branch.PullRequest != 0 {
+			question = fmt.Sprintf("Delete the branch %s, closing #%d?", branch.Name, branch.PullRequest)
+		}
+		m.branchTask = &branchTask{kind: deleteBranchTask, branch: branch.Name}
+		return tea.Sequence(
+			m.componentGroup.Update(m.promptComponent, components.PromptResetMsg{
+				Question: question,
+				Options:  components.ConfirmOptions,
+			}),
+			m.componentGroup.FocusOn(m.promptComponent),
+		)
+	case "r":
+		return m.fetchBranches()
+	case "esc":
+		m.view = readmeView
+		return m.componentGroup.FocusOn(m.markdownViewerComponent)
+	default:
+		return m.componentGroup.UpdateFocused(msg)
+	}
+}
+
+func (m *RepoPageModel) showBranchForm(title string, fields []components.FormField) tea.Cmd {
+	return tea.Sequence(
+		m.componentGroup.Update(m.branchFormComponent, components.FormResetMsg{Title: title, Fields: fields}),
+		m.componentGroup.FocusOn(m.branchFormComponent),
+	)
+}
+
+// handleBranchForm creates or renames the branch of the task with the
+// values of the form.
+func (m *RepoPageModel) handleBranchForm(values []string) tea.Cmd {
+	task := m.branchTask
+	m.branchTask = nil
+	focus := m.componentGroup.FocusOn(m.branchesListComponent)
+	if task == nil {
+		return focus
+	}
+
+	name := strings.TrimSpace(values[0])
+	if name == "" {
+		m.setNotice("The branch needs a name", true)
+		return focus
+	}
+	repo := m.repo
+	switch task.kind {
+	case createBranchTask:
+		from := strings.TrimSpace(values[1])
+		if from == "" {
+			from = "HEAD"
+			if m.branches != nil {
+				from = m.branches.DefaultBranch
+			}
+		}
+		return tea.Batch(focus, m.performBranchAction("create "+name, func(ctx context.Context) error {
+			return m.repos.CreateBranch(ctx, repo, name, from)
+		}, fmt.Sprintf("Created %s from %s", name, from)))
+	default:
+		if name == task.branch {
+			return focus
+		}
+		return tea.Batch(focus, m.performBranchAction("rename "+task.branch, func(ctx context.Context) error {
+			return m.repos.RenameBranch(ctx, repo, task.branch, name)
+		}, fmt.Sprintf("Renamed %s to %s", task.branch, name)))
+	}
+}
+
+// handleBranchAnswer deletes the branch of the task once confirmed.
+func (m *RepoPageModel) handleBranchAnswer(answer string) tea.Cmd {
+	task := m.branchTask
+	m.branchTask = nil
+	focus := m.componentGroup.FocusOn(m.branchesListComponent)
+	if task == nil || answer != "yes" {
+		return focus
+	}
+	repo := m.repo
+	return tea.Batch(focus, m.performBranchAction("delete "+task.branch, func(ctx context.Context) error {
+		return m.repos.DeleteBranch(ctx, repo, task.branch)
+	}, "Deleted "+task.branch))
+}
+
+// performBranchAction runs action, then lists the branches again.
+func (m *RepoPageModel) performBranchAction(description string, action func(ctx context.Context) error, notice string) tea.Cmd {
+	return utils.PageCmd(m.id, func() tea.Msg {
+		if err := action(context.Background()); err != nil {
+			return branchActionFailedMsg{action: description, err: err}
+		}
+		return branchActionDoneMsg{notice: notice}
+	})
+}
+
+func (m *RepoPageModel) fetchBranches() tea.Cmd {
+	m.branchesLoading = true
+	repo := m.repo
+	return utils.PageCmd(m.id, func() tea.Msg {
+		list, err := m.repos.ListBranches(context.Background(), repo)
+		return branchesReadyMsg{list: list, err: err}
+	})
+}
+
+func (m *RepoPageModel) handleBranchesReady(msg branchesReadyMsg) tea.Cmd {
+	m.branchesLoading = false
+	if msg.err != nil {
+		m.setNotice("Failed to list the branches and tags: "+msg.err.Error(), true)
+		return nil
+	}
+	m.branches = &msg.list
+	// The ref picker offers the same branches and tags.
+	refs := msg.list.Refs()
+	m.refs = &refs
+	return tea.Batch(
+		m.componentGroup.Update(m.branchesListComponent, components.ListSetItemsMsg[gh.Branch]{Items: msg.list.Branches}),
+		m.componentGroup.Update(m.tagsListComponent, components.ListSetItemsMsg[gh.Tag]{Items: msg.list.Tags}),
+	)
+}
+
+// selectedRef returns the name of the branch or tag under the cursor.
+func (m RepoPageModel) selectedRef() (string, bool) {
+	if m.showTags {
+		tag, ok := m.componentGroup.
+			GetComponent(m.tagsListComponent).(components.ListModel[gh.Tag]).
+			GetSelectedItem()
+		return tag.Name, ok
+	}
+	branch, ok := m.componentGroup.
+		GetComponent(m.branchesListComponent).(components.ListModel[gh.Branch]).
+		GetSelectedItem()
+	return branch.Name, ok
+}
+
+// branchesBody renders the branches or tags, or the form to change a branch.
+func (m RepoPageModel) branchesBody() string {
+	if m.componentGroup.IsFocused(m.branchFormComponent) {
+		return m.componentGroup.GetComponent(m.branchFormComponent).View()
+	}
+
+	listed, list, count := "branches", m.branchesListComponent, 0
+	if m.showTags {
+		listed, list = "tags", m.tagsListComponent
+	}
+	if m.branches != nil {
+		count = len(m.branches.Branches)
+		if m.showTags {
+			count = len(m.branches.Tags)
+		}
+	}
+	summary := fmt.Sprintf("%d %s", count, listed)
+	if m.branchesLoading {
+		summary += " " + m.componentGroup.GetComponent(m.spinnerComponent).View()
+	}
+	keys := " · enter files · c commits · n new branch · R rename · D delete · t branches/tags · r refresh · esc back"
+	if m.showTags {
+		keys = " · enter files · c commits · n new branch · t branches/tags · r refresh · esc back"
+	}
+	header := lipgloss.NewStyle().
+		MaxWidth(m.width).
+		MaxHeight(1).
+		Render(lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			chipStyle.Render(listed),
+			summary,
+			mutedStyle.Render(keys),
+		))
+
+	if count == 0 && !m.branchesLoading {
+		return lipgloss.JoinVertical(lipgloss.Left, header, mutedStyle.Render("No "+listed+"."))
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		m.componentGroup.GetComponent(list).View(),
+	)
+}
+
+// renderBranch renders a branch as a line of the list: its name, protection,
+// how far it is from the default branch, its pull request and last commit.
+func renderBranch(branch gh.Branch, width int) string {
+	parts := []string{directoryStyle.Render(branch.Name)}
+	if branch.IsDefault {
+		parts = append(parts, "default")
+	}
+	if branch.Protected {
+		parts = append(parts, pendingStyle.Render("protected"))
+	}
+	if branch.Ahead > 0 || branch.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d ↓%d", branch.Ahead, branch.Behind))
+	}
+	if branch.PullRequest != 0 {
+		parts = append(parts, successStyle.Render(fmt.Sprintf("#%d", branch.PullRequest))+" "+branch.PullRequestTitle)
+	}
+	return strings.Join(append(parts, renderRefCommit(branch.Commit)), mutedStyle.Render(" · "))
+}
+
+// renderTag renders a tag as a line of the list: its name and commit.
+func renderTag(tag gh.Tag, width int) string {
+	return directoryStyle.Render(tag.Name) + mutedStyle.Render(" · ") + renderRefCommit(tag.Commit)
+}
+
+func renderRefCommit(commit gh.RefCommit) string {
+	return shaStyle.Render(shortSHA(commit.SHA)) + " " + commit.Subject + mutedStyle.Render(fmt.Sprintf(
+		" · @%s · %s",
+		commit.Author,
+		utils.Since(commit.Date),
+	))
+}
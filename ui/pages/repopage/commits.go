@@ -0,0 +1,449 @@
+package repopage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+const commitsPerPage = 50
+
+// A line of the commits list: a commit and whether it is marked to compare.
+type commitRow struct {
+	commit gh.CommitSummary
+	marked bool
+}
+
+// commitDiff is the commit, or the comparison of two commits, shown in the
+// diff viewer.
+type commitDiff struct {
+	// Empty for a single commit.
+	base string
+	head string
+	// Shown above the diff: the commit's message, or the commits compared.
+	header  string
+	loading bool
+}
+
+type commitsReadyMsg struct {
+	request int
+	page    gh.CommitPage
+	err     error
+}
+
+type commitDiffReadyMsg struct {
+	base   string
+	head   string
+	header string
+	files  []*github.CommitFile
+	err    error
+}
+
+// openCommits shows the history of the ref browsed, of the file or
+// directory at filePath if it is not empty.
+func (m *RepoPageModel) openCommits(filePath string) tea.Cmd {
+	m.commitsReturnView = m.view
+	m.view = commitsView
+	m.commitsPath = filePath
+	m.commitCursors = nil
+	m.commitCursor = ""
+	m.marked = nil
+	return tea.Batch(
+		m.componentGroup.FocusOn(m.commitsListComponent),
+		m.componentGroup.Update(m.commitsListComponent, components.ListResetViewportMsg{}),
+		m.fetchCommits(),
+	)
+}
+
+func (m *RepoPageModel) handleCommitsKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case m.componentGroup.IsFocused(m.refsListComponent):
+		return m.handleRefsKey(msg)
+	case m.componentGroup.IsFocused(m.commitDiffViewerComponent):
+		if msg.String() == "esc" {
+			m.commitDiff = nil
+			return m.componentGroup.FocusOn(m.commitsListComponent)
+		}
+		return m.componentGroup.UpdateFocused(msg)
+	}
+
+	row, selected := m.componentGroup.
+		GetComponent(m.commitsListComponent).(components.ListModel[commitRow]).
+		GetSelectedItem()
+	switch msg.String() {
+	case "enter":
+		if !selected {
+			return nil
+		}
+		return m.openCommitDiff("", row.commit.SHA)
+	case "v":
+		if !selected {
+			return nil
+		}
+		m.toggleMark(row.commit)
+		return m.updateCommitsList()
+	case "=":
+		if len(m.marked) != 2 {
+			m.setNotice("Mark two commits with v to compare them", true)
+			return nil
+		}
+		base, head := m.marked[0], m.marked[1]
+		if base.Date.After(head.Date) {
+			base, head = head, base
+		}
+		return m.openCommitDiff(base.SHA, head.SHA)
+	case "]":
+		if m.nextCommitCursor == "" || m.commitsLoading {
+			return nil
+		}
+		m.commitCursors = append(m.commitCursors, m.commitCursor)
+		m.commitCursor = m.nextCommitCursor
+		return tea.Batch(
+			m.componentGroup.Update(m.commitsListComponent, components.ListResetViewportMsg{}),
+			m.fetchCommits(),
+		)
+	case "[":
+		if len(m.commitCursors) == 0 || m.commitsLoading {
+			return nil
+		}
+		m.commitCursor = m.commitCursors[len(m.commitCursors)-1]
+		m.commitCursors = m.commitCursors[:len(m.commitCursors)-1]
+		return tea.Batch(
+			m.componentGroup.Update(m.commitsListComponent, components.ListResetViewportMsg{}),
+			m.fetchCommits(),
+		)
+	case "b":
+		if m.refs == nil {
+			m.setNotice("Still loading the branches and tags", false)
+			return m.fetchRefs()
+		}
+		return tea.Sequence(
+			m.componentGroup.Update(m.refsListComponent, components.ListSetItemsMsg[refChoice]{
+				Items: refChoices(*m.refs),
+			}),
+			m.componentGroup.FocusOn(m.refsListComponent),
+		)
+	case "p":
+		return tea.Sequence(
+			m.componentGroup.Update(m.pathFormComponent, components.FormResetMsg{
+				Title: "Commits changing",
+				Fields: []components.FormField{
+					{Label: "Path", Value: m.commitsPath, Hint: "A file or directory, empty for every commit"},
+				},
+			}),
+			m.componentGroup.FocusOn(m.pathFormComponent),
+		)
+	case "r":
+		return m.fetchCommits()
+	case "esc":
+		switch m.view = m.commitsReturnView; m.view {
+		case filesView:
+			return m.componentGroup.FocusOn(m.entriesListComponent)
+		case branchesView:
+			return m.openBranches()
+		default:
+			return m.componentGroup.FocusOn(m.markdownViewerComponent)
+		}
+	default:
+		return m.componentGroup.UpdateFocused(msg)
+	}
+}
+
+// switchCommitsRef lists the history of ref instead.
+func (m *RepoPageModel) switchCommitsRef(ref string) tea.Cmd {
+	m.setRef(ref)
+	m.commitCursors = nil
+	m.commitCursor = ""
+	return tea.Batch(
+		m.componentGroup.FocusOn(m.commitsListComponent),
+		m.componentGroup.Update(m.commitsListComponent, components.ListResetViewportMsg{}),
+		m.fetchCommits(),
+	)
+}
+
+// toggleMark marks commit to compare, or unmarks it. Marking a third commit
+// unmarks the one marked first.
+func (m *RepoPageModel) toggleMark(commit gh.CommitSummary) {
+	for i, marked := range m.marked {
+		if marked.SHA == commit.SHA {
+			m.marked = append(m.marked[:i:i], m.marked[i+1:]...)
+			return
+		}
+	}
+	m.marked = append(m.marked, commit)
+	if len(m.marked) > 2 {
+		m.marked = m.marked[1:]
+	}
+}
+
+// fetchCommits lists the page of commits at the cursor.
+func (m *RepoPageModel) fetchCommits() tea.Cmd {
+	m.commitsRequest++
+	m.commitsLoading = true
+	request := m.commitsRequest
+	repo := m.repo
+	search := gh.CommitSearch{
+		Ref:     m.ref,
+		Path:    m.commitsPath,
+		After:   m.commitCursor,
+		PerPage: commitsPerPage,
+	}
+	return utils.PageCmd(m.id, func() tea.Msg {
+		page, err := m.repos.ListCommits(context.Background(), repo, search)
+		return commitsReadyMsg{request: request, page: page, err: err}
+	})
+}
+
+func (m *RepoPageModel) handleCommitsReady(msg commitsReadyMsg) tea.Cmd {
+	if msg.request != m.commitsRequest {
+		return nil
+	}
+	m.commitsLoading = false
+	if msg.err != nil {
+		m.setNotice("Failed to list the commits: "+msg.err.Error(), true)
+		return nil
+	}
+	m.commits = msg.page.Commits
+	m.nextCommitCursor = msg.page.EndCursor
+	return m.updateCommitsList()
+}
+
+// updateCommitsList lists the commits with the ones marked to compare.
+func (m *RepoPageModel) updateCommitsList() tea.Cmd {
+	rows := make([]commitRow, len(m.commits))
+	for i, commit := range m.commits {
+		rows[i] = commitRow{commit: commit}
+		for _, marked := range m.marked {
+			if marked.SHA == commit.SHA {
+				rows[i].marked = true
+			}
+		}
+	}
+	return m.componentGroup.Update(m.commitsListComponent, components.ListSetItemsMsg[commitRow]{Items: rows})
+}
+
+// openCommitDiff shows the changes of head, or from base to head when base is
+// not empty, in the diff viewer.
+func (m *RepoPageModel) openCommitDiff(base string, head string) tea.Cmd {
+	m.commitDiff = &commitDiff{base: base, head: head, loading: true}
+	repo := m.repo
+	return tea.Sequence(
+		m.resizeCommitDiffViewer(),
+		m.componentGroup.Update(m.commitDiffViewerComponent, components.DiffViewerSetFilesMsg{}),
+		m.componentGroup.FocusOn(m.commitDiffViewerComponent),
+		utils.PageCmd(m.id, func() tea.Msg {
+			ctx := context.Background()
+			if base == "" {
+				commit, err := m.repos.GetCommit(ctx, repo, head)
+				if err != nil {
+					return commitDiffReadyMsg{base: base, head: head, err: err}
+				}
+				return commitDiffReadyMsg{base: base, head: head, header: describeCommit(commit), files: commit.Files}
+			}
+			comparison, err := m.repos.CompareCommits(ctx, repo, base, head)
+			if err != nil {
+				return commitDiffReadyMsg{base: base, head: head, err: err}
+			}
+			return commitDiffReadyMsg{base: base, head: head, header: describeComparison(base, head, comparison), files: comparison.Files}
+		}),
+	)
+}
+
+func (m *RepoPageModel) handleCommitDiffReady(msg commitDiffReadyMsg) tea.Cmd {
+	diff := m.commitDiff
+	if diff == nil || diff.base != msg.base || diff.head != msg.head {
+		return nil
+	}
+	if msg.err != nil {
+		m.commitDiff = nil
+		m.setNotice("Failed to load the changes: "+msg.err.Error(), true)
+		return m.componentGroup.FocusOn(m.commitsListComponent)
+	}
+	diff.loading = false
+	diff.header = msg.header
+	return tea.Sequence(
+		m.resizeCommitDiffViewer(),
+		m.componentGroup.Update(m.commitDiffViewerComponent, components.DiffViewerSetFilesMsg{Files: msg.files}),
+	)
+}
+
+// fetchCommitPatch loads the patch GitHub left out of a file's listing from
+// the whole diff.
+func (m *RepoPageModel) fetchCommitPatch(filename string) tea.Cmd {
+	repo, base, head := m.repo, m.commitDiff.base, m.commitDiff.head
+	return utils.PageCmd(m.id, func() tea.Msg {
+		diff, err := m.repos.GetCommitDiff(context.Background(), repo, base, head)
+		if err != nil {
+			return components.DiffViewerSetPatchMsg{Filename: filename, Err: err}
+		}
+		return components.DiffViewerSetPatchMsg{Filename: filename, Patch: gh.FilePatch(diff, filename)}
+	})
+}
+
+// resizeCommitDiffViewer fits the diff viewer under the header of the diff shown.
+func (m *RepoPageModel) resizeCommitDiffViewer() tea.Cmd {
+	return m.componentGroup.Update(m.commitDiffViewerComponent, utils.UpdateSizeMsg{
+		ID:     m.commitDiffViewerComponent,
+		Width:  m.width,
+		Height: max(1, m.height-1-m.commitHeaderHeight()),
+	})
+}
+
+// commitHeaderHeight is the height of the header of the diff shown, at most a
+// third of the page.
+func (m RepoPageModel) commitHeaderHeight() int {
+	if m.commitDiff == nil || m.commitDiff.header == "" {
+		return 1
+	}
+	return min(strings.Count(m.commitDiff.header, "\n")+1, max(1, m.height/3))
+}
+
+// commitsBody renders the commits list, or the diff of the commit or
+// comparison opened.
+func (m RepoPageModel) commitsBody() string {
+	if m.componentGroup.IsFocused(m.pathFormComponent) {
+		return m.componentGroup.GetComponent(m.pathFormComponent).View()
+	}
+
+	if diff := m.commitDiff; diff != nil {
+		header := diff.header
+		if diff.loading {
+			header = m.componentGroup.GetComponent(m.spinnerComponent).View() + " Loading " + describeRange(diff.base, diff.head)
+		}
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			lipgloss.NewStyle().MaxWidth(m.width).MaxHeight(m.commitHeaderHeight()).Render(header),
+			m.componentGroup.GetComponent(m.commitDiffViewerComponent).View(),
+		)
+	}
+
+	ref := m.ref
+	if ref == "" && m.refs != nil {
+		ref = m.refs.DefaultBranch
+	}
+	location := "all files"
+	if m.commitsPath != "" {
+		location = "/" + m.commitsPath
+	}
+	if m.commitsLoading {
+		location += " " + m.componentGroup.GetComponent(m.spinnerComponent).View()
+	}
+	header := lipgloss.NewStyle().
+		MaxWidth(m.width).
+		MaxHeight(1).
+		Render(lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			chipStyle.Render(ref),
+			fmt.Sprintf("page %d · %s", len(m.commitCursors)+1, location),
+			mutedStyle.Render(" · enter open · v mark · = compare marked · ] [ pages · b ref · p path · esc back"),
+		))
+
+	if m.componentGroup.IsFocused(m.refsListComponent) {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			m.componentGroup.GetComponent(m.refsListComponent).View(),
+		)
+	}
+	if len(m.commits) == 0 && !m.commitsLoading {
+		return lipgloss.JoinVertical(lipgloss.Left, header, mutedStyle.Render("No commits."))
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		m.componentGroup.GetComponent(m.commitsListComponent).View(),
+	)
+}
+
+// renderCommitRow renders a commit as a line of the list: whether it is
+// marked, its checks, short SHA, subject, author, date and signature.
+func renderCommitRow(row commitRow, width int) string {
+	commit := row.commit
+	marker := " "
+	if row.marked {
+		marker = markedStyle.Render("◆")
+	}
+	parts := []string{
+		fmt.Sprintf("%s%s %s %s", marker, checkIcon(commit.CheckStatus), shaStyle.Render(shortSHA(commit.SHA)), commit.Subject),
+		"@" + commit.Author,
+		utils.Since(commit.Date),
+	}
+	if commit.Verified {
+		parts = append(parts, successStyle.Render("verified"))
+	} else if commit.Signed {
+		parts = append(parts, failureStyle.Render("unverified"))
+	}
+	return strings.Join(parts, mutedStyle.Render(" · "))
+}
+
+// describeCommit describes a commit above its diff: its SHA, author, date,
+// signature and full message.
+func describeCommit(commit *github.RepositoryCommit) string {
+	details := commit.GetCommit()
+	parts := []string{shaStyle.Render(shortSHA(commit.GetSHA()))}
+	author := details.GetAuthor().GetName()
+	if login := commit.GetAuthor().GetLogin(); login != "" {
+		author = "@" + login
+	}
+	parts = append(parts, author, utils.Since(details.GetAuthor().GetDate().Time))
+	if verification := details.GetVerification(); verification.GetVerified() {
+		parts = append(parts, successStyle.Render("verified"))
+	} else if verification.GetSignature() != "" {
+		parts = append(parts, failureStyle.Render("unverified: "+strings.ReplaceAll(verification.GetReason(), "_", " ")))
+	}
+	stats := commit.GetStats()
+	parts = append(parts, fmt.Sprintf("%d files, +%d -%d", len(commit.Files), stats.GetAdditions(), stats.GetDeletions()))
+	return strings.Join(parts, mutedStyle.Render(" · ")) + "\n" + strings.TrimSpace(details.GetMessage())
+}
+
+// describeComparison describes the comparison of two commits above its diff:
+// the commits compared and the commits between them.
+func describeComparison(base string, head string, comparison *github.CommitsComparison) string {
+	lines := []string{
+		shaStyle.Render(describeRange(base, head)) + mutedStyle.Render(fmt.Sprintf(
+			" · %d commits, %d files · %s",
+			comparison.GetTotalCommits(),
+			len(comparison.Files),
+			comparison.GetStatus(),
+		)),
+	}
+	for _, commit := range comparison.Commits {
+		subject, _, _ := strings.Cut(commit.GetCommit().GetMessage(), "\n")
+		lines = append(lines, shaStyle.Render(shortSHA(commit.GetSHA()))+" "+subject)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeRange describes a commit, or the commits from base to head.
+func describeRange(base string, head string) string {
+	if base == "" {
+		return "commit " + shortSHA(head)
+	}
+	return shortSHA(base) + ".." + shortSHA(head)
+}
+
+func shortSHA(sha string) string {
+	return sha[:min(7, len(sha))]
+}
+
+// checkIcon returns an icon for the combined state of a commit's checks.
+func checkIcon(state string) string {
+	switch strings.ToUpper(state) {
+	case "SUCCESS":
+		return successStyle.Render("✓")
+	case "FAILURE", "ERROR":
+		return failureStyle.Render("✗")
+	case "PENDING", "EXPECTED":
+		return pendingStyle.Render("●")
+	default:
+		return " "
+	}
+}
@@ -0,0 +1,565 @@
+package repopage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+// Files larger than this are not previewed.
+const maxPreviewSize = 512 * 1024
+
+// How much of a file is checked for NUL bytes to tell whether it is binary.
+const binarySniffSize = 8000
+
+var imageExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".bmp": true, ".ico": true,
+}
+
+var markdownExtensions = map[string]bool{
+	".md": true, ".markdown": true, ".mdown": true,
+}
+
+// A ref to browse the files at, in the ref picker.
+type refChoice struct {
+	name string
+	// "branch" or "tag".
+	kind string
+	// Whether it is the default branch.
+	isDefault bool
+}
+
+type previewKind int
+
+const (
+	notePreview previewKind = iota
+	codePreview
+	markdownPreview
+)
+
+// filePreview is the file shown next to the directory.
+type filePreview struct {
+	path    string
+	kind    previewKind
+	loading bool
+	// Shown in place of files that cannot be previewed, or while loading.
+	note string
+}
+
+type directoryReadyMsg struct {
+	request int
+	ref     string
+	dir     string
+	entries []gh.ContentEntry
+	err     error
+}
+
+type fileReadyMsg struct {
+	ref  string
+	path string
+	file gh.RepoFile
+	err  error
+}
+
+type refsReadyMsg struct {
+	refs gh.RepoRefs
+	err  error
+}
+
+// openFiles shows the files browser, listing the root on the default branch
+// the first time.
+func (m *RepoPageModel) openFiles() tea.Cmd {
+	m.view = filesView
+	if m.entriesLoaded {
+		return m.componentGroup.FocusOn(m.entriesListComponent)
+	}
+	return tea.Batch(
+		m.componentGroup.FocusOn(m.entriesListComponent),
+		m.fetchDirectory(m.ref, ""),
+		m.fetchRefs(),
+	)
+}
+
+func (m *RepoPageModel) handleFilesKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case m.componentGroup.IsFocused(m.refsListComponent):
+		return m.handleRefsKey(msg)
+	case m.componentGroup.IsFocused(m.fileViewerComponent) || m.componentGroup.IsFocused(m.fileMarkdownViewerComponent):
+		if msg.String() == "esc" {
+			return m.componentGroup.FocusOn(m.entriesListComponent)
+		}
+		return m.componentGroup.UpdateFocused(msg)
+	}
+
+	switch msg.String() {
+	case "enter", "l", "right":
+		entry, ok := m.getSelectedEntry()
+		if !ok {
+			return nil
+		}
+		if entry.Type == "dir" {
+			return m.fetchDirectory(m.ref, entry.Path)
+		}
+		return m.previewFile(entry)
+	case "h", "left", "backspace":
+		if m.dir == "" {
+			return nil
+		}
+		parent := path.Dir(m.dir)
+		if parent == "." {
+			parent = ""
+		}
+		return m.fetchDirectory(m.ref, parent)
+	case "b":
+		if m.refs == nil {
+			m.setNotice("Still loading the branches and tags", false)
+			return m.fetchRefs()
+		}
+		return tea.Sequence(
+			m.componentGroup.Update(m.refsListComponent, components.ListSetItemsMsg[refChoice]{
+				Items: refChoices(*m.refs),
+			}),
+			m.componentGroup.FocusOn(m.refsListComponent),
+		)
+	case "B":
+		return tea.Sequence(
+			m.componentGroup.Update(m.refFormComponent, components.FormResetMsg{
+				Title: "Browse the files at",
+				Fields: []components.FormField{
+					{Label: "Ref", Value: m.ref, Hint: "A branch, tag or commit SHA, empty for the default branch"},
+				},
+			}),
+			m.componentGroup.FocusOn(m.refFormComponent),
+		)
+	case "c":
+		historyPath := m.dir
+		if entry, ok := m.getSelectedEntry(); ok {
+			historyPath = entry.Path
+		}
+		return m.openCommits(historyPath)
+	case "esc":
+		m.view = readmeView
+		return m.componentGroup.FocusOn(m.markdownViewerComponent)
+	default:
+		return m.componentGroup.UpdateFocused(msg)
+	}
+}
+
+func (m *RepoPageModel) handleRefsKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		choice, ok := m.componentGroup.
+			GetComponent(m.refsListComponent).(components.ListModel[refChoice]).
+			GetSelectedItem()
+		if !ok {
+			return nil
+		}
+		if m.view == commitsView {
+			return m.switchCommitsRef(choice.name)
+		}
+		return tea.Batch(
+			m.componentGroup.FocusOn(m.entriesListComponent),
+			m.fetchDirectory(choice.name, m.dir),
+		)
+	case "esc":
+		if m.view == commitsView {
+			return m.componentGroup.FocusOn(m.commitsListComponent)
+		}
+		return m.componentGroup.FocusOn(m.entriesListComponent)
+	default:
+		return m.componentGroup.UpdateFocused(msg)
+	}
+}
+
+// setRef browses ref from now on. The files browser lists the root at ref the
+// next time it is opened.
+func (m *RepoPageModel) setRef(ref string) {
+	if ref != m.ref {
+		m.ref = ref
+		m.dir = ""
+		m.preview = nil
+		m.entriesLoaded = false
+	}
+}
+
+// fetchDirectory lists dir at ref. The files browser moves there once it is
+// listed, and stays where it was if that fails.
+func (m *RepoPageModel) fetchDirectory(ref string, dir string) tea.Cmd {
+	m.directoryRequest++
+	request := m.directoryRequest
+	m.directoryLoading = true
+	repo := m.repo
+	return utils.PageCmd(m.id, func() tea.Msg {
+		entries, err := m.repos.ListDirectory(context.Background(), repo, ref, dir)
+		return directoryReadyMsg{request: request, ref: ref, dir: dir, entries: entries, err: err}
+	})
+}
+
+func (m *RepoPageModel) handleDirectoryReady(msg directoryReadyMsg) tea.Cmd {
+	if msg.request != m.directoryRequest {
+		return nil
+	}
+	m.directoryLoading = false
+	open := m.pendingOpen
+	m.pendingOpen = ""
+	if msg.err != nil {
+		where := "/" + msg.dir
+		if msg.ref != "" {
+			where += " at " + msg.ref
+		}
+		m.setNotice(fmt.Sprintf("Failed to list %s: %s", where, msg.err), true)
+		if !m.entriesLoaded {
+			// Nothing to browse, so back to the README.
+			m.view = readmeView
+			return m.componentGroup.FocusOn(m.markdownViewerComponent)
+		}
+		return nil
+	}
+
+	// Coming back up, the directory left is selected, and following a link the
+	// entry it is to.
+	selected := 0
+	if open != "" {
+		selected = slices.IndexFunc(msg.entries, func(entry gh.ContentEntry) bool { return entry.Name == open })
+		if selected < 0 {
+			m.setNotice(fmt.Sprintf("%s not found", path.Join("/"+msg.dir, open)), true)
+			selected = 0
+		}
+	} else if m.entriesLoaded && msg.ref == m.ref && strings.HasPrefix(m.dir, msg.dir) && m.dir != msg.dir {
+		child := strings.TrimPrefix(strings.TrimPrefix(m.dir, msg.dir), "/")
+		child, _, _ = strings.Cut(child, "/")
+		for i, entry := range msg.entries {
+			if entry.Name == child {
+				selected = i
+			}
+		}
+	}
+	if msg.ref != m.ref {
+		m.preview = nil
+	}
+
+	m.entriesLoaded = true
+	m.ref = msg.ref
+	m.dir = msg.dir
+	cmds := []tea.Cmd{
+		m.componentGroup.Update(m.entriesListComponent, components.ListSetItemsMsg[gh.ContentEntry]{Items: msg.entries}),
+		m.componentGroup.Update(m.entriesListComponent, components.ListResetViewportMsg{}),
+		m.componentGroup.Update(m.entriesListComponent, components.ListSetCursorMsg{Index: selected}),
+	}
+	if open != "" && selected < len(msg.entries) && msg.entries[selected].Name == open {
+		entry := msg.entries[selected]
+		if entry.Type == "dir" {
+			cmds = append(cmds, m.fetchDirectory(m.ref, entry.Path))
+		} else {
+			cmds = append(cmds, m.previewFile(entry))
+		}
+	}
+	return tea.Sequence(cmds...)
+}
+
+// followLink opens links to the repository's files in the files browser,
+// links to issues and pull requests in their pages and others in the browser.
+func (m *RepoPageModel) followLink(link string) tea.Cmd {
+	target, err := url.Parse(link)
+	if err == nil && m.webHost != "" && strings.EqualFold(target.Host, m.webHost) {
+		// /owner/name/blob/ref/path, /owner/name/tree/ref/path, /owner/name/issues/1 or /owner/name/pull/1
+		segments := strings.Split(strings.Trim(target.Path, "/"), "/")
+		if len(segments) >= 4 {
+			repo := gh.Repo{Owner: segments[0], Name: segments[1]}
+			switch segments[2] {
+			case "issues", "pull":
+				if number, err := strconv.Atoi(segments[3]); err == nil {
+					return func() tea.Msg {
+						return utils.OpenItemMsg{
+							Repo:        repo,
+							Number:      number,
+							Title:       fmt.Sprintf("%s#%d", repo, number),
+							PullRequest: segments[2] == "pull",
+						}
+					}
+				}
+			case "blob", "tree":
+				if strings.EqualFold(repo.String(), m.repo.String()) {
+					if m.refs == nil {
+						// Which segments are the ref, as refs can hold
+						// slashes, is told once the refs are listed.
+						m.pendingLink = link
+						return m.fetchRefs()
+					}
+					ref, filePath := splitRefPath(*m.refs, segments[3:])
+					return m.openPath(ref, filePath)
+				}
+			}
+		}
+	}
+
+	if err := utils.OpenInBrowser(link); err != nil {
+		m.setNotice(fmt.Sprintf("Failed to open %s: %s", link, err), true)
+		return nil
+	}
+	m.setNotice("Opened "+link+" in the browser", false)
+	return nil
+}
+
+// splitRefPath splits the segments of a path following blob/ or tree/ into the
+// ref and the path on it: the longest branch or tag the segments start with,
+// or else the first segment, such as a commit SHA.
+func splitRefPath(refs gh.RepoRefs, segments []string) (string, string) {
+	names := append([]string{refs.DefaultBranch}, refs.Branches...)
+	names = append(names, refs.Tags...)
+	for n := len(segments); n > 1; n-- {
+		if ref := strings.Join(segments[:n], "/"); slices.Contains(names, ref) {
+			return ref, strings.Join(segments[n:], "/")
+		}
+	}
+	return segments[0], strings.Join(segments[1:], "/")
+}
+
+// openPath shows the file or directory at filePath on ref in the files browser.
+func (m *RepoPageModel) openPath(ref string, filePath string) tea.Cmd {
+	m.view = filesView
+	cmds := []tea.Cmd{m.componentGroup.FocusOn(m.entriesListComponent)}
+	if m.refs == nil {
+		cmds = append(cmds, m.fetchRefs())
+	}
+	if filePath == "" {
+		return tea.Batch(append(cmds, m.fetchDirectory(ref, ""))...)
+	}
+	parent := path.Dir(filePath)
+	if parent == "." {
+		parent = ""
+	}
+	fetch := m.fetchDirectory(ref, parent)
+	m.pendingOpen = path.Base(filePath)
+	return tea.Batch(append(cmds, fetch)...)
+}
+
+// recordWebHost records the host of a page of the repository on GitHub.
+func (m *RepoPageModel) recordWebHost(htmlURL string) {
+	if parsed, err := url.Parse(htmlURL); err == nil && parsed.Host != "" {
+		m.webHost = parsed.Host
+	}
+}
+
+// previewFile shows entry next to the directory, loading it unless it cannot
+// be previewed anyway.
+func (m *RepoPageModel) previewFile(entry gh.ContentEntry) tea.Cmd {
+	m.preview = &filePreview{path: entry.Path, kind: notePreview}
+	switch {
+	case entry.Type == "submodule":
+		m.preview.note = fmt.Sprintf("%s is a submodule, another repository.", entry.Name)
+		return nil
+	case entry.Size > maxPreviewSize:
+		m.preview.note = fmt.Sprintf("%s is %s, too large to preview.", entry.Name, utils.FormatSize(int64(entry.Size)))
+		return nil
+	}
+
+	m.preview.loading = true
+	m.preview.note = "Loading " + entry.Name + "…"
+	repo, ref := m.repo, m.ref
+	return utils.PageCmd(m.id, func() tea.Msg {
+		file, err := m.repos.GetFile(context.Background(), repo, ref, entry.Path)
+		return fileReadyMsg{ref: ref, path: entry.Path, file: file, err: err}
+	})
+}
+
+func (m *RepoPageModel) handleFileReady(msg fileReadyMsg) tea.Cmd {
+	if m.preview == nil || m.preview.path != msg.path || m.ref != msg.ref {
+		return nil
+	}
+	preview := m.preview
+	preview.loading = false
+	name := path.Base(msg.path)
+	file := msg.file
+	extension := strings.ToLower(path.Ext(msg.path))
+
+	switch {
+	case msg.err != nil:
+		preview.note = fmt.Sprintf("Failed to load %s: %s", name, msg.err)
+		return nil
+	case file.Target != "":
+		preview.note = fmt.Sprintf("%s links to %s.", name, file.Target)
+		return nil
+	case file.Content == nil:
+		preview.note = fmt.Sprintf("%s is %s, too large to preview.", name, utils.FormatSize(int64(file.Size)))
+		return nil
+	case imageExtensions[extension]:
+		preview.note = describeImage(name, file)
+		return nil
+	case isBinary(file.Content):
+		preview.note = fmt.Sprintf("%s is a binary file of %s.", name, utils.FormatSize(int64(file.Size)))
+		return nil
+	case markdownExtensions[extension]:
+		preview.kind = markdownPreview
+		m.recordWebHost(file.HTMLURL)
+		return tea.Sequence(
+			m.componentGroup.Update(m.fileMarkdownViewerComponent, components.MarkdownViewerSetContentMsg{
+				Content: string(file.Content),
+				BaseURL: file.HTMLURL,
+			}),
+			m.componentGroup.FocusOn(m.fileMarkdownViewerComponent),
+		)
+	default:
+		preview.kind = codePreview
+		return tea.Sequence(
+			m.componentGroup.Update(m.fileViewerComponent, components.FileViewerSetFileMsg{
+				Path:    msg.path,
+				Content: string(file.Content),
+			}),
+			m.componentGroup.FocusOn(m.fileViewerComponent),
+		)
+	}
+}
+
+func (m *RepoPageModel) fetchRefs() tea.Cmd {
+	repo := m.repo
+	return utils.PageCmd(m.id, func() tea.Msg {
+		list, err := m.repos.ListBranches(context.Background(), repo)
+		return refsReadyMsg{refs: list.Refs(), err: err}
+	})
+}
+
+// filesBody renders the ref and directory, the directory's entries and the
+// file previewed.
+func (m RepoPageModel) filesBody() string {
+	if m.componentGroup.IsFocused(m.refFormComponent) {
+		return m.componentGroup.GetComponent(m.refFormComponent).View()
+	}
+
+	ref := m.ref
+	if ref == "" && m.refs != nil {
+		ref = m.refs.DefaultBranch
+	}
+	location := "/" + m.dir
+	if m.directoryLoading {
+		location += " " + m.componentGroup.GetComponent(m.spinnerComponent).View()
+	}
+	header := lipgloss.NewStyle().
+		MaxWidth(m.width).
+		MaxHeight(1).
+		Render(lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			chipStyle.Render(ref),
+			location,
+			mutedStyle.Render(" · enter open · h up · c history · b branches and tags · B any ref · esc README"),
+		))
+
+	if m.componentGroup.IsFocused(m.refsListComponent) {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			m.componentGroup.GetComponent(m.refsListComponent).View(),
+		)
+	}
+
+	entries := m.componentGroup.GetComponent(m.entriesListComponent).View()
+	if m.preview == nil {
+		return lipgloss.JoinVertical(lipgloss.Left, header, entries)
+	}
+
+	var preview string
+	switch m.preview.kind {
+	case codePreview:
+		preview = m.componentGroup.GetComponent(m.fileViewerComponent).View()
+	case markdownPreview:
+		preview = m.componentGroup.GetComponent(m.fileMarkdownViewerComponent).View()
+	default:
+		preview = lipgloss.NewStyle().
+			Width(m.previewWidth()).
+			Height(m.height - 2).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(mutedStyle.Render(m.preview.note))
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		lipgloss.JoinHorizontal(lipgloss.Top, entries, previewBorderStyle.Render(preview)),
+	)
+}
+
+func (m RepoPageModel) entriesWidth() int {
+	return max(20, m.width/3)
+}
+
+// previewWidth is the width of the preview, inside its border.
+func (m RepoPageModel) previewWidth() int {
+	return max(0, m.width-m.entriesWidth()-1)
+}
+
+func (m RepoPageModel) getSelectedEntry() (gh.ContentEntry, bool) {
+	return m.componentGroup.
+		GetComponent(m.entriesListComponent).(components.ListModel[gh.ContentEntry]).
+		GetSelectedItem()
+}
+
+// refChoices lists the default branch first, then the other branches and the tags.
+func refChoices(refs gh.RepoRefs) []refChoice {
+	choices := []refChoice{{name: refs.DefaultBranch, kind: "branch", isDefault: true}}
+	for _, branch := range refs.Branches {
+		if branch != refs.DefaultBranch {
+			choices = append(choices, refChoice{name: branch, kind: "branch"})
+		}
+	}
+	for _, tag := range refs.Tags {
+		choices = append(choices, refChoice{name: tag, kind: "tag"})
+	}
+	return choices
+}
+
+func renderRefChoice(choice refChoice, width int) string {
+	line := choice.name + mutedStyle.Render(" · "+choice.kind)
+	if choice.isDefault {
+		line += mutedStyle.Render(" · default")
+	}
+	return line
+}
+
+// renderEntry renders an entry of a directory with an icon for its type and
+// the size of files.
+func renderEntry(entry gh.ContentEntry, width int) string {
+	switch entry.Type {
+	case "dir":
+		return directoryStyle.Render("▸ " + entry.Name + "/")
+	case "submodule":
+		return "◆ " + entry.Name + mutedStyle.Render(" · submodule")
+	case "symlink":
+		return "↪ " + entry.Name
+	default:
+		return "  " + entry.Name + mutedStyle.Render(" · "+utils.FormatSize(int64(entry.Size)))
+	}
+}
+
+// isBinary reports whether content looks like a binary file: it has NUL
+// bytes near its start or is not valid UTF-8.
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), binarySniffSize)], 0) >= 0 || !utf8.Valid(content)
+}
+
+// describeImage describes an image file with its format and dimensions when
+// they can be read.
+func describeImage(name string, file gh.RepoFile) string {
+	size := utils.FormatSize(int64(file.Size))
+	config, format, err := image.DecodeConfig(bytes.NewReader(file.Content))
+	if err != nil {
+		return fmt.Sprintf("%s is an image of %s.", name, size)
+	}
+	return fmt.Sprintf("%s is a %s image of %d×%d pixels, %s.", name, strings.ToUpper(format), config.Width, config.Height, size)
+}
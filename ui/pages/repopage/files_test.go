@@ -0,0 +1,34 @@
+package repopage
+
+import (
+	"testing"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+)
+
+func TestSplitRefPath(t *testing.T) {
+	refs := gh.RepoRefs{
+		DefaultBranch: "main",
+		Branches:      []string{"main", "feature", "feature/x"},
+		Tags:          []string{"v1.0", "releases/v2"},
+	}
+	tests := []struct {
+		path     []string
+		wantRef  string
+		wantPath string
+	}{
+		{[]string{"main", "README.md"}, "main", "README.md"},
+		{[]string{"feature", "x", "README.md"}, "feature/x", "README.md"},
+		{[]string{"feature", "docs", "README.md"}, "feature", "docs/README.md"},
+		{[]string{"releases", "v2", "cmd", "main.go"}, "releases/v2", "cmd/main.go"},
+		{[]string{"feature", "x"}, "feature/x", ""},
+		{[]string{"0123abc", "src", "app.go"}, "0123abc", "src/app.go"},
+		{[]string{"main"}, "main", ""},
+	}
+	for _, test := range tests {
+		ref, filePath := splitRefPath(refs, test.path)
+		if ref != test.wantRef || filePath != test.wantPath {
+			t.Errorf("splitRefPath(%q) = %q, %q, want %q, %q", test.path, ref, filePath, test.wantRef, test.wantPath)
+		}
+	}
+}
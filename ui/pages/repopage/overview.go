@@ -0,0 +1,183 @@
+package repopage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+var topicStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("39")).
+	Background(lipgloss.Color("236")).
+	Padding(0, 1).
+	MarginRight(1)
+
+type overviewReadyMsg struct {
+	overview gh.RepoOverview
+	err      error
+}
+
+func (m *RepoPageModel) fetchOverview() tea.Cmd {
+	repo := m.repo
+	return utils.PageCmd(m.id, func() tea.Msg {
+		overview, err := m.repos.GetOverview(context.Background(), repo)
+		return overviewReadyMsg{overview: overview, err: err}
+	})
+}
+
+func (m *RepoPageModel) handleOverviewReady(msg overviewReadyMsg) tea.Cmd {
+	m.overviewErr = msg.err
+	if msg.err == nil {
+		m.overview = &msg.overview
+	}
+	return m.resizeReadme()
+}
+
+// resizeReadme fits the README under the overview.
+func (m *RepoPageModel) resizeReadme() tea.Cmd {
+	return m.componentGroup.Update(m.markdownViewerComponent, utils.UpdateSizeMsg{
+		ID:     m.markdownViewerComponent,
+		Width:  m.width,
+		Height: max(1, m.height-1-lipgloss.Height(m.overviewHeader())),
+	})
+}
+
+// overviewHeader renders the overview shown above the README: the
+// description and topics, counts, default branch and license, latest release
+// and checks, and the languages.
+func (m RepoPageModel) overviewHeader() string {
+	line := lipgloss.NewStyle().MaxWidth(m.width).MaxHeight(1)
+	rule := mutedStyle.Render(strings.Repeat("─", m.width))
+	title := chipStyle.Render(m.repo.String())
+
+	overview := m.overview
+	if overview == nil {
+		status := m.componentGroup.GetComponent(m.spinnerComponent).View() + " Loading the overview…"
+		if m.overviewErr != nil {
+			status = failureStyle.Render("Failed to load the overview: " + m.overviewErr.Error())
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, line.Render(title+status), rule)
+	}
+
+	description := mutedStyle.Render("No description.")
+	if text := strings.Join(strings.Fields(overview.Description), " "); text != "" {
+		description = text
+	}
+	if overview.IsArchived {
+		title += pendingStyle.Render("archived") + " "
+	}
+	if overview.IsFork {
+		title += mutedStyle.Render("fork") + " "
+	}
+	lines := []string{line.Render(title + description)}
+
+	if len(overview.Topics) > 0 {
+		topics := make([]string, len(overview.Topics))
+		for i, topic := range overview.Topics {
+			topics[i] = topicStyle.Render(topic)
+		}
+		lines = append(lines, line.Render(strings.Join(topics, "")))
+	}
+
+	stats := []string{
+		"★ " + utils.FormatCount(overview.Stars) + " stars",
+		"⑂ " + utils.FormatCount(overview.Forks) + " forks",
+		utils.FormatCount(overview.Watchers) + " watching",
+	}
+	if overview.DefaultBranch != "" {
+		stats = append(stats, directoryStyle.Render("⎇ "+overview.DefaultBranch))
+	}
+	if overview.License != "" {
+		stats = append(stats, overview.License)
+	}
+	stats = append(
+		stats,
+		fmt.Sprintf("%d open issues", overview.OpenIssues),
+		fmt.Sprintf("%d open pull requests", overview.OpenPulls),
+	)
+	lines = append(lines, line.Render(strings.Join(stats, mutedStyle.Render(" · "))))
+
+	status := []string{mutedStyle.Render("No releases")}
+	if release := overview.LatestRelease; release != nil {
+		name := release.Name
+		if strings.TrimSpace(name) == "" {
+			name = release.Tag
+		}
+		status = []string{"Latest release " + name + " " + shaStyle.Render(release.Tag) + " " + mutedStyle.Render(utils.Since(release.PublishedAt))}
+	}
+	if overview.HeadSHA != "" {
+		status = append(status, describeChecks(overview.CheckStatus)+mutedStyle.Render(" on "+overview.DefaultBranch+" at ")+shaStyle.Render(shortSHA(overview.HeadSHA)))
+	}
+	lines = append(lines, line.Render(strings.Join(status, mutedStyle.Render(" · "))))
+
+	if overview.LanguagesSize > 0 {
+		lines = append(
+			lines,
+			languagesBar(overview.Languages, overview.LanguagesSize, m.width),
+			line.Render(languagesLegend(overview.Languages, overview.LanguagesSize)),
+		)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, append(lines, rule)...)
+}
+
+// describeChecks describes the combined state of the checks of a commit.
+func describeChecks(state string) string {
+	switch strings.ToUpper(state) {
+	case "SUCCESS":
+		return checkIcon(state) + " checks passing"
+	case "FAILURE", "ERROR":
+		return checkIcon(state) + " checks failing"
+	case "PENDING", "EXPECTED":
+		return checkIcon(state) + " checks running"
+	default:
+		return mutedStyle.Render("No checks")
+	}
+}
+
+// languagesBar renders width cells split between the languages by their
+// size, what is left of total after them being the other languages.
+func languagesBar(languages []gh.Language, total int, width int) string {
+	bar := strings.Builder{}
+	cells, size := 0, 0
+	for _, language := range languages {
+		size += language.Size
+		// Rounding where each language ends keeps the bar exactly width wide.
+		end := int(math.Round(float64(size) * float64(width) / float64(total)))
+		bar.WriteString(languageStyle(language).Render(strings.Repeat("█", end-cells)))
+		cells = end
+	}
+	bar.WriteString(mutedStyle.Render(strings.Repeat("█", max(0, width-cells))))
+	return bar.String()
+}
+
+// languagesLegend renders the name and share of each language.
+func languagesLegend(languages []gh.Language, total int) string {
+	parts := make([]string, 0, len(languages)+1)
+	size := 0
+	for _, language := range languages {
+		size += language.Size
+		parts = append(parts, languageStyle(language).Render("●")+fmt.Sprintf(" %s %.1f%%", language.Name, percentOf(language.Size, total)))
+	}
+	if other := total - size; other > 0 {
+		parts = append(parts, mutedStyle.Render("●")+fmt.Sprintf(" Other %.1f%%", percentOf(other, total)))
+	}
+	return strings.Join(parts, "  ")
+}
+
+func languageStyle(language gh.Language) lipgloss.Style {
+	if language.Color == "" {
+		return mutedStyle
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(language.Color))
+}
+
+func percentOf(size int, total int) float64 {
+	return float64(size) * 100 / float64(total)
+}
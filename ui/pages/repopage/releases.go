@@ -0,0 +1,581 @@
+package repopage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/go-github/v69/github"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+	"github.com/alex-laycalvert/ghtui/ui/components"
+	"github.com/alex-laycalvert/ghtui/utils"
+)
+
+// Width of the bar showing how much of an asset is downloaded.
+const downloadBarWidth = 20
+
+// assetDownload is an asset being downloaded.
+type assetDownload struct {
+	name    string
+	path    string
+	size    int64
+	written int64
+	cancel  context.CancelFunc
+	// Progress, then the result of the download.
+	updates chan tea.Msg
+}
+
+type releasesReadyMsg struct {
+	releases []*github.RepositoryRelease
+	err      error
+}
+
+type releaseNotesGeneratedMsg struct {
+	draft gh.ReleaseDraft
+	name  string
+	notes string
+}
+
+type releaseActionDoneMsg struct {
+	notice string
+}
+
+type releaseActionFailedMsg struct {
+	action string
+	err    error
+}
+
+type downloadProgressMsg struct {
+	written int64
+}
+
+type downloadDoneMsg struct {
+	path string
+	err  error
+}
+
+// openReleases shows the releases, listing them the first time.
+func (m *RepoPageModel) openReleases() tea.Cmd {
+	m.view = releasesView
+	m.release = nil
+	focus := m.componentGroup.FocusOn(m.releasesListComponent)
+	if m.releaseList != nil {
+		return focus
+	}
+	return tea.Batch(focus, m.fetchReleases())
+}
+
+func (m *RepoPageModel) handleReleasesKey(msg tea.KeyMsg) tea.Cmd {
+	if msg.String() == "x" && m.download != nil {
+		m.download.cancel()
+		return nil
+	}
+	if m.release != nil {
+		return m.handleReleaseKey(msg)
+	}
+
+	release, selected := m.componentGroup.
+		GetComponent(m.releasesListComponent).(components.ListModel[*github.RepositoryRelease]).
+		GetSelectedItem()
+	switch msg.String() {
+	case "enter":
+		if !selected {
+			return nil
+		}
+		return m.openRelease(release)
+	case "n":
+		return tea.Sequence(
+			m.componentGroup.Update(m.releaseFormComponent, components.FormResetMsg{
+				Title: "Draft a release",
+				Fields: []components.FormField{
+					{Label: "Tag", Hint: "Created when the release is published if it does not exist"},
+					{Label: "Target", Hint: "Branch or SHA to tag, empty for the default branch"},
+					{Label: "Previous tag", Hint: "Notes cover the changes since it, empty for the last release"},
+					{Label: "Name", Hint: "Empty for the name GitHub generates"},
+					{Label: "Prerelease", Value: "no", Hint: "yes or no"},
+				},
+			}),
+			m.componentGroup.FocusOn(m.releaseFormComponent),
+		)
+	case "P":
+		if !selected {
+			return nil
+		}
+		return m.confirmPublish(release)
+	case "r":
+		return m.fetchReleases()
+	case "esc":
+		m.view = readmeView
+		return m.componentGroup.FocusOn(m.markdownViewerComponent)
+	default:
+		return m.componentGroup.UpdateFocused(msg)
+	}
+}
+
+// handleReleaseKey handles keys while a release's notes and assets are shown.
+func (m *RepoPageModel) handleReleaseKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "a":
+		if m.componentGroup.IsFocused(m.assetsListComponent) {
+			return m.componentGroup.FocusOn(m.releaseNotesViewerComponent)
+		}
+		if len(m.release.Assets) == 0 {
+			m.setNotice("This release has no assets", false)
+			return nil
+		}
+		return m.componentGroup.FocusOn(m.assetsListComponent)
+	case "enter", "d":
+		if !m.componentGroup.IsFocused(m.assetsListComponent) {
+			return m.componentGroup.UpdateFocused(msg)
+		}
+		asset, ok := m.componentGroup.
+			GetComponent(m.assetsListComponent).(components.ListModel[*github.ReleaseAsset]).
+			GetSelectedItem()
+		if !ok {
+			return nil
+		}
+		if m.download != nil {
+			m.setNotice("Wait for "+m.download.name+" to download, or cancel it with x", true)
+			return nil
+		}
+		m.pendingAsset = asset
+		return tea.Sequence(
+			m.componentGroup.Update(m.downloadFormComponent, components.FormResetMsg{
+				Title: "Download " + asset.GetName(),
+				Fields: []components.FormField{
+					{Label: "Directory", Value: defaultDownloadDirectory()},
+				},
+			}),
+			m.componentGroup.FocusOn(m.downloadFormComponent),
+		)
+	case "P":
+		return m.confirmPublish(m.release)
+	case "esc":
+		m.release = nil
+		return m.componentGroup.FocusOn(m.releasesListComponent)
+	default:
+		return m.componentGroup.UpdateFocused(msg)
+	}
+}
+
+// openRelease shows the notes and assets of release.
+func (m *RepoPageModel) openRelease(release *github.RepositoryRelease) tea.Cmd {
+	m.release = release
+	m.recordWebHost(release.GetHTMLURL())
+	return tea.Sequence(
+		m.resizeRelease(),
+		m.componentGroup.Update(m.releaseNotesViewerComponent, components.MarkdownViewerSetContentMsg{
+			Content: renderReleaseNotes(release),
+			BaseURL: release.GetHTMLURL(),
+		}),
+		m.componentGroup.Update(m.assetsListComponent, components.ListSetItemsMsg[*github.ReleaseAsset]{
+			Items: release.Assets,
+		}),
+		m.componentGroup.Update(m.assetsListComponent, components.ListResetViewportMsg{}),
+		m.componentGroup.FocusOn(m.releaseNotesViewerComponent),
+	)
+}
+
+func (m *RepoPageModel) confirmPublish(release *github.RepositoryRelease) tea.Cmd {
+	if !release.GetDraft() {
+		m.setNotice(releaseName(release)+" is already published", true)
+		return nil
+	}
+	m.pendingPublish = release
+	return tea.Sequence(
+		m.componentGroup.Update(m.promptComponent, components.PromptResetMsg{
+			Question: fmt.Sprintf("Publish %s, tagging %s?", releaseName(release), release.GetTagName()),
+			Options:  components.ConfirmOptions,
+		}),
+		m.componentGroup.FocusOn(m.promptComponent),
+	)
+}
+
+// handlePublishAnswer publishes the draft waiting for confirmation.
+func (m *RepoPageModel) handlePublishAnswer(answer string) tea.Cmd {
+	release := m.pendingPublish
+	m.pendingPublish = nil
+	focus := m.componentGroup.FocusOn(m.releasesListComponent)
+	if m.release != nil {
+		focus = m.componentGroup.FocusOn(m.releaseNotesViewerComponent)
+	}
+	if answer != "yes" {
+		return focus
+	}
+	repo, service := m.repo, m.releases
+	return tea.Batch(focus, utils.PageCmd(m.id, func() tea.Msg {
+		if err := service.PublishRelease(context.Background(), repo, release.GetID()); err != nil {
+			return releaseActionFailedMsg{action: "publish " + releaseName(release), err: err}
+		}
+		return releaseActionDoneMsg{notice: "Published " + releaseName(release)}
+	}))
+}
+
+// handleReleaseForm generates the notes of the release drafted with the
+// form, to be edited before the draft is created.
+func (m *RepoPageModel) handleReleaseForm(values []string) tea.Cmd {
+	focus := m.componentGroup.FocusOn(m.releasesListComponent)
+	draft := gh.ReleaseDraft{
+		Tag:    strings.TrimSpace(values[0]),
+		Target: strings.TrimSpace(values[1]),
+		Name:   strings.TrimSpace(values[3]),
+	}
+	previousTag := strings.TrimSpace(values[2])
+	switch strings.ToLower(strings.TrimSpace(values[4])) {
+	case "yes", "y", "true":
+		draft.Prerelease = true
+	case "no", "n", "false", "":
+	default:
+		m.setNotice("Prerelease must be yes or no", true)
+		return focus
+	}
+	if draft.Tag == "" {
+		m.setNotice("The release needs a tag", true)
+		return focus
+	}
+
+	m.setNotice("Generating the notes of "+draft.Tag+"…", false)
+	repo, service := m.repo, m.releases
+	return tea.Batch(focus, utils.PageCmd(m.id, func() tea.Msg {
+		name, notes, err := service.GenerateNotes(context.Background(), repo, draft.Tag, draft.Target, previousTag)
+		if err != nil {
+			return releaseActionFailedMsg{action: "generate the notes of " + draft.Tag, err: err}
+		}
+		return releaseNotesGeneratedMsg{draft: draft, name: name, notes: notes}
+	}))
+}
+
+// handleNotesGenerated opens the generated notes in the editor.
+func (m *RepoPageModel) handleNotesGenerated(msg releaseNotesGeneratedMsg) tea.Cmd {
+	draft := msg.draft
+	if draft.Name == "" {
+		draft.Name = msg.name
+	}
+	m.releaseDraft = &draft
+	m.notice = ""
+	instructions := fmt.Sprintf(
+		"Notes of the draft release %s, generated by GitHub.\nSave and close the editor to create the draft.",
+		draft.Name,
+	)
+	return components.Compose(m.id, components.ComposerTemplate(msg.notes, instructions, ""))
+}
+
+// handleComposedNotes creates the draft release with the notes written.
+func (m *RepoPageModel) handleComposedNotes(msg components.ComposerDoneMsg) tea.Cmd {
+	draft := m.releaseDraft
+	m.releaseDraft = nil
+	if draft == nil {
+		return nil
+	}
+	if msg.Err != nil {
+		m.setNotice("Failed to edit the notes: "+msg.Err.Error(), true)
+		return nil
+	}
+	if msg.Cancelled {
+		m.setNotice("Cancelled, the release was not drafted", false)
+		return nil
+	}
+	draft.Body = msg.Text
+	repo, service := m.repo, m.releases
+	return utils.PageCmd(m.id, func() tea.Msg {
+		if _, err := service.CreateDraft(context.Background(), repo, *draft); err != nil {
+			return releaseActionFailedMsg{action: "draft " + draft.Name, err: err}
+		}
+		return releaseActionDoneMsg{notice: "Drafted " + draft.Name + ", publish it with P"}
+	})
+}
+
+func (m *RepoPageModel) fetchReleases() tea.Cmd {
+	m.releasesLoading = true
+	repo, service := m.repo, m.releases
+	return utils.PageCmd(m.id, func() tea.Msg {
+		releases, err := service.ListReleases(context.Background(), repo)
+		return releasesReadyMsg{releases: releases, err: err}
+	})
+}
+
+func (m *RepoPageModel) handleReleasesReady(msg releasesReadyMsg) tea.Cmd {
+	m.releasesLoading = false
+	if msg.err != nil {
+		m.setNotice("Failed to list the releases: "+msg.err.Error(), true)
+		return nil
+	}
+	m.releaseList = msg.releases
+	cmds := []tea.Cmd{
+		m.componentGroup.Update(m.releasesListComponent, components.ListSetItemsMsg[*github.RepositoryRelease]{
+			Items: msg.releases,
+		}),
+	}
+	// Show the release opened as it is now.
+	if m.release != nil {
+		for _, release := range msg.releases {
+			if release.GetID() == m.release.GetID() {
+				m.release = release
+				cmds = append(cmds, m.componentGroup.Update(m.releaseNotesViewerComponent, components.MarkdownViewerSetContentMsg{
+					Content: renderReleaseNotes(release),
+					BaseURL: release.GetHTMLURL(),
+				}))
+			}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// startDownload downloads the asset waiting for a directory to dir.
+func (m *RepoPageModel) startDownload(dir string) tea.Cmd {
+	asset := m.pendingAsset
+	m.pendingAsset = nil
+	focus := m.componentGroup.FocusOn(m.assetsListComponent)
+	if asset == nil {
+		return focus
+	}
+
+	dir = strings.TrimSpace(dir)
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+		}
+	}
+	if dir == "" {
+		dir = "."
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	download := &assetDownload{
+		name:    asset.GetName(),
+		path:    filepath.Join(dir, asset.GetName()),
+		size:    int64(asset.GetSize()),
+		cancel:  cancel,
+		updates: make(chan tea.Msg, 1),
+	}
+	m.download = download
+
+	repo, service, id := m.repo, m.releases, asset.GetID()
+	go func() {
+		defer cancel()
+		err := downloadAsset(ctx, service, repo, id, download.path, func(written int64) {
+			// Progress is dropped while the last update is still waiting.
+			select {
+			case download.updates <- downloadProgressMsg{written: written}:
+			default:
+			}
+		})
+		download.updates <- downloadDoneMsg{path: download.path, err: err}
+	}()
+	return tea.Batch(focus, waitForDownload(m.id, download.updates))
+}
+
+// waitForDownload waits for the next update of a download of the page with id.
+func waitForDownload(id string, updates chan tea.Msg) tea.Cmd {
+	return utils.PageCmd(id, func() tea.Msg {
+		return <-updates
+	})
+}
+
+// downloadAsset downloads an asset to path through a temporary file next to
+// it, so a failed download leaves nothing behind.
+func downloadAsset(ctx context.Context, service gh.ReleaseService, repo gh.Repo, id int64, path string, progress func(int64)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.part")
+	if err != nil {
+		return err
+	}
+	err = service.DownloadAsset(ctx, repo, id, file, progress)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
+
+// defaultDownloadDirectory returns ~/Downloads if there is one, or the
+// working directory.
+func defaultDownloadDirectory() string {
+	if home, err := os.UserHomeDir(); err == nil {
+		if info, err := os.Stat(filepath.Join(home, "Downloads")); err == nil && info.IsDir() {
+			return "~/Downloads"
+		}
+	}
+	if dir, err := os.Getwd(); err == nil {
+		return dir
+	}
+	return "."
+}
+
+// resizeRelease fits the notes and assets of the release shown in the page.
+func (m *RepoPageModel) resizeRelease() tea.Cmd {
+	assetsHeight := m.assetsHeight()
+	return tea.Batch(
+		m.componentGroup.Update(m.releaseNotesViewerComponent, utils.UpdateSizeMsg{
+			ID:     m.releaseNotesViewerComponent,
+			Width:  m.width,
+			Height: max(1, m.height-2-assetsHeight),
+		}),
+		m.componentGroup.Update(m.assetsListComponent, utils.UpdateSizeMsg{
+			ID:     m.assetsListComponent,
+			Width:  m.width,
+			Height: max(1, assetsHeight-1),
+		}),
+	)
+}
+
+// assetsHeight is the height of the assets of the release shown with their
+// title, at most a third of the page.
+func (m RepoPageModel) assetsHeight() int {
+	if m.release == nil || len(m.release.Assets) == 0 {
+		return 0
+	}
+	return min(len(m.release.Assets)+1, max(2, m.height/3))
+}
+
+// releasesBody renders the releases, or the notes and assets of the one opened.
+func (m RepoPageModel) releasesBody() string {
+	if m.componentGroup.IsFocused(m.releaseFormComponent) {
+		return m.componentGroup.GetComponent(m.releaseFormComponent).View()
+	}
+	if m.componentGroup.IsFocused(m.downloadFormComponent) {
+		return m.componentGroup.GetComponent(m.downloadFormComponent).View()
+	}
+
+	if release := m.release; release != nil {
+		keys := " · a assets · enter download · esc back"
+		if release.GetDraft() {
+			keys += " · P publish"
+		}
+		header := lipgloss.NewStyle().
+			MaxWidth(m.width).
+			MaxHeight(1).
+			Render(lipgloss.JoinHorizontal(
+				lipgloss.Top,
+				chipStyle.Render(release.GetTagName()),
+				releaseName(release),
+				mutedStyle.Render(keys),
+			))
+		parts := []string{header, m.componentGroup.GetComponent(m.releaseNotesViewerComponent).View()}
+		if len(release.Assets) > 0 {
+			parts = append(
+				parts,
+				mutedStyle.Render(fmt.Sprintf("Assets (%d)", len(release.Assets))),
+				m.componentGroup.GetComponent(m.assetsListComponent).View(),
+			)
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, parts...)
+	}
+
+	summary := fmt.Sprintf("%d releases", len(m.releaseList))
+	if m.releasesLoading {
+		summary += " " + m.componentGroup.GetComponent(m.spinnerComponent).View()
+	}
+	header := lipgloss.NewStyle().
+		MaxWidth(m.width).
+		MaxHeight(1).
+		Render(lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			chipStyle.Render("releases"),
+			summary,
+			mutedStyle.Render(" · enter notes and assets · n draft · P publish · r refresh · esc back"),
+		))
+	if len(m.releaseList) == 0 && !m.releasesLoading {
+		return lipgloss.JoinVertical(lipgloss.Left, header, mutedStyle.Render("No releases."))
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		m.componentGroup.GetComponent(m.releasesListComponent).View(),
+	)
+}
+
+// downloadStatus renders the progress of the download under way.
+func (m RepoPageModel) downloadStatus() string {
+	download := m.download
+	filled, progress := 0, utils.FormatSize(download.written)
+	if download.size > 0 {
+		filled = int(min(download.written, download.size) * downloadBarWidth / download.size)
+		progress = fmt.Sprintf(
+			"%d%% · %s of %s",
+			min(download.written, download.size)*100/download.size,
+			utils.FormatSize(download.written),
+			utils.FormatSize(download.size),
+		)
+	}
+	bar := successStyle.Render(strings.Repeat("█", filled)) + mutedStyle.Render(strings.Repeat("░", downloadBarWidth-filled))
+	return lipgloss.NewStyle().
+		MaxWidth(m.width).
+		Render(fmt.Sprintf("Downloading %s %s %s", download.name, bar, progress) + mutedStyle.Render(" · x cancel"))
+}
+
+// renderRelease renders a release as a line of the list: its name, tag,
+// state, date and number of assets.
+func renderRelease(release *github.RepositoryRelease, width int) string {
+	parts := []string{releaseName(release), shaStyle.Render(release.GetTagName())}
+	switch {
+	case release.GetDraft():
+		parts = append(parts, pendingStyle.Render("draft"))
+	case release.GetPrerelease():
+		parts = append(parts, pendingStyle.Render("prerelease"))
+	}
+	date := release.GetPublishedAt()
+	if date.IsZero() {
+		date = release.GetCreatedAt()
+	}
+	parts = append(parts, utils.Since(date.Time))
+	if count := len(release.Assets); count > 0 {
+		parts = append(parts, fmt.Sprintf("%d assets", count))
+	}
+	return strings.Join(parts, mutedStyle.Render(" · "))
+}
+
+// renderAsset renders an asset as a line of the list: its name, size and
+// download count.
+func renderAsset(asset *github.ReleaseAsset, width int) string {
+	return asset.GetName() + mutedStyle.Render(fmt.Sprintf(
+		" · %s · %d downloads",
+		utils.FormatSize(int64(asset.GetSize())),
+		asset.GetDownloadCount(),
+	))
+}
+
+// renderReleaseNotes renders the markdown of a release's page: who released
+// it and when, and its notes.
+func renderReleaseNotes(release *github.RepositoryRelease) string {
+	var doc strings.Builder
+	fmt.Fprintf(&doc, "# %s\n\n", releaseName(release))
+	state := "Released"
+	date := release.GetPublishedAt()
+	switch {
+	case release.GetDraft():
+		state, date = "Drafted", release.GetCreatedAt()
+	case release.GetPrerelease():
+		state = "Prereleased"
+	}
+	fmt.Fprintf(&doc, "*%s by @%s %s, tagging `%s`", state, release.GetAuthor().GetLogin(), utils.Since(date.Time), release.GetTagName())
+	if target := release.GetTargetCommitish(); target != "" {
+		fmt.Fprintf(&doc, " on `%s`", target)
+	}
+	doc.WriteString(".*\n\n")
+	if body := strings.TrimSpace(release.GetBody()); body != "" {
+		doc.WriteString(body + "\n")
+	} else {
+		doc.WriteString("*No release notes.*\n")
+	}
+	return doc.String()
+}
+
+// releaseName returns the name of a release, or its tag if it has none.
+func releaseName(release *github.RepositoryRelease) string {
+	if name := strings.TrimSpace(release.GetName()); name != "" {
+		return name
+	}
+	return release.GetTagName()
+}
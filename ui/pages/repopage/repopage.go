@@ -2,6 +2,7 @@ package repopage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -9,10 +10,41 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/google/go-github/v69/github"
 
+	"github.com/alex-laycalvert/ghtui/gh"
 	"github.com/alex-laycalvert/ghtui/ui/components"
 	"github.com/alex-laycalvert/ghtui/utils"
 )
 
+var (
+	chipStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("230")).
+			Background(lipgloss.Color("62")).
+			Padding(0, 1).
+			MarginRight(1)
+	directoryStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	shaStyle           = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	markedStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	successStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	failureStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	pendingStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	mutedStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	noticeStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	noticeErrorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	previewBorderStyle = lipgloss.NewStyle().
+				Border(lipgloss.NormalBorder(), false, false, false, true).
+				BorderForeground(lipgloss.Color("241"))
+)
+
+type repoView int
+
+const (
+	readmeView repoView = iota
+	filesView
+	commitsView
+	branchesView
+	releasesView
+)
+
 type RepoPageModel struct {
 	id     string
 	width  int
@@ -20,45 +52,190 @@ type RepoPageModel struct {
 
 	isLoaded bool
 	state    utils.ComponentState
-	repo     string
-	client   *github.Client
+	repo     gh.Repo
+	repos    gh.RepoService
+	releases gh.ReleaseService
+	view     repoView
+
+	// The overview shown above the README, nil until it is loaded.
+	overview    *gh.RepoOverview
+	overviewErr error
 
-	componentGroup          utils.ComponentGroup
-	spinnerComponent        string
-	markdownViewerComponent string
+	// The ref files are browsed at, "" for the default branch.
+	ref  string
+	refs *gh.RepoRefs
+	// The directory listed, "" for the root.
+	dir string
+	// Name of the entry to open once the directory is listed, when following a link to it.
+	pendingOpen string
+	// Link to the repository's files to follow once its refs are listed.
+	pendingLink string
+	// Host of the repository's pages on GitHub, to tell links to them from others.
+	webHost       string
+	entriesLoaded bool
+	// Increased with every listing, so only the latest one is shown.
+	directoryRequest int
+	directoryLoading bool
+	preview          *filePreview
+
+	// The file or directory whose history is listed, "" for the whole repository.
+	commitsPath string
+	// The view esc in the commits view goes back to.
+	commitsReturnView repoView
+	commits           []gh.CommitSummary
+	// Increased with every page listed, so only the latest one is shown.
+	commitsRequest int
+	commitsLoading bool
+	// Cursors of the pages before the one shown, of the page shown and of the next one.
+	commitCursors    []string
+	commitCursor     string
+	nextCommitCursor string
+	// Commits marked to compare, the one marked first first.
+	marked     []gh.CommitSummary
+	commitDiff *commitDiff
+
+	branches        *gh.BranchList
+	branchesLoading bool
+	// Whether tags are listed instead of branches.
+	showTags bool
+	// The change to a branch waiting for its form or confirmation.
+	branchTask *branchTask
+
+	releaseList     []*github.RepositoryRelease
+	releasesLoading bool
+	// The release whose notes and assets are shown, nil for the list.
+	release *github.RepositoryRelease
+	// The asset waiting for the directory to download it to.
+	pendingAsset *github.ReleaseAsset
+	download     *assetDownload
+	// The release drafted while its notes are edited.
+	releaseDraft *gh.ReleaseDraft
+	// The draft waiting for confirmation to publish it.
+	pendingPublish *github.RepositoryRelease
+
+	notice        string
+	noticeIsError bool
+
+	componentGroup              utils.ComponentGroup
+	spinnerComponent            string
+	markdownViewerComponent     string
+	errorPanelComponent         string
+	entriesListComponent        string
+	fileViewerComponent         string
+	fileMarkdownViewerComponent string
+	refsListComponent           string
+	refFormComponent            string
+	commitsListComponent        string
+	commitDiffViewerComponent   string
+	pathFormComponent           string
+	branchesListComponent       string
+	tagsListComponent           string
+	branchFormComponent         string
+	promptComponent             string
+	releasesListComponent       string
+	releaseNotesViewerComponent string
+	assetsListComponent         string
+	releaseFormComponent        string
+	downloadFormComponent       string
 }
 
 type repoLoadingMsg struct{}
 
 type repoReadyMsg struct {
-	content string
+	readme gh.Readme
 }
 
-func NewRepoPage(id string, client *github.Client, repo string, width int, height int) RepoPageModel {
+func NewRepoPage(id string, repos gh.RepoService, releases gh.ReleaseService, repo gh.Repo, width int, height int) RepoPageModel {
 	spinner := components.NewSpinnerComponent()
 	markdownViewer := components.NewMarkdownViewerComponent(
 		width,
-		height,
+		height-1,
 		lipgloss.NewStyle(),
 	)
 
-	return RepoPageModel{
-		id:                      id,
-		isLoaded:                false,
-		client:                  client,
-		repo:                    repo,
-		width:                   width,
-		height:                  height,
-		componentGroup:          utils.NewComponentGroup(spinner, markdownViewer),
-		spinnerComponent:        spinner.ID(),
-		markdownViewerComponent: markdownViewer.ID(),
+	errorPanel := components.NewErrorPanelComponent(width)
+
+	m := RepoPageModel{
+		id:       id,
+		isLoaded: false,
+		repos:    repos,
+		releases: releases,
+		repo:     repo,
+		width:    width,
+		height:   height,
 	}
+	entriesList := components.NewListComponent(m.entriesWidth(), height-2, renderEntry)
+	fileViewer := components.NewFileViewerComponent(m.previewWidth(), height-2)
+	fileMarkdownViewer := components.NewMarkdownViewerComponent(m.previewWidth(), height-2, lipgloss.NewStyle())
+	refsList := components.NewListComponent(width, height-2, renderRefChoice)
+	refForm := components.NewFormComponent(width)
+	commitsList := components.NewListComponent(width, height-2, renderCommitRow)
+	commitDiffViewer := components.NewDiffViewerComponent(width, height-2)
+	pathForm := components.NewFormComponent(width)
+	branchesList := components.NewListComponent(width, height-2, renderBranch)
+	tagsList := components.NewListComponent(width, height-2, renderTag)
+	branchForm := components.NewFormComponent(width)
+	prompt := components.NewPromptComponent(width)
+	releasesList := components.NewListComponent(width, height-2, renderRelease)
+	releaseNotesViewer := components.NewMarkdownViewerComponent(width, height-2, lipgloss.NewStyle())
+	assetsList := components.NewListComponent(width, 1, renderAsset)
+	releaseForm := components.NewFormComponent(width)
+	downloadForm := components.NewFormComponent(width)
+
+	m.componentGroup = utils.NewComponentGroup(
+		spinner,
+		markdownViewer,
+		errorPanel,
+		entriesList,
+		fileViewer,
+		fileMarkdownViewer,
+		refsList,
+		refForm,
+		commitsList,
+		commitDiffViewer,
+		pathForm,
+		branchesList,
+		tagsList,
+		branchForm,
+		prompt,
+		releasesList,
+		releaseNotesViewer,
+		assetsList,
+		releaseForm,
+		downloadForm,
+	)
+	m.spinnerComponent = spinner.ID()
+	m.markdownViewerComponent = markdownViewer.ID()
+	m.errorPanelComponent = errorPanel.ID()
+	m.entriesListComponent = entriesList.ID()
+	m.fileViewerComponent = fileViewer.ID()
+	m.fileMarkdownViewerComponent = fileMarkdownViewer.ID()
+	m.refsListComponent = refsList.ID()
+	m.refFormComponent = refForm.ID()
+	m.commitsListComponent = commitsList.ID()
+	m.commitDiffViewerComponent = commitDiffViewer.ID()
+	m.pathFormComponent = pathForm.ID()
+	m.branchesListComponent = branchesList.ID()
+	m.tagsListComponent = tagsList.ID()
+	m.branchFormComponent = branchForm.ID()
+	m.promptComponent = prompt.ID()
+	m.releasesListComponent = releasesList.ID()
+	m.releaseNotesViewerComponent = releaseNotesViewer.ID()
+	m.assetsListComponent = assetsList.ID()
+	m.releaseFormComponent = releaseForm.ID()
+	m.downloadFormComponent = downloadForm.ID()
+	return m
 }
 
 func (m RepoPageModel) ID() string {
 	return m.id
 }
 
+// CapturesInput reports whether the ref form is taking input.
+func (m RepoPageModel) CapturesInput() bool {
+	return m.componentGroup.CapturesInput()
+}
+
 func (m RepoPageModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.fetchRepo(),
@@ -68,6 +245,11 @@ func (m RepoPageModel) Init() tea.Cmd {
 
 func (m RepoPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case utils.PageMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+		return m.Update(msg.Msg)
 	case utils.FocusMsg:
 		if m.id != msg.ID {
 			return m, m.componentGroup.UpdateAll(msg)
@@ -102,15 +284,108 @@ func (m RepoPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.height = msg.Height
 		}
 
-		m.width = msg.Width
-		m.height = msg.Height
-		return m, m.componentGroup.Update(m.markdownViewerComponent, utils.UpdateSizeMsg{
-			ID:     m.markdownViewerComponent,
-			Width:  m.width,
-			Height: m.height,
-		})
+		return m, tea.Batch(
+			m.resizeReadme(),
+			m.componentGroup.Update(m.errorPanelComponent, utils.UpdateSizeMsg{
+				ID:    m.errorPanelComponent,
+				Width: m.width,
+			}),
+			m.componentGroup.Update(m.entriesListComponent, utils.UpdateSizeMsg{
+				ID:     m.entriesListComponent,
+				Width:  m.entriesWidth(),
+				Height: m.height - 2,
+			}),
+			m.componentGroup.Update(m.fileViewerComponent, utils.UpdateSizeMsg{
+				ID:     m.fileViewerComponent,
+				Width:  m.previewWidth(),
+				Height: m.height - 2,
+			}),
+			m.componentGroup.Update(m.fileMarkdownViewerComponent, utils.UpdateSizeMsg{
+				ID:     m.fileMarkdownViewerComponent,
+				Width:  m.previewWidth(),
+				Height: m.height - 2,
+			}),
+			m.componentGroup.Update(m.refsListComponent, utils.UpdateSizeMsg{
+				ID:     m.refsListComponent,
+				Width:  m.width,
+				Height: m.height - 2,
+			}),
+			m.componentGroup.Update(m.refFormComponent, utils.UpdateSizeMsg{
+				ID:    m.refFormComponent,
+				Width: m.width,
+			}),
+			m.componentGroup.Update(m.commitsListComponent, utils.UpdateSizeMsg{
+				ID:     m.commitsListComponent,
+				Width:  m.width,
+				Height: m.height - 2,
+			}),
+			m.resizeCommitDiffViewer(),
+			m.componentGroup.Update(m.pathFormComponent, utils.UpdateSizeMsg{
+				ID:    m.pathFormComponent,
+				Width: m.width,
+			}),
+			m.componentGroup.Update(m.branchesListComponent, utils.UpdateSizeMsg{
+				ID:     m.branchesListComponent,
+				Width:  m.width,
+				Height: m.height - 2,
+			}),
+			m.componentGroup.Update(m.tagsListComponent, utils.UpdateSizeMsg{
+				ID:     m.tagsListComponent,
+				Width:  m.width,
+				Height: m.height - 2,
+			}),
+			m.componentGroup.Update(m.branchFormComponent, utils.UpdateSizeMsg{
+				ID:    m.branchFormComponent,
+				Width: m.width,
+			}),
+			m.componentGroup.Update(m.promptComponent, utils.UpdateSizeMsg{
+				ID:    m.promptComponent,
+				Width: m.width,
+			}),
+			m.componentGroup.Update(m.releasesListComponent, utils.UpdateSizeMsg{
+				ID:     m.releasesListComponent,
+				Width:  m.width,
+				Height: m.height - 2,
+			}),
+			m.resizeRelease(),
+			m.componentGroup.Update(m.releaseFormComponent, utils.UpdateSizeMsg{
+				ID:    m.releaseFormComponent,
+				Width: m.width,
+			}),
+			m.componentGroup.Update(m.downloadFormComponent, utils.UpdateSizeMsg{
+				ID:    m.downloadFormComponent,
+				Width: m.width,
+			}),
+		)
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
+		m.notice = ""
+		// The diff viewer captures input for tab, but esc still closes it.
+		if m.componentGroup.CapturesInput() && !m.componentGroup.IsFocused(m.commitDiffViewerComponent) {
+			return m, m.componentGroup.UpdateFocused(msg)
+		}
+		switch keypress := msg.String(); {
+		case keypress == "r" && m.state == utils.ErrorState:
+			return m, m.fetchRepo()
+		case m.state != utils.ReadyState:
+			return m, nil
+		case m.view == filesView:
+			return m, m.handleFilesKey(msg)
+		case m.view == commitsView:
+			return m, m.handleCommitsKey(msg)
+		case m.view == branchesView:
+			return m, m.handleBranchesKey(msg)
+		case m.view == releasesView:
+			return m, m.handleReleasesKey(msg)
+		case keypress == "f":
+			return m, m.openFiles()
+		case keypress == "c":
+			return m, m.openCommits("")
+		case keypress == "b":
+			return m, m.openBranches()
+		case keypress == "R":
+			return m, m.openReleases()
+		case keypress == "r":
+			return m, m.fetchRepo()
 		default:
 			cmd := m.componentGroup.UpdateFocused(msg)
 			return m, cmd
@@ -118,15 +393,154 @@ func (m RepoPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case repoReadyMsg:
 		m.state = utils.ReadyState
 		m.isLoaded = true
-		return m, tea.Batch(
+		m.recordWebHost(msg.readme.HTMLURL)
+		cmds := []tea.Cmd{
+			m.resizeReadme(),
 			m.componentGroup.Update(m.markdownViewerComponent, components.MarkdownViewerSetContentMsg{
-				Content: msg.content,
+				Content: msg.readme.Markdown,
+				BaseURL: msg.readme.HTMLURL,
 			}),
-			m.componentGroup.FocusOn(m.markdownViewerComponent),
-		)
+		}
+		if m.view == readmeView {
+			cmds = append(cmds, m.componentGroup.FocusOn(m.markdownViewerComponent))
+		}
+		return m, tea.Batch(cmds...)
 	case repoLoadingMsg:
 		m.state = utils.LoadingState
 		return m, m.componentGroup.FocusOn(m.spinnerComponent)
+	case overviewReadyMsg:
+		return m, m.handleOverviewReady(msg)
+	case directoryReadyMsg:
+		return m, m.handleDirectoryReady(msg)
+	case fileReadyMsg:
+		return m, m.handleFileReady(msg)
+	case refsReadyMsg:
+		link := m.pendingLink
+		m.pendingLink = ""
+		if msg.err != nil {
+			m.setNotice("Failed to list the branches and tags: "+msg.err.Error(), true)
+			return m, nil
+		}
+		m.refs = &msg.refs
+		if link != "" {
+			return m, m.followLink(link)
+		}
+		return m, nil
+	case components.MarkdownViewerFollowLinkMsg:
+		if m.markdownViewerComponent != msg.ID &&
+			m.fileMarkdownViewerComponent != msg.ID &&
+			m.releaseNotesViewerComponent != msg.ID {
+			return m, nil
+		}
+		return m, m.followLink(msg.URL)
+	case commitsReadyMsg:
+		return m, m.handleCommitsReady(msg)
+	case commitDiffReadyMsg:
+		return m, m.handleCommitDiffReady(msg)
+	case components.DiffViewerLoadPatchMsg:
+		if m.commitDiffViewerComponent != msg.ID || m.commitDiff == nil {
+			return m, nil
+		}
+		return m, m.fetchCommitPatch(msg.File.GetFilename())
+	case branchesReadyMsg:
+		return m, m.handleBranchesReady(msg)
+	case branchActionDoneMsg:
+		m.setNotice(msg.notice, false)
+		return m, m.fetchBranches()
+	case branchActionFailedMsg:
+		m.setNotice(fmt.Sprintf("Failed to %s: %s", msg.action, msg.err), true)
+		return m, nil
+	case components.PromptAnswerMsg:
+		if m.promptComponent != msg.ID {
+			return m, nil
+		}
+		if m.pendingPublish != nil {
+			return m, m.handlePublishAnswer(msg.Value)
+		}
+		return m, m.handleBranchAnswer(msg.Value)
+	case releasesReadyMsg:
+		return m, m.handleReleasesReady(msg)
+	case releaseNotesGeneratedMsg:
+		return m, m.handleNotesGenerated(msg)
+	case components.ComposerDoneMsg:
+		if m.id != msg.ID {
+			return m, nil
+		}
+		return m, m.handleComposedNotes(msg)
+	case releaseActionDoneMsg:
+		m.setNotice(msg.notice, false)
+		return m, m.fetchReleases()
+	case releaseActionFailedMsg:
+		m.setNotice(fmt.Sprintf("Failed to %s: %s", msg.action, msg.err), true)
+		return m, nil
+	case downloadProgressMsg:
+		if m.download == nil {
+			return m, nil
+		}
+		m.download.written = msg.written
+		return m, waitForDownload(m.id, m.download.updates)
+	case downloadDoneMsg:
+		m.download = nil
+		switch {
+		case errors.Is(msg.err, context.Canceled):
+			m.setNotice("Download canceled", false)
+		case msg.err != nil:
+			m.setNotice("Failed to download: "+msg.err.Error(), true)
+		default:
+			m.setNotice("Downloaded "+msg.path, false)
+		}
+		return m, nil
+	case components.FormSubmitMsg:
+		if m.branchFormComponent == msg.ID {
+			return m, m.handleBranchForm(msg.Values)
+		}
+		if m.releaseFormComponent == msg.ID {
+			return m, m.handleReleaseForm(msg.Values)
+		}
+		if m.downloadFormComponent == msg.ID {
+			return m, m.startDownload(msg.Values[0])
+		}
+		if m.pathFormComponent == msg.ID {
+			m.commitsPath = strings.Trim(strings.TrimSpace(msg.Values[0]), "/")
+			return m, m.switchCommitsRef(m.ref)
+		}
+		if m.refFormComponent != msg.ID {
+			return m, nil
+		}
+		return m, tea.Batch(
+			m.componentGroup.FocusOn(m.entriesListComponent),
+			m.fetchDirectory(msg.Values[0], ""),
+		)
+	case components.FormCancelMsg:
+		if m.branchFormComponent == msg.ID {
+			m.branchTask = nil
+			return m, m.componentGroup.FocusOn(m.branchesListComponent)
+		}
+		if m.releaseFormComponent == msg.ID {
+			return m, m.componentGroup.FocusOn(m.releasesListComponent)
+		}
+		if m.downloadFormComponent == msg.ID {
+			m.pendingAsset = nil
+			return m, m.componentGroup.FocusOn(m.assetsListComponent)
+		}
+		if m.pathFormComponent == msg.ID {
+			return m, m.componentGroup.FocusOn(m.commitsListComponent)
+		}
+		if m.refFormComponent != msg.ID {
+			return m, nil
+		}
+		return m, m.componentGroup.FocusOn(m.entriesListComponent)
+	case utils.ErrorMsg:
+		if m.id != msg.Source {
+			return m, m.componentGroup.UpdateAll(msg)
+		}
+
+		m.state = utils.ErrorState
+		m.isLoaded = false
+		return m, tea.Batch(
+			m.componentGroup.Update(m.errorPanelComponent, components.ErrorPanelSetErrorMsg{Err: msg}),
+			m.componentGroup.FocusOn(m.errorPanelComponent),
+		)
 	default:
 		return m, m.componentGroup.UpdateAll(msg)
 	}
@@ -136,42 +550,106 @@ func (m RepoPageModel) View() string {
 	return lipgloss.NewStyle().
 		Width(m.width).
 		Height(m.height).
-		AlignHorizontal(lipgloss.Center).
-		AlignVertical(lipgloss.Center).
 		Render(m.body())
 }
 
 func (m RepoPageModel) body() string {
 	switch m.state {
 	case utils.LoadingState:
-		return fmt.Sprintf(
-			"%s Loading Repo %s",
-			m.componentGroup.GetComponent(m.spinnerComponent).View(),
-			m.repo,
-		)
-	case utils.ReadyState:
-		return m.componentGroup.GetComponent(m.markdownViewerComponent).View()
-	default:
-		return ""
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(fmt.Sprintf(
+				"%s Loading Repo %s",
+				m.componentGroup.GetComponent(m.spinnerComponent).View(),
+				m.repo,
+			))
+	case utils.ErrorState:
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(m.componentGroup.GetComponent(m.errorPanelComponent).View())
+	}
+
+	var body string
+	footer := mutedStyle.Render("f browse files · c commits · b branches and tags · R releases · ] and [ select links · r refresh")
+	if m.view == filesView {
+		body = m.filesBody()
+		footer = ""
+	} else if m.view == commitsView {
+		body = m.commitsBody()
+		footer = ""
+	} else if m.view == branchesView {
+		body = m.branchesBody()
+		footer = ""
+	} else if m.view == releasesView {
+		body = m.releasesBody()
+		footer = ""
+	} else if m.componentGroup.CapturesInput() {
+		footer = mutedStyle.Render("tab next link · shift+tab previous · enter follow · esc done")
+		body = m.overviewBody()
+	} else {
+		body = m.overviewBody()
+	}
+	if m.notice != "" {
+		style := noticeStyle
+		if m.noticeIsError {
+			style = noticeErrorStyle
+		}
+		footer = style.Width(m.width).MaxHeight(1).Render(m.notice)
+	} else if m.download != nil {
+		footer = m.downloadStatus()
+	}
+	if m.componentGroup.IsFocused(m.promptComponent) {
+		footer = m.componentGroup.GetComponent(m.promptComponent).View()
 	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lipgloss.NewStyle().MaxHeight(max(0, m.height-1)).Render(body),
+		footer,
+	)
 }
 
-func (m *RepoPageModel) fetchRepo() tea.Cmd {
-	return tea.Sequence(
-		repoLoadingCmd,
-		func() tea.Msg {
-			parts := strings.Split(m.repo, "/")
-			owner := parts[0]
-			repoName := parts[1]
-			// TODO: handle err
-			content, _, _ := m.client.Repositories.GetReadme(context.Background(), owner, repoName, nil)
-			markdown, _ := content.GetContent()
-
-			return repoReadyMsg{content: markdown}
-		},
+// overviewBody renders the overview with the README below it.
+func (m RepoPageModel) overviewBody() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.overviewHeader(),
+		m.componentGroup.GetComponent(m.markdownViewerComponent).View(),
 	)
 }
 
-func repoLoadingCmd() tea.Msg {
-	return repoLoadingMsg{}
+// fetchRepo loads the overview, and shows the cached README right away, if
+// there is one, and replaces it once revalidated with GitHub. Without a
+// cached README the loading spinner is shown instead.
+func (m *RepoPageModel) fetchRepo() tea.Cmd {
+	return tea.Batch(m.fetchOverview(), tea.Sequence(
+		utils.PageCmd(m.id, func() tea.Msg {
+			readme, err := m.repos.GetReadme(gh.WithCacheOnly(context.Background()), m.repo)
+			if err != nil || readme.Markdown == "" {
+				return repoLoadingMsg{}
+			}
+			return repoReadyMsg{readme: readme}
+		}),
+		utils.PageCmd(m.id, func() tea.Msg {
+			readme, err := m.repos.GetReadme(context.Background(), m.repo)
+			if err != nil {
+				return utils.NewErrorMsg(m.id, err)
+			}
+			if readme.Markdown == "" {
+				readme.Markdown = "*This repository has no README.*"
+			}
+
+			return repoReadyMsg{readme: readme}
+		}),
+	))
+}
+
+func (m *RepoPageModel) setNotice(notice string, isError bool) {
+	m.notice = notice
+	m.noticeIsError = isError
 }
@@ -0,0 +1,90 @@
+package theme
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A color theme used across the UI.
+type Theme struct {
+	Name string
+
+	// Color of borders, tabs and other chrome.
+	Highlight lipgloss.TerminalColor
+
+	// Name of the glamour style used to render markdown, "auto" picks
+	// dark or light based on the terminal background.
+	MarkdownStyle string
+
+	// Name of the chroma style used to highlight code, "auto" picks dark or
+	// light based on the terminal background.
+	SyntaxStyle string
+}
+
+var themes = map[string]Theme{
+	"auto": {
+		Name:          "auto",
+		Highlight:     lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"},
+		MarkdownStyle: "auto",
+		SyntaxStyle:   "auto",
+	},
+	"dark": {
+		Name:          "dark",
+		Highlight:     lipgloss.Color("#7D56F4"),
+		MarkdownStyle: styles.DarkStyle,
+		SyntaxStyle:   "github-dark",
+	},
+	"light": {
+		Name:          "light",
+		Highlight:     lipgloss.Color("#874BFD"),
+		MarkdownStyle: styles.LightStyle,
+		SyntaxStyle:   "github",
+	},
+	"dracula": {
+		Name:          "dracula",
+		Highlight:     lipgloss.Color("#BD93F9"),
+		MarkdownStyle: styles.DraculaStyle,
+		SyntaxStyle:   "dracula",
+	},
+	"tokyo-night": {
+		Name:          "tokyo-night",
+		Highlight:     lipgloss.Color("#7AA2F7"),
+		MarkdownStyle: styles.TokyoNightStyle,
+		SyntaxStyle:   "tokyonight-night",
+	},
+}
+
+// The theme currently in use, changed with `Set`.
+var Current = themes["auto"]
+
+// Set changes the current theme, it must be called before any component is created.
+func Set(name string) error {
+	theme, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q, expected one of %v", name, Names())
+	}
+	Current = theme
+	return nil
+}
+
+// Names returns the names of all available themes.
+func Names() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MarkdownStyleOption returns the glamour option matching the current theme.
+func MarkdownStyleOption() glamour.TermRendererOption {
+	if Current.MarkdownStyle == "auto" {
+		return glamour.WithAutoStyle()
+	}
+	return glamour.WithStandardStyle(Current.MarkdownStyle)
+}
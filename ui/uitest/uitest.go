@@ -0,0 +1,166 @@
+// Package uitest runs models in a headless program for tests, so the
+// commands they return are executed like in the app.
+package uitest
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// How long `Program.WaitFor` waits by default.
+const DefaultTimeout = 2 * time.Second
+
+// Program runs a model without a terminal.
+type Program struct {
+	t       testing.TB
+	program *tea.Program
+	done    chan struct{}
+
+	mu sync.Mutex
+	// Closed and replaced on every update of the model.
+	updated chan struct{}
+}
+
+// inspectMsg runs fn with the model on the program's event loop, as models
+// share state between copies that must not be read while they update.
+type inspectMsg struct {
+	fn   func(tea.Model)
+	done chan struct{}
+}
+
+// Start runs model until the test ends.
+func Start(t testing.TB, model tea.Model) *Program {
+	t.Helper()
+	p := &Program{t: t, done: make(chan struct{}), updated: make(chan struct{})}
+	p.program = tea.NewProgram(
+		recorder{program: p, model: model},
+		// No input, but a reader rather than nil: the terminal is restored
+		// with one after running the editor.
+		tea.WithInput(strings.NewReader("")),
+		tea.WithOutput(io.Discard),
+		tea.WithoutRenderer(),
+		tea.WithoutSignalHandler(),
+	)
+	go func() {
+		defer close(p.done)
+		_, _ = p.program.Run()
+	}()
+	t.Cleanup(func() {
+		// Killing the program can leave its event loop blocked handing a
+		// command over, quitting lets it stop by itself.
+		p.program.Quit()
+		<-p.done
+	})
+	return p
+}
+
+// Send sends msg to the model.
+func (p *Program) Send(msg tea.Msg) {
+	p.program.Send(msg)
+}
+
+// Type sends a key press for each of keys, e.g. "r", "enter" or "esc".
+func (p *Program) Type(keys ...string) {
+	for _, key := range keys {
+		p.Send(KeyMsg(key))
+	}
+}
+
+// Inspect runs fn with the model as of its last update.
+func (p *Program) Inspect(fn func(tea.Model)) {
+	p.t.Helper()
+	msg := inspectMsg{fn: fn, done: make(chan struct{})}
+	p.Send(msg)
+	select {
+	case <-msg.done:
+	case <-p.done:
+		p.t.Fatal("program stopped")
+	}
+}
+
+// WaitFor waits until ready reports true for the model, failing the test
+// after `DefaultTimeout`.
+func (p *Program) WaitFor(what string, ready func(tea.Model) bool) {
+	p.t.Helper()
+	timeout := time.After(DefaultTimeout)
+	for {
+		p.mu.Lock()
+		updated := p.updated
+		p.mu.Unlock()
+
+		var ok bool
+		p.Inspect(func(model tea.Model) { ok = ready(model) })
+		if ok {
+			return
+		}
+		select {
+		case <-updated:
+		case <-p.done:
+			p.t.Fatalf("program stopped waiting for %s", what)
+		case <-timeout:
+			var view string
+			p.Inspect(func(model tea.Model) { view = model.View() })
+			p.t.Fatalf("timed out waiting for %s, view:\n%s", what, view)
+		}
+	}
+}
+
+// KeyMsg returns the message of pressing key, as named by `tea.KeyMsg.String`.
+func KeyMsg(key string) tea.KeyMsg {
+	for keyType, name := range keyNames {
+		if name == key {
+			return tea.KeyMsg{Type: keyType}
+		}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+var keyNames = map[tea.KeyType]string{
+	tea.KeyEnter:     "enter",
+	tea.KeyEsc:       "esc",
+	tea.KeyTab:       "tab",
+	tea.KeyShiftTab:  "shift+tab",
+	tea.KeyBackspace: "backspace",
+	tea.KeyUp:        "up",
+	tea.KeyDown:      "down",
+	tea.KeyLeft:      "left",
+	tea.KeyRight:     "right",
+	tea.KeySpace:     " ",
+	tea.KeyCtrlS:     "ctrl+s",
+}
+
+// recorder runs the model under test and tells the `Program` about each update.
+type recorder struct {
+	program *Program
+	model   tea.Model
+}
+
+func (r recorder) Init() tea.Cmd {
+	return r.model.Init()
+}
+
+func (r recorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(inspectMsg); ok {
+		msg.fn(r.model)
+		close(msg.done)
+		return r, nil
+	}
+
+	model, cmd := r.model.Update(msg)
+	r.model = model
+
+	r.program.mu.Lock()
+	close(r.program.updated)
+	r.program.updated = make(chan struct{})
+	r.program.mu.Unlock()
+	return r, cmd
+}
+
+func (r recorder) View() string {
+	return r.model.View()
+}
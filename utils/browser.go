@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// OpenInBrowser opens url in the browser given by $BROWSER, or the system's
+// default browser.
+func OpenInBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch {
+	case os.Getenv("BROWSER") != "":
+		cmd = exec.Command(os.Getenv("BROWSER"), url)
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("open", url)
+	case runtime.GOOS == "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the process once the browser has it.
+	go cmd.Wait()
+	return nil
+}
@@ -11,6 +11,18 @@ type Component interface {
 	ID() string
 }
 
+// Implemented by components that, while focused, want every key press,
+// including the ones handled globally such as tab to switch pages.
+type InputCapturer interface {
+	CapturesInput() bool
+}
+
+// CapturesInput reports whether the focused component of the group captures input.
+func (c ComponentGroup) CapturesInput() bool {
+	capturer, ok := c.GetFocusedComponent().(InputCapturer)
+	return ok && capturer.CapturesInput()
+}
+
 // A collection of `Component` instances that can be managed as a group,
 // with the ability to "focus" on a specific component at a time.
 //
@@ -68,7 +80,7 @@ func (c *ComponentGroup) UpdateAll(msg tea.Msg) tea.Cmd {
 }
 
 func (c *ComponentGroup) UpdateFocused(msg tea.Msg) tea.Cmd {
-	if c.focus == -1 {
+	if c.focus == -1 || c.focus >= len(c.components) {
 		return nil
 	}
 
@@ -78,7 +90,7 @@ func (c *ComponentGroup) UpdateFocused(msg tea.Msg) tea.Cmd {
 }
 
 func (c ComponentGroup) GetFocusedComponent() Component {
-	if c.focus == -1 {
+	if c.focus == -1 || c.focus >= len(c.components) {
 		return nil
 	}
 	return c.components[c.focus]
@@ -119,6 +131,27 @@ func (c *ComponentGroup) FocusOn(id string) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+// BlurFocused sends a `utils.BlurMsg` to the focused component, as when the
+// whole group is hidden. The component stays the one focused in the group, and
+// is shown again with `FocusOn`.
+func (c *ComponentGroup) BlurFocused() tea.Cmd {
+	focused := c.GetFocusedComponent()
+	if focused == nil {
+		return nil
+	}
+	return c.Update(focused.ID(), BlurMsg{ID: focused.ID()})
+}
+
+// SuspendFocused sends a `utils.SuspendMsg` to the focused component, as when
+// the whole group is hidden but is to be shown again as it was left.
+func (c *ComponentGroup) SuspendFocused() tea.Cmd {
+	focused := c.GetFocusedComponent()
+	if focused == nil {
+		return nil
+	}
+	return c.Update(focused.ID(), SuspendMsg{ID: focused.ID()})
+}
+
 // FocusNext behaves the same as FocusOn but will focus the next sequential component in the group,
 // based on the order originally provided to `NewComponentGroup`.
 //
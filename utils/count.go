@@ -0,0 +1,17 @@
+package utils
+
+import "fmt"
+
+// FormatCount returns a count shortened the way GitHub shows it, e.g. "1.2k".
+// Counts are rounded to a tenth of the unit first, so 999,950 is "1.0m"
+// rather than "1000.0k".
+func FormatCount(count int) string {
+	if count < 1000 {
+		return fmt.Sprintf("%d", count)
+	}
+	if tenths := (count + 50) / 100; tenths < 10_000 {
+		return fmt.Sprintf("%d.%dk", tenths/10, tenths%10)
+	}
+	tenths := (count + 50_000) / 100_000
+	return fmt.Sprintf("%d.%dm", tenths/10, tenths%10)
+}
@@ -0,0 +1,27 @@
+package utils
+
+import "testing"
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		count int
+		want  string
+	}{
+		{count: 0, want: "0"},
+		{count: 999, want: "999"},
+		{count: 1000, want: "1.0k"},
+		{count: 1049, want: "1.0k"},
+		{count: 1050, want: "1.1k"},
+		{count: 12_345, want: "12.3k"},
+		{count: 999_949, want: "999.9k"},
+		{count: 999_950, want: "1.0m"},
+		{count: 1_000_000, want: "1.0m"},
+		{count: 1_250_000, want: "1.3m"},
+		{count: 1_234_567_890, want: "1234.6m"},
+	}
+	for _, test := range tests {
+		if got := FormatCount(test.count); got != test.want {
+			t.Errorf("FormatCount(%d) = %q, want %q", test.count, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// ErrorMsg reports that an operation started by the component identified by
+// `Source` failed.
+//
+// Components forward error messages that are not theirs to their children, the
+// same way as `FocusMsg`, so an error reaches its source wherever it is nested.
+type ErrorMsg struct {
+	Source string
+	Err    error
+
+	// Whether trying the same operation again may succeed, e.g. after a network
+	// failure, a server error or a rate limit.
+	Retryable bool
+
+	// HTTP status code of the response that caused the error, 0 if there was none.
+	Status int
+}
+
+// NewErrorMsg wraps err for source, deriving `Status` and `Retryable` from the
+// GitHub API error types.
+func NewErrorMsg(source string, err error) ErrorMsg {
+	msg := ErrorMsg{Source: source, Err: err}
+
+	var errorResponse *github.ErrorResponse
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	var acceptedErr *github.AcceptedError
+	switch {
+	case errors.As(err, &rateLimitErr):
+		msg.Status = statusCode(rateLimitErr.Response)
+		msg.Retryable = true
+	case errors.As(err, &abuseErr):
+		msg.Status = statusCode(abuseErr.Response)
+		msg.Retryable = true
+	case errors.As(err, &acceptedErr):
+		msg.Status = http.StatusAccepted
+		msg.Retryable = true
+	case errors.As(err, &errorResponse):
+		msg.Status = statusCode(errorResponse.Response)
+		msg.Retryable = msg.Status >= 500 || msg.Status == http.StatusTooManyRequests
+	case errors.Is(err, context.Canceled):
+		msg.Retryable = false
+	default:
+		// Network failures and other errors that never got a response.
+		msg.Retryable = true
+	}
+	return msg
+}
+
+func (msg ErrorMsg) Error() string {
+	if msg.Err == nil {
+		return "unknown error"
+	}
+	return msg.Err.Error()
+}
+
+func statusCode(response *http.Response) int {
+	if response == nil {
+		return 0
+	}
+	return response.StatusCode
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FuzzyMatch reports whether the characters of pattern appear in text in
+// order, ignoring case, and scores the match: higher for characters that
+// follow each other or start a word, and for shorter texts.
+func FuzzyMatch(pattern string, text string) (int, bool) {
+	pattern = strings.ToLower(pattern)
+	runes := []rune(strings.ToLower(text))
+	score, position, previous := 0, 0, -2
+	for _, char := range pattern {
+		if unicode.IsSpace(char) {
+			continue
+		}
+		found := false
+		for ; position < len(runes); position++ {
+			if runes[position] != char {
+				continue
+			}
+			score++
+			if position == previous+1 {
+				score += 4
+			}
+			if position == 0 || !unicode.IsLetter(runes[position-1]) && !unicode.IsDigit(runes[position-1]) {
+				score += 3
+			}
+			previous = position
+			position++
+			found = true
+			break
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	return score*100 - len(runes), true
+}
@@ -1,5 +1,11 @@
 package utils
 
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/alex-laycalvert/ghtui/gh"
+)
+
 type FocusMsg struct {
 	ID string
 }
@@ -8,8 +14,46 @@ type BlurMsg struct {
 	ID string
 }
 
+// SuspendMsg is sent to the page shown when the repository it belongs to is
+// hidden. Unlike on `BlurMsg`, the page keeps what it shows, and only stops
+// its polling until it is focused again.
+type SuspendMsg struct {
+	ID string
+}
+
 type UpdateSizeMsg struct {
 	ID     string
 	Width  int
 	Height int
 }
+
+// OpenItemMsg asks the app to show an issue or pull request, of any
+// repository, in the detail view of the page listing them. The app forwards it
+// to that page with `ID` set to the page's.
+type OpenItemMsg struct {
+	ID          string
+	Repo        gh.Repo
+	Number      int
+	Title       string
+	PullRequest bool
+}
+
+// PageMsg is a message of the page with `ID`, which the app delivers to it
+// whichever page and repository are shown when it arrives.
+type PageMsg struct {
+	ID  string
+	Msg tea.Msg
+}
+
+// PageCmd addresses the message of cmd to the page with id, so the results of
+// requests made for a repository never reach the pages of another. cmd must
+// not return a batch or sequence of commands.
+func PageCmd(id string, cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		msg := cmd()
+		if msg == nil {
+			return nil
+		}
+		return PageMsg{ID: id, Msg: msg}
+	}
+}
@@ -0,0 +1,22 @@
+package utils
+
+import "fmt"
+
+// FormatSize returns a size in bytes in a readable unit, e.g. "1.5 MB".
+// Sizes are rounded to a tenth of the unit first, so 1,048,575 bytes is
+// "1.0 MB" rather than "1024.0 KB".
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	size := int64(unit)
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		if tenths := (bytes*10 + size/2) / size; tenths < unit*10 {
+			return fmt.Sprintf("%d.%d %s", tenths/10, tenths%10, suffix)
+		}
+		size *= unit
+	}
+	tenths := (bytes*10 + size/2) / size
+	return fmt.Sprintf("%d.%d TB", tenths/10, tenths%10)
+}
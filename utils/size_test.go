@@ -0,0 +1,26 @@
+package utils
+
+import "testing"
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{bytes: 0, want: "0 B"},
+		{bytes: 1023, want: "1023 B"},
+		{bytes: 1024, want: "1.0 KB"},
+		{bytes: 1536, want: "1.5 KB"},
+		{bytes: 1_048_524, want: "1023.9 KB"},
+		{bytes: 1_048_525, want: "1.0 MB"},
+		{bytes: 1_048_575, want: "1.0 MB"},
+		{bytes: 1_073_741_823, want: "1.0 GB"},
+		{bytes: 1 << 40, want: "1.0 TB"},
+		{bytes: 5 << 40, want: "5.0 TB"},
+	}
+	for _, test := range tests {
+		if got := FormatSize(test.bytes); got != test.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", test.bytes, got, test.want)
+		}
+	}
+}
@@ -5,4 +5,5 @@ type ComponentState int
 const (
 	LoadingState ComponentState = iota
 	ReadyState
+	ErrorState
 )